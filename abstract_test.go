@@ -507,7 +507,8 @@ func TestResolveTypeOnInterfaceYieldsUsefulError(t *testing.T) {
 		},
 		Errors: []gqlerrors.FormattedError{
 			{
-				Message: `Runtime Object type "Human" is not a possible type for "Pet".`,
+				Message:    `Runtime Object type "Human" is not a possible type for "Pet".`,
+				Extensions: map[string]interface{}{"code": gqlerrors.CodeInternalServerError},
 				Locations: []location.SourceLocation{
 					{
 						Line:   2,
@@ -631,7 +632,8 @@ func TestResolveTypeOnUnionYieldsUsefulError(t *testing.T) {
 		},
 		Errors: []gqlerrors.FormattedError{
 			{
-				Message: `Runtime Object type "Human" is not a possible type for "Pet".`,
+				Message:    `Runtime Object type "Human" is not a possible type for "Pet".`,
+				Extensions: map[string]interface{}{"code": gqlerrors.CodeInternalServerError},
 				Locations: []location.SourceLocation{
 					{
 						Line:   2,
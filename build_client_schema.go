@@ -0,0 +1,457 @@
+package graphql
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/graphql-go/graphql/language/parser"
+)
+
+// BuildClientSchema builds a schema from the result of an introspection query,
+// such as testutil.IntrospectionQuery. The introspection result is expected to
+// contain the "__schema" key, e.g. the Data of an introspection Result or the
+// "data" object of its JSON representation.
+//
+// The returned schema cannot be used to execute queries, since the
+// introspection result carries no resolvers; it is meant to be used by tools
+// that validate documents against a remote service with ValidateDocument.
+func BuildClientSchema(introspection map[string]interface{}) (*Schema, error) {
+	schemaIntrospection, ok := introspection["__schema"].(map[string]interface{})
+	if !ok {
+		return nil, errors.New("Invalid or incomplete introspection result. Ensure that you are passing the \"data\" property of the introspection response and no \"errors\" were returned alongside.")
+	}
+
+	stdTypeMap := map[string]Type{}
+	for _, ttype := range append(GetIntrospectionTypes(), getSpecifiedScalarTypes()...) {
+		stdTypeMap[ttype.Name()] = ttype
+	}
+
+	builder := clientSchemaBuilder{
+		typeDefs:   map[string]map[string]interface{}{},
+		typeMap:    map[string]Type{},
+		stdTypeMap: stdTypeMap,
+	}
+
+	config, err := builder.buildSchemaConfig(schemaIntrospection)
+	if err != nil {
+		return nil, err
+	}
+
+	schema, err := newSchemaFromThunks(*config)
+	if err != nil {
+		return nil, err
+	}
+	return &schema, nil
+}
+
+type clientSchemaBuilder struct {
+	typeDefs   map[string]map[string]interface{}
+	typeMap    map[string]Type
+	stdTypeMap map[string]Type
+}
+
+func (c *clientSchemaBuilder) buildSchemaConfig(schemaIntrospection map[string]interface{}) (*SchemaConfig, error) {
+	typeIntrospections, ok := schemaIntrospection["types"].([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("Invalid or incomplete schema, unknown types: %v.", schemaIntrospection["types"])
+	}
+
+	// Unions take their possible types as a list of already built objects,
+	// so they are built once all the other types are known.
+	typeNames := []string{}
+	unionNames := []string{}
+	for _, typeIntrospection := range typeIntrospections {
+		typeDef, ok := typeIntrospection.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("Invalid or incomplete schema, unknown type: %v.", typeIntrospection)
+		}
+		name, _ := typeDef["name"].(string)
+		if name == "" {
+			return nil, fmt.Errorf("Invalid or incomplete schema, unnamed type: %v.", typeIntrospection)
+		}
+		c.typeDefs[name] = typeDef
+		if typeDef["kind"] == TypeKindUnion {
+			unionNames = append(unionNames, name)
+		} else {
+			typeNames = append(typeNames, name)
+		}
+	}
+
+	for _, name := range append(typeNames, unionNames...) {
+		if stdType, ok := c.stdTypeMap[name]; ok {
+			c.typeMap[name] = stdType
+			continue
+		}
+		ttype, err := c.buildType(c.typeDefs[name])
+		if err != nil {
+			return nil, err
+		}
+		c.typeMap[name] = ttype
+	}
+
+	schemaConfig := SchemaConfig{
+		Types: []Type{},
+	}
+	for _, name := range append(typeNames, unionNames...) {
+		schemaConfig.Types = append(schemaConfig.Types, c.typeMap[name])
+	}
+
+	var err error
+	if schemaConfig.Query, err = c.getRootType(schemaIntrospection["queryType"]); err != nil {
+		return nil, err
+	}
+	if schemaConfig.Mutation, err = c.getRootType(schemaIntrospection["mutationType"]); err != nil {
+		return nil, err
+	}
+	if schemaConfig.Subscription, err = c.getRootType(schemaIntrospection["subscriptionType"]); err != nil {
+		return nil, err
+	}
+
+	// Older introspection results may not include directives, in which case
+	// the specified directives are assumed.
+	directiveIntrospections, ok := schemaIntrospection["directives"].([]interface{})
+	if !ok {
+		schemaConfig.Directives = specifiedDirectivesWithSpecifiedBy()
+		return &schemaConfig, nil
+	}
+	schemaConfig.Directives = []*Directive{}
+	for _, directiveIntrospection := range directiveIntrospections {
+		directiveDef, ok := directiveIntrospection.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("Invalid or incomplete schema, unknown directive: %v.", directiveIntrospection)
+		}
+		directive, err := c.buildDirective(directiveDef)
+		if err != nil {
+			return nil, err
+		}
+		schemaConfig.Directives = append(schemaConfig.Directives, directive)
+	}
+
+	return &schemaConfig, nil
+}
+
+func (c *clientSchemaBuilder) getRootType(typeRef interface{}) (*Object, error) {
+	if typeRef == nil {
+		return nil, nil
+	}
+	ttype, err := c.getType(typeRef)
+	if err != nil {
+		return nil, err
+	}
+	objectType, ok := ttype.(*Object)
+	if !ok {
+		return nil, fmt.Errorf("Root type %v must be an Object type.", ttype)
+	}
+	return objectType, nil
+}
+
+func (c *clientSchemaBuilder) buildType(typeDef map[string]interface{}) (Type, error) {
+	name, _ := typeDef["name"].(string)
+	description, _ := typeDef["description"].(string)
+
+	switch typeDef["kind"] {
+	case TypeKindScalar:
+		specifiedByURL, _ := typeDef["specifiedByURL"].(string)
+		return NewScalar(ScalarConfig{
+			Name:           name,
+			Description:    description,
+			SpecifiedByURL: specifiedByURL,
+			// Custom scalars need to be defined but we're not using them (because we're not executing against this schema)
+			Serialize: func(value interface{}) interface{} {
+				return value
+			},
+		}), nil
+
+	case TypeKindObject:
+		return NewObject(ObjectConfig{
+			Name:        name,
+			Description: description,
+			Interfaces:  c.buildInterfacesThunk(typeDef),
+			Fields:      c.buildFieldsThunk(typeDef),
+		}), nil
+
+	case TypeKindInterface:
+		return NewInterface(InterfaceConfig{
+			Name:        name,
+			Description: description,
+			Interfaces:  c.buildInterfacesThunk(typeDef),
+			Fields:      c.buildFieldsThunk(typeDef),
+		}), nil
+
+	case TypeKindUnion:
+		possibleTypes, ok := typeDef["possibleTypes"].([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("Introspection result missing possibleTypes: %v.", name)
+		}
+		types := []*Object{}
+		for _, typeRef := range possibleTypes {
+			ttype, err := c.getType(typeRef)
+			if err != nil {
+				return nil, err
+			}
+			objectType, ok := ttype.(*Object)
+			if !ok {
+				return nil, fmt.Errorf("Union type \"%s\" is not an Object", ttype)
+			}
+			types = append(types, objectType)
+		}
+		return NewUnion(UnionConfig{
+			Name:        name,
+			Description: description,
+			Types:       types,
+			// ResolveType needs to be defined but we're not using it (because we're not executing against this schema)
+			ResolveType: func(p ResolveTypeParams) *Object {
+				return nil
+			},
+		}), nil
+
+	case TypeKindEnum:
+		enumValues, ok := typeDef["enumValues"].([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("Introspection result missing enumValues: %v.", name)
+		}
+		values := EnumValueConfigMap{}
+		for _, enumValue := range enumValues {
+			valueDef, ok := enumValue.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("Invalid enum value in %v: %v.", name, enumValue)
+			}
+			valueName, _ := valueDef["name"].(string)
+			valueDescription, _ := valueDef["description"].(string)
+			values[valueName] = &EnumValueConfig{
+				Value:             valueName,
+				Description:       valueDescription,
+				DeprecationReason: getIntrospectionDeprecationReason(valueDef),
+			}
+		}
+		return NewEnum(EnumConfig{
+			Name:        name,
+			Description: description,
+			Values:      values,
+		}), nil
+
+	case TypeKindInputObject:
+		if _, ok := typeDef["inputFields"].([]interface{}); !ok {
+			return nil, fmt.Errorf("Introspection result missing inputFields: %v.", name)
+		}
+		return NewInputObject(InputObjectConfig{
+			Name:        name,
+			Description: description,
+			Fields:      c.buildInputObjectFieldsThunk(typeDef),
+		}), nil
+	}
+
+	return nil, fmt.Errorf("Invalid or incomplete introspection result. Ensure that a full introspection query is used in order to build a client schema: %v.", typeDef)
+}
+
+func (c *clientSchemaBuilder) buildDirective(directiveDef map[string]interface{}) (*Directive, error) {
+	name, _ := directiveDef["name"].(string)
+	for _, specifiedDirective := range specifiedDirectivesWithSpecifiedBy() {
+		if specifiedDirective.Name == name {
+			return specifiedDirective, nil
+		}
+	}
+
+	locationValues, ok := directiveDef["locations"].([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("Introspection result missing directive locations: %v.", name)
+	}
+	locations := []string{}
+	for _, location := range locationValues {
+		if location, ok := location.(string); ok {
+			locations = append(locations, location)
+		}
+	}
+
+	args, err := c.buildArgumentMap(directiveDef["args"])
+	if err != nil {
+		return nil, err
+	}
+
+	description, _ := directiveDef["description"].(string)
+	directive := NewDirective(DirectiveConfig{
+		Name:        name,
+		Description: description,
+		Locations:   locations,
+		Args:        args,
+	})
+	if directive.err != nil {
+		return nil, directive.err
+	}
+	return directive, nil
+}
+
+func (c *clientSchemaBuilder) buildInterfacesThunk(typeDef map[string]interface{}) InterfacesThunk {
+	return func() []*Interface {
+		interfaces := []*Interface{}
+		typeRefs, _ := typeDef["interfaces"].([]interface{})
+		for _, typeRef := range typeRefs {
+			ttype, err := c.getType(typeRef)
+			if err != nil {
+				// InterfaceThunks do not return errors, so panic here
+				panic(err)
+			}
+			interfaceType, ok := ttype.(*Interface)
+			if !ok {
+				panic(fmt.Errorf("Type %v is not an Interface type.", ttype))
+			}
+			interfaces = append(interfaces, interfaceType)
+		}
+		return interfaces
+	}
+}
+
+func (c *clientSchemaBuilder) buildFieldsThunk(typeDef map[string]interface{}) FieldsThunk {
+	return func() Fields {
+		fieldIntrospections, ok := typeDef["fields"].([]interface{})
+		if !ok {
+			panic(fmt.Errorf("Introspection result missing fields: %v.", typeDef["name"]))
+		}
+		fields := Fields{}
+		for _, fieldIntrospection := range fieldIntrospections {
+			fieldDef, ok := fieldIntrospection.(map[string]interface{})
+			if !ok {
+				panic(fmt.Errorf("Invalid field in %v: %v.", typeDef["name"], fieldIntrospection))
+			}
+			name, _ := fieldDef["name"].(string)
+			ttype, err := c.getType(fieldDef["type"])
+			if err != nil {
+				// FieldThunks do not return errors, so panic here
+				panic(err)
+			}
+			if _, ok := ttype.(Output); !ok {
+				panic(fmt.Errorf("Introspection must provide output type for fields, but received: %v.", ttype))
+			}
+			args, err := c.buildArgumentMap(fieldDef["args"])
+			if err != nil {
+				panic(err)
+			}
+			description, _ := fieldDef["description"].(string)
+			fields[name] = &Field{
+				Name:              name,
+				Type:              ttype.(Output),
+				Description:       description,
+				Args:              args,
+				DeprecationReason: getIntrospectionDeprecationReason(fieldDef),
+			}
+		}
+		return fields
+	}
+}
+
+func (c *clientSchemaBuilder) buildInputObjectFieldsThunk(typeDef map[string]interface{}) InputObjectConfigFieldMapThunk {
+	return func() InputObjectConfigFieldMap {
+		fields := InputObjectConfigFieldMap{}
+		inputFields, _ := typeDef["inputFields"].([]interface{})
+		for _, inputField := range inputFields {
+			name, inputType, description, defaultValue, err := c.buildInputValue(inputField)
+			if err != nil {
+				// InputObjectConfigFieldMapThunks do not return errors, so panic here
+				panic(err)
+			}
+			fields[name] = &InputObjectFieldConfig{
+				Type:         inputType,
+				Description:  description,
+				DefaultValue: defaultValue,
+			}
+		}
+		return fields
+	}
+}
+
+func (c *clientSchemaBuilder) buildArgumentMap(argIntrospections interface{}) (FieldConfigArgument, error) {
+	argMap := FieldConfigArgument{}
+	args, _ := argIntrospections.([]interface{})
+	for _, arg := range args {
+		name, inputType, description, defaultValue, err := c.buildInputValue(arg)
+		if err != nil {
+			return nil, err
+		}
+		argMap[name] = &ArgumentConfig{
+			Type:         inputType,
+			Description:  description,
+			DefaultValue: defaultValue,
+		}
+	}
+	return argMap, nil
+}
+
+// buildInputValue converts an __InputValue introspection into the parts of an
+// argument or input field. The default value is printed as a GraphQL literal
+// in the introspection result and is coerced to the input type here.
+func (c *clientSchemaBuilder) buildInputValue(introspection interface{}) (string, Input, string, interface{}, error) {
+	inputValueDef, ok := introspection.(map[string]interface{})
+	if !ok {
+		return "", nil, "", nil, fmt.Errorf("Invalid input value: %v.", introspection)
+	}
+	name, _ := inputValueDef["name"].(string)
+	description, _ := inputValueDef["description"].(string)
+
+	ttype, err := c.getType(inputValueDef["type"])
+	if err != nil {
+		return "", nil, "", nil, err
+	}
+	inputType, ok := ttype.(Input)
+	if !ok {
+		return "", nil, "", nil, fmt.Errorf("Introspection must provide input type for arguments, but received: %v.", ttype)
+	}
+
+	var defaultValue interface{}
+	if defaultValueSource, ok := inputValueDef["defaultValue"].(string); ok {
+		valueAST, err := parser.ParseValue(parser.ParseParams{
+			Source: defaultValueSource,
+		})
+		if err != nil {
+			return "", nil, "", nil, err
+		}
+		defaultValue = valueFromAST(valueAST, inputType, nil)
+	}
+
+	return name, inputType, description, defaultValue, nil
+}
+
+// getType resolves an introspection type reference, i.e. a {kind, name, ofType}
+// object, to a type built from the introspection result.
+func (c *clientSchemaBuilder) getType(typeRef interface{}) (Type, error) {
+	typeRefDef, ok := typeRef.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("Invalid type reference: %v.", typeRef)
+	}
+
+	switch typeRefDef["kind"] {
+	case TypeKindList:
+		ofType, err := c.getType(typeRefDef["ofType"])
+		if err != nil {
+			return nil, err
+		}
+		return NewList(ofType), nil
+	case TypeKindNonNull:
+		ofType, err := c.getType(typeRefDef["ofType"])
+		if err != nil {
+			return nil, err
+		}
+		return NewNonNull(ofType), nil
+	}
+
+	name, _ := typeRefDef["name"].(string)
+	if ttype, ok := c.stdTypeMap[name]; ok {
+		return ttype, nil
+	}
+	if ttype, ok := c.typeMap[name]; ok {
+		return ttype, nil
+	}
+	if _, ok := c.typeDefs[name]; ok {
+		return nil, fmt.Errorf("Type \"%s\" is referenced before it is built.", name)
+	}
+	return nil, fmt.Errorf("Invalid or incomplete schema, unknown type: %v. Ensure that a full introspection query is used in order to build a client schema.", name)
+}
+
+func getIntrospectionDeprecationReason(def map[string]interface{}) string {
+	if isDeprecated, _ := def["isDeprecated"].(bool); !isDeprecated {
+		return ""
+	}
+	if reason, ok := def["deprecationReason"].(string); ok && reason != "" {
+		return reason
+	}
+	return DefaultDeprecationReason
+}
@@ -0,0 +1,290 @@
+package graphql_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/testutil"
+)
+
+func introspectSchema(t *testing.T, schema graphql.Schema) map[string]interface{} {
+	result := graphql.Do(graphql.Params{
+		Schema:        schema,
+		RequestString: testutil.IntrospectionQuery,
+	})
+	if len(result.Errors) > 0 {
+		t.Fatalf("Unexpected errors: %v", result.Errors)
+	}
+	return result.Data.(map[string]interface{})
+}
+
+// cycleIntrospection builds a schema from the SDL, introspects it and builds a
+// client schema from the introspection result, which is expected to print the
+// same as the original schema.
+func cycleIntrospection(t *testing.T, sdl string) {
+	serverSchema, err := graphql.BuildSchema(sdl)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	clientSchema, err := graphql.BuildClientSchema(introspectSchema(t, *serverSchema))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expectPrinted(t, graphql.PrintSchema(*clientSchema), graphql.PrintSchema(*serverSchema))
+}
+
+func TestBuildClientSchema_BuildsSimpleSchema(t *testing.T) {
+	cycleIntrospection(t, `"""Simple schema"""
+type Simple {
+  """This is a string field"""
+  string: String
+}
+
+type Query {
+  simple: Simple
+}
+`)
+}
+
+func TestBuildClientSchema_BuildsSchemaWithCustomRootTypes(t *testing.T) {
+	cycleIntrospection(t, `schema {
+  query: QueryRoot
+  mutation: MutationRoot
+}
+
+type MutationRoot {
+  setString(value: String!): String
+}
+
+type QueryRoot {
+  string: String
+}
+`)
+}
+
+func TestBuildClientSchema_BuildsAbstractTypes(t *testing.T) {
+	cycleIntrospection(t, `type Cat implements Pet {
+  meows: Boolean
+  name: String
+}
+
+union CatOrDog = Cat | Dog
+
+type Dog implements Pet {
+  barks: Boolean
+  name: String
+}
+
+interface Pet {
+  name: String
+}
+
+type Query {
+  pet: Pet
+  pets: [CatOrDog!]!
+}
+`)
+}
+
+func TestBuildClientSchema_BuildsEnumsAndDeprecations(t *testing.T) {
+	cycleIntrospection(t, `enum Color {
+  """So blue"""
+  BLUE @deprecated(reason: "No longer blue")
+  GREEN @deprecated
+  RED
+}
+
+type Query {
+  color(favorite: Color = GREEN): Color
+  colors: [Color] @deprecated(reason: "Use color")
+  oldColor: Color @deprecated
+}
+`)
+}
+
+func TestBuildClientSchema_BuildsInputObjectsWithDefaults(t *testing.T) {
+	cycleIntrospection(t, `enum Color {
+  BLUE
+  RED
+}
+
+input Filter {
+  color: Color = RED
+  first: Int = 10
+  tags: [String] = ["a", "b"]
+}
+
+type Query {
+  things(filter: Filter = {color: BLUE, first: 5}, ratio: Float = 1.5): [String]
+}
+`)
+}
+
+func TestBuildClientSchema_BuildsCustomScalarsAndDirectives(t *testing.T) {
+	cycleIntrospection(t, `"""Checks the role of the viewer"""
+directive @auth(role: String = "admin") on FIELD_DEFINITION | OBJECT
+
+scalar Date
+
+type Query {
+  today: Date
+}
+`)
+}
+
+func TestBuildClientSchema_KeepsTheSpecifiedByURLsOfScalars(t *testing.T) {
+	sdl := `scalar Date @specifiedBy(url: "https://tools.ietf.org/html/rfc3339")
+
+type Query {
+  today: Date
+}
+`
+	serverSchema, err := graphql.BuildSchema(sdl)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	clientSchema, err := graphql.BuildClientSchema(introspectSchema(t, *serverSchema))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expectPrinted(t, graphql.PrintSchema(*clientSchema), sdl)
+}
+
+func TestBuildClientSchema_BuildsSchemaFromIntrospectionJSON(t *testing.T) {
+	serverSchema, err := graphql.BuildSchema(`
+		type Query {
+			hello(name: String = "world"): String
+		}
+	`)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	b, err := json.Marshal(introspectSchema(t, *serverSchema))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var introspection map[string]interface{}
+	if err := json.Unmarshal(b, &introspection); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	clientSchema, err := graphql.BuildClientSchema(introspection)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expectPrinted(t, graphql.PrintSchema(*clientSchema), `type Query {
+  hello(name: String = "world"): String
+}
+`)
+}
+
+func TestBuildClientSchema_ValidatesDocumentsAgainstClientSchema(t *testing.T) {
+	serverSchema, err := graphql.BuildSchema(`
+		interface Pet {
+			name: String
+		}
+		type Dog implements Pet {
+			name: String
+			barks: Boolean
+		}
+		enum Size {
+			SMALL
+			LARGE
+		}
+		type Query {
+			pet(size: Size): Pet
+		}
+	`)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	clientSchema, err := graphql.BuildClientSchema(introspectSchema(t, *serverSchema))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	validate := func(query string) []string {
+		doc, err := parser.Parse(parser.ParseParams{Source: query})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		messages := []string{}
+		for _, err := range graphql.ValidateDocument(clientSchema, doc, nil).Errors {
+			messages = append(messages, err.Message)
+		}
+		return messages
+	}
+
+	if messages := validate(`{ pet(size: SMALL) { name ... on Dog { barks } } }`); len(messages) != 0 {
+		t.Fatalf("Unexpected validation errors: %v", messages)
+	}
+	expected := []string{
+		`Argument "size" has invalid value MEDIUM.` + "\nExpected type \"Size\", found MEDIUM.",
+		`Cannot query field "meows" on type "Pet".`,
+	}
+	if messages := validate(`{ pet(size: MEDIUM) { meows } }`); !reflect.DeepEqual(messages, expected) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, messages))
+	}
+}
+
+func TestBuildClientSchema_RejectsInvalidIntrospection(t *testing.T) {
+	if _, err := graphql.BuildClientSchema(map[string]interface{}{}); err == nil {
+		t.Fatalf("Expected error for missing __schema")
+	}
+
+	introspection := map[string]interface{}{
+		"__schema": map[string]interface{}{
+			"queryType": map[string]interface{}{"name": "Query"},
+			"types": []interface{}{
+				map[string]interface{}{
+					"kind": "OBJECT",
+					"name": "Query",
+					"fields": []interface{}{
+						map[string]interface{}{
+							"name": "unknown",
+							"args": []interface{}{},
+							"type": map[string]interface{}{"kind": "OBJECT", "name": "Unknown"},
+						},
+					},
+					"interfaces": []interface{}{},
+				},
+			},
+		},
+	}
+	_, err := graphql.BuildClientSchema(introspection)
+	if err == nil {
+		t.Fatalf("Expected error for unknown type")
+	}
+	expected := "Invalid or incomplete schema, unknown type: Unknown. Ensure that a full introspection query is used in order to build a client schema."
+	if err.Error() != expected {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, err.Error()))
+	}
+}
+
+func TestBuildClientSchema_DoesNotAlterSpecifiedDirectives(t *testing.T) {
+	// with spare capacity, appending to SpecifiedDirectives writes to the
+	// array shared with the other schemas
+	specifiedDirectives := graphql.SpecifiedDirectives
+	defer func() { graphql.SpecifiedDirectives = specifiedDirectives }()
+	graphql.SpecifiedDirectives = append(make([]*graphql.Directive, 0, len(specifiedDirectives)+1), specifiedDirectives...)
+	spare := graphql.SpecifiedDirectives[:len(specifiedDirectives)+1]
+
+	schema, err := graphql.BuildSchema(`type Query { a: String }`)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	introspection := introspectSchema(t, *schema)
+	if _, err := graphql.BuildClientSchema(introspection); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	// older introspection results have no directives
+	delete(introspection["__schema"].(map[string]interface{}), "directives")
+	if _, err := graphql.BuildClientSchema(introspection); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if spare[len(specifiedDirectives)] != nil {
+		t.Fatalf("Unexpected directive appended to SpecifiedDirectives: %v", spare[len(specifiedDirectives)].Name)
+	}
+}
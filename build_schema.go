@@ -38,11 +38,7 @@ func BuildAstSchema(documentNode *ast.Document) (*Schema, error) {
 		stdTypeMap[ttype.Name()] = ttype
 	}
 
-	builder := SchemaConfigBuilder{
-		typeExtensionsMap: make(map[string][]*ast.TypeExtensionDefinition),
-		typeMap:           make(map[string]Type),
-		stdTypeMap:        stdTypeMap,
-	}
+	builder := newSchemaConfigBuilder(stdTypeMap)
 
 	config, err := builder.buildSchemaConfig(documentNode)
 
@@ -59,6 +55,22 @@ func BuildAstSchema(documentNode *ast.Document) (*Schema, error) {
 	return &schema, nil
 }
 
+// newSchemaFromThunks creates a schema from a config whose field, interface
+// and input field thunks panic when they reference unknown types, since
+// thunks cannot return errors. Such panics are returned as errors.
+func newSchemaFromThunks(config SchemaConfig) (schema Schema, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			if rErr, ok := r.(error); ok {
+				err = rErr
+				return
+			}
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+	return NewSchema(config)
+}
+
 // graphql-go doesn't have an interface for type definitions with names, even though many
 // type definitions support a GetName() function. We need to be able to call GetName(),
 // so this custom interface allows us to cast interfaces to such a generic type.
@@ -69,9 +81,55 @@ type NamedTypeDefinition interface {
 }
 
 type SchemaConfigBuilder struct {
-	typeExtensionsMap map[string][]*ast.TypeExtensionDefinition
-	typeMap           map[string]Type
-	stdTypeMap        map[string]Type
+	typeExtensionsMap        map[string][]*ast.TypeExtensionDefinition
+	interfaceExtensionsMap   map[string][]*ast.InterfaceExtensionDefinition
+	unionExtensionsMap       map[string][]*ast.UnionExtensionDefinition
+	enumExtensionsMap        map[string][]*ast.EnumExtensionDefinition
+	inputObjectExtensionsMap map[string][]*ast.InputObjectExtensionDefinition
+	typeMap                  map[string]Type
+	stdTypeMap               map[string]Type
+	// resolvers is only set when building an executable schema
+	resolvers *ResolverMap
+}
+
+func newSchemaConfigBuilder(stdTypeMap map[string]Type) SchemaConfigBuilder {
+	return SchemaConfigBuilder{
+		typeExtensionsMap:        make(map[string][]*ast.TypeExtensionDefinition),
+		interfaceExtensionsMap:   make(map[string][]*ast.InterfaceExtensionDefinition),
+		unionExtensionsMap:       make(map[string][]*ast.UnionExtensionDefinition),
+		enumExtensionsMap:        make(map[string][]*ast.EnumExtensionDefinition),
+		inputObjectExtensionsMap: make(map[string][]*ast.InputObjectExtensionDefinition),
+		typeMap:                  make(map[string]Type),
+		stdTypeMap:               stdTypeMap,
+	}
+}
+
+// addTypeExtension records the given extension node so that it is applied
+// when the extended type is built.
+func (c *SchemaConfigBuilder) addTypeExtension(node ast.Node) {
+	switch node := node.(type) {
+	case *ast.TypeExtensionDefinition:
+		name := node.Definition.Name.Value
+		c.typeExtensionsMap[name] = append(c.typeExtensionsMap[name], node)
+	case *ast.InterfaceExtensionDefinition:
+		name := node.Definition.Name.Value
+		c.interfaceExtensionsMap[name] = append(c.interfaceExtensionsMap[name], node)
+	case *ast.UnionExtensionDefinition:
+		name := node.Definition.Name.Value
+		c.unionExtensionsMap[name] = append(c.unionExtensionsMap[name], node)
+	case *ast.EnumExtensionDefinition:
+		name := node.Definition.Name.Value
+		c.enumExtensionsMap[name] = append(c.enumExtensionsMap[name], node)
+	case *ast.InputObjectExtensionDefinition:
+		name := node.Definition.Name.Value
+		c.inputObjectExtensionsMap[name] = append(c.inputObjectExtensionsMap[name], node)
+	}
+}
+
+func (c *SchemaConfigBuilder) hasTypeExtensions() bool {
+	return len(c.typeExtensionsMap) > 0 || len(c.interfaceExtensionsMap) > 0 ||
+		len(c.unionExtensionsMap) > 0 || len(c.enumExtensionsMap) > 0 ||
+		len(c.inputObjectExtensionsMap) > 0
 }
 
 // Convert a graphql-go *ast.Document of a schema to a graphql-go *graphql.SchemaConfig
@@ -88,13 +146,9 @@ func (c *SchemaConfigBuilder) buildSchemaConfig(documentAST *ast.Document) (*Sch
 		switch node := def.(type) {
 		case *ast.SchemaDefinition:
 			schemaDef = node
-		case *ast.TypeExtensionDefinition:
-			extendedTypeName := node.Definition.Name.Value
-			if v, ok := c.typeExtensionsMap[extendedTypeName]; ok {
-				c.typeExtensionsMap[extendedTypeName] = append(v, node)
-			} else {
-				c.typeExtensionsMap[extendedTypeName] = []*ast.TypeExtensionDefinition{node}
-			}
+		case *ast.TypeExtensionDefinition, *ast.InterfaceExtensionDefinition, *ast.UnionExtensionDefinition,
+			*ast.EnumExtensionDefinition, *ast.InputObjectExtensionDefinition:
+			c.addTypeExtension(node)
 		case *ast.DirectiveDefinition:
 			directiveDefs = append(directiveDefs, node)
 		case *ast.ObjectDefinition, *ast.InterfaceDefinition, *ast.EnumDefinition, *ast.ScalarDefinition, *ast.InputObjectDefinition:
@@ -105,7 +159,7 @@ func (c *SchemaConfigBuilder) buildSchemaConfig(documentAST *ast.Document) (*Sch
 		}
 	}
 
-	if len(typeDefs) == 0 && len(c.typeExtensionsMap) == 0 && len(directiveDefs) == 0 && schemaDef == nil {
+	if len(typeDefs) == 0 && !c.hasTypeExtensions() && len(directiveDefs) == 0 && schemaDef == nil {
 		return &schemaConfig, nil
 	}
 
@@ -198,7 +252,7 @@ func (c *SchemaConfigBuilder) buildSchemaConfig(documentAST *ast.Document) (*Sch
 
 	// If specified directives were not explicitly declared, also add them to the schema
 	// See TODO (ECO-3255) for why we are also including the "specifiedBy" directive
-	specifiedDirectives := append(SpecifiedDirectives, SpecifiedByDirective)
+	specifiedDirectives := specifiedDirectivesWithSpecifiedBy()
 	for _, specifiedDirective := range specifiedDirectives {
 		hasDiretive := false
 		for _, declaredDirective := range schemaConfig.Directives {
@@ -230,12 +284,16 @@ func (c *SchemaConfigBuilder) buildType(astNode interface{}) (interface{}, error
 			return nil, err
 		}
 
-		return NewObject(ObjectConfig{
+		objectConfig := ObjectConfig{
 			Name:        node.Name.Value,
 			Description: description,
 			Interfaces:  c.buildInterfacesThunk(node),
 			Fields:      fieldsThunk,
-		}), nil
+		}
+		if c.resolvers != nil {
+			objectConfig.IsTypeOf = c.resolvers.IsTypeOf[node.Name.Value]
+		}
+		return NewObject(objectConfig), nil
 
 	case *ast.InterfaceDefinition:
 		description := ""
@@ -248,11 +306,16 @@ func (c *SchemaConfigBuilder) buildType(astNode interface{}) (interface{}, error
 			return nil, err
 		}
 
-		return NewInterface(InterfaceConfig{
+		interfaceConfig := InterfaceConfig{
 			Name:        node.Name.Value,
 			Description: description,
+			Interfaces:  c.buildInterfacesThunk(node),
 			Fields:      fieldsThunk,
-		}), nil
+		}
+		if c.resolvers != nil {
+			interfaceConfig.ResolveType = c.resolvers.ResolveType[node.Name.Value]
+		}
+		return NewInterface(interfaceConfig), nil
 
 	case *ast.EnumDefinition:
 		description := ""
@@ -260,8 +323,13 @@ func (c *SchemaConfigBuilder) buildType(astNode interface{}) (interface{}, error
 			description = node.Description.Value
 		}
 
+		enumValues := append([]*ast.EnumValueDefinition{}, node.Values...)
+		for _, extensionNode := range c.enumExtensionsMap[node.Name.Value] {
+			enumValues = append(enumValues, extensionNode.Definition.Values...)
+		}
+
 		enums := EnumValueConfigMap{}
-		for _, enumValue := range node.Values {
+		for _, enumValue := range enumValues {
 			description := ""
 			if enumValue.Description != nil {
 				description = enumValue.Description.Value
@@ -269,7 +337,7 @@ func (c *SchemaConfigBuilder) buildType(astNode interface{}) (interface{}, error
 
 			enums[enumValue.Name.Value] = &EnumValueConfig{
 				Description:       description,
-				DeprecationReason: getDeprecationReason(node),
+				DeprecationReason: getDeprecationReason(DefinitionWithDirectives{Directives: enumValue.Directives}),
 			}
 		}
 
@@ -285,8 +353,13 @@ func (c *SchemaConfigBuilder) buildType(astNode interface{}) (interface{}, error
 			description = node.Description.Value
 		}
 
+		nodeTypes := append([]*ast.Named{}, node.Types...)
+		for _, extensionNode := range c.unionExtensionsMap[node.Name.Value] {
+			nodeTypes = append(nodeTypes, extensionNode.Definition.Types...)
+		}
+
 		types := []*Object{}
-		for _, nodeType := range node.Types {
+		for _, nodeType := range nodeTypes {
 			nodeTypeaName := nodeType.Name.Value
 			ttype, err := c.getNamedType(nodeTypeaName)
 			if err != nil {
@@ -301,7 +374,7 @@ func (c *SchemaConfigBuilder) buildType(astNode interface{}) (interface{}, error
 			}
 		}
 
-		return NewUnion(UnionConfig{
+		unionConfig := UnionConfig{
 			Name:        node.Name.Value,
 			Description: description,
 			Types:       types,
@@ -309,7 +382,12 @@ func (c *SchemaConfigBuilder) buildType(astNode interface{}) (interface{}, error
 			ResolveType: func(p ResolveTypeParams) *Object {
 				return nil
 			},
-		}), nil
+		}
+		if c.resolvers != nil {
+			// Without a ResolveType function, the IsTypeOf functions of the possible types are used
+			unionConfig.ResolveType = c.resolvers.ResolveType[node.Name.Value]
+		}
+		return NewUnion(unionConfig), nil
 
 	case *ast.ScalarDefinition:
 		description := ""
@@ -317,14 +395,29 @@ func (c *SchemaConfigBuilder) buildType(astNode interface{}) (interface{}, error
 			description = node.Description.Value
 		}
 
-		return NewScalar(ScalarConfig{
-			Name:        node.Name.Value,
-			Description: description,
+		scalarConfig := ScalarConfig{
+			Name:           node.Name.Value,
+			Description:    description,
+			SpecifiedByURL: getSpecifiedByUrl(DefinitionWithDirectives{Directives: node.Directives}),
 			// Custom scalars need to be defined but we're not using them (because we're not executing against this schema)
 			Serialize: func(value interface{}) interface{} {
 				return nil
 			},
-		}), nil
+		}
+		if c.resolvers != nil {
+			// Custom scalars without a ScalarResolver serialize values as is
+			scalarConfig.Serialize = func(value interface{}) interface{} {
+				return value
+			}
+			if scalar, ok := c.resolvers.Scalars[node.Name.Value]; ok && scalar != nil {
+				if scalar.Serialize != nil {
+					scalarConfig.Serialize = scalar.Serialize
+				}
+				scalarConfig.ParseValue = scalar.ParseValue
+				scalarConfig.ParseLiteral = scalar.ParseLiteral
+			}
+		}
+		return NewScalar(scalarConfig), nil
 
 	case *ast.InputObjectDefinition:
 		description := ""
@@ -371,34 +464,36 @@ func (c *SchemaConfigBuilder) buildDirective(directiveDef *ast.DirectiveDefiniti
 	}), nil
 }
 
-func (c *SchemaConfigBuilder) buildInterfacesThunk(node *ast.ObjectDefinition) InterfacesThunk {
+func (c *SchemaConfigBuilder) buildInterfacesThunk(astNode interface{}) InterfacesThunk {
+	namedInterfaces := []*ast.Named{}
+
+	switch node := astNode.(type) {
+	case *ast.ObjectDefinition:
+		// Add interfaces of the object and of its extensions
+		namedInterfaces = append(namedInterfaces, node.Interfaces...)
+		for _, en := range c.typeExtensionsMap[node.Name.Value] {
+			namedInterfaces = append(namedInterfaces, en.Definition.Interfaces...)
+		}
+	case *ast.InterfaceDefinition:
+		// Add interfaces of the interface and of its extensions
+		namedInterfaces = append(namedInterfaces, node.Interfaces...)
+		for _, en := range c.interfaceExtensionsMap[node.Name.Value] {
+			namedInterfaces = append(namedInterfaces, en.Definition.Interfaces...)
+		}
+	}
+
 	return func() []*Interface {
 		interfaces := []*Interface{}
 
-		// Add interfaces of the object
-		for _, i := range node.Interfaces {
+		for _, i := range namedInterfaces {
 			namedInterface, err := c.getNamedType(i.Name.Value)
 			if err != nil {
 				// InterfaceThunks do not return errors, so panic here
 				panic(err)
 			}
 
-			if convertedInterface, ok := namedInterface.(Interface); ok {
-				interfaces = append(interfaces, &convertedInterface)
-			}
-		}
-
-		// Add interfaces of extenions
-		for _, en := range c.typeExtensionsMap[node.Name.Value] {
-			for _, i := range en.Definition.Interfaces {
-				namedInterface, err := c.getNamedType(i.Name.Value)
-				if err != nil {
-					// InterfaceThunks do not return errors, so panic here
-					panic(err)
-				}
-				if convertedInterface, ok := namedInterface.(*Interface); ok {
-					interfaces = append(interfaces, convertedInterface)
-				}
+			if convertedInterface, ok := namedInterface.(*Interface); ok {
+				interfaces = append(interfaces, convertedInterface)
 			}
 		}
 
@@ -414,19 +509,21 @@ func (c *SchemaConfigBuilder) buildFieldsThunk(astNode interface{}) (FieldsThunk
 	case *ast.ObjectDefinition:
 		fieldDefs = append(fieldDefs, node.Fields...)
 		name = node.Name.Value
+		for _, extensionNode := range c.typeExtensionsMap[name] {
+			fieldDefs = append(fieldDefs, extensionNode.Definition.Fields...)
+		}
 	case *ast.InterfaceDefinition:
 		fieldDefs = append(fieldDefs, node.Fields...)
 		name = node.Name.Value
+		for _, extensionNode := range c.interfaceExtensionsMap[name] {
+			fieldDefs = append(fieldDefs, extensionNode.Definition.Fields...)
+		}
 	default:
 		return nil, errors.New("buildFieldsThunk called with unsupported node type")
 	}
 
-	for _, extensionNode := range c.typeExtensionsMap[name] {
-		fieldDefs = append(fieldDefs, extensionNode.Definition.Fields...)
-	}
-
 	return func() Fields {
-		fields, err := c.buildFieldMap(fieldDefs)
+		fields, err := c.buildFieldMap(name, fieldDefs)
 		if err != nil {
 			// FieldThunks do not return errors, so panic here
 			panic(err)
@@ -437,38 +534,53 @@ func (c *SchemaConfigBuilder) buildFieldsThunk(astNode interface{}) (FieldsThunk
 }
 
 func (c *SchemaConfigBuilder) buildInputObjectFieldsThunk(node *ast.InputObjectDefinition) (InputObjectConfigFieldMapThunk, error) {
+	fieldDefs := append([]*ast.InputValueDefinition{}, node.Fields...)
+	for _, extensionNode := range c.inputObjectExtensionsMap[node.Name.Value] {
+		fieldDefs = append(fieldDefs, extensionNode.Definition.Fields...)
+	}
+
 	return func() InputObjectConfigFieldMap {
-		inputFieldMap := InputObjectConfigFieldMap{}
-		for _, field := range node.Fields {
-			fieldType, _ := c.getWrappedType(field.Type)
-			if castedField, ok := fieldType.(Input); ok {
-				description := ""
-				if node.Description != nil {
-					description = node.Description.Value
-				}
+		return c.buildInputFieldMap(fieldDefs)
+	}, nil
+}
 
-				inputFieldMap[field.Name.Value] = &InputObjectFieldConfig{
-					Type:         castedField,
-					DefaultValue: valueFromAST(field.DefaultValue, castedField, nil),
-					Description:  description,
-				}
+func (c *SchemaConfigBuilder) buildInputFieldMap(fieldDefs []*ast.InputValueDefinition) InputObjectConfigFieldMap {
+	inputFieldMap := InputObjectConfigFieldMap{}
+	for _, field := range fieldDefs {
+		fieldType, _ := c.getWrappedType(field.Type)
+		if castedField, ok := fieldType.(Input); ok {
+			description := ""
+			if field.Description != nil {
+				description = field.Description.Value
+			}
+
+			inputFieldMap[field.Name.Value] = &InputObjectFieldConfig{
+				Type:         castedField,
+				DefaultValue: valueFromAST(field.DefaultValue, castedField, nil),
+				Description:  description,
 			}
 		}
-		return inputFieldMap
-	}, nil
+	}
+	return inputFieldMap
 }
 
-func (c *SchemaConfigBuilder) buildFieldMap(fieldDefs []*ast.FieldDefinition) (Fields, error) {
+func (c *SchemaConfigBuilder) buildFieldMap(typeName string, fieldDefs []*ast.FieldDefinition) (Fields, error) {
 	fields := Fields{}
 
 	for _, f := range fieldDefs {
 		field := Field{
 			Name: f.Name.Value,
 		}
+		if c.resolvers != nil {
+			field.Resolve = c.resolvers.Fields[typeName+"."+f.Name.Value]
+			field.Subscribe = c.resolvers.Subscribe[typeName+"."+f.Name.Value]
+		}
 
 		if f.Description != nil {
 			field.Description = f.Description.Value
 		}
+		field.DeprecationReason = getDeprecationReason(DefinitionWithDirectives{Directives: f.Directives})
+		field.Directives = f.Directives
 
 		wrapped, err := c.getWrappedType(f.Type)
 		if err != nil {
@@ -602,9 +714,13 @@ type DefinitionWithDirectives struct {
 func getDeprecationReason(def interface{}) string {
 	if d, ok := def.(DefinitionWithDirectives); ok {
 		deprecated := getDirectiveValues(*DeprecatedDirective, d)
-		if reason, ok := deprecated["reason"]; ok {
-			return reason.(string)
+		if deprecated == nil {
+			return ""
+		}
+		if reason, ok := deprecated["reason"].(string); ok {
+			return reason
 		}
+		return DefaultDeprecationReason
 	}
 
 	return ""
@@ -612,9 +728,9 @@ func getDeprecationReason(def interface{}) string {
 
 func getSpecifiedByUrl(def interface{}) string {
 	if d, ok := def.(DefinitionWithDirectives); ok {
-		deprecated := getDirectiveValues(*SpecifiedByDirective, d)
-		if reason, ok := deprecated["reason"]; ok {
-			return reason.(string)
+		specifiedBy := getDirectiveValues(*SpecifiedByDirective, d)
+		if url, ok := specifiedBy["url"].(string); ok {
+			return url
 		}
 	}
 
@@ -0,0 +1,143 @@
+package graphql
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/graphql-go/graphql/language/parser"
+)
+
+// ResolverMap supplies the functions needed to execute a schema built from
+// SDL with BuildSchemaWithResolvers.
+type ResolverMap struct {
+	// Fields maps "Type.field" to the resolver of that field. Fields without a
+	// resolver use DefaultResolveFn.
+	Fields map[string]FieldResolveFn
+
+	// Subscribe maps "Type.field" to the subscriber of that field, for fields
+	// of the subscription root type.
+	Subscribe map[string]FieldResolveFn
+
+	// IsTypeOf maps object type names to their IsTypeOf function.
+	IsTypeOf map[string]IsTypeOfFn
+
+	// ResolveType maps interface and union type names to their ResolveType
+	// function. Abstract types without one use the IsTypeOf functions of
+	// their possible types.
+	ResolveType map[string]ResolveTypeFn
+
+	// Scalars maps custom scalar type names to their serialization and
+	// parsing functions. Custom scalars without one serialize values as is.
+	Scalars map[string]*ScalarResolver
+}
+
+// ScalarResolver supplies the serialization and parsing functions of a custom
+// scalar defined in SDL. ParseValue and ParseLiteral must both be provided or
+// both be omitted.
+type ScalarResolver struct {
+	Serialize    SerializeFn
+	ParseValue   ParseValueFn
+	ParseLiteral ParseLiteralFn
+}
+
+// BuildSchemaWithResolvers builds an executable schema from the given SDL,
+// using the functions of the given ResolverMap. Building fails if the
+// ResolverMap refers to a type or field which is not defined in the SDL, or
+// to a type of the wrong kind.
+func BuildSchemaWithResolvers(source string, resolvers ResolverMap) (*Schema, error) {
+	astDoc, err := parser.Parse(parser.ParseParams{
+		Source:  source,
+		Options: parser.ParseOptions{},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	stdTypeMap := map[string]Type{}
+	for _, ttype := range append(GetIntrospectionTypes(), getSpecifiedScalarTypes()...) {
+		stdTypeMap[ttype.Name()] = ttype
+	}
+
+	builder := newSchemaConfigBuilder(stdTypeMap)
+	builder.resolvers = &resolvers
+
+	config, err := builder.buildSchemaConfig(astDoc)
+	if err != nil {
+		return nil, err
+	}
+
+	schema, err := newSchemaFromThunks(*config)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkResolverMap(schema, resolvers); err != nil {
+		return nil, err
+	}
+	return &schema, nil
+}
+
+// checkResolverMap verifies that every entry of the ResolverMap refers to a
+// type of the expected kind, or field, of the schema.
+func checkResolverMap(schema Schema, resolvers ResolverMap) error {
+	for _, key := range sortedResolverKeys(resolvers.Fields) {
+		if err := checkFieldResolverKey(schema, key); err != nil {
+			return err
+		}
+	}
+	for _, key := range sortedResolverKeys(resolvers.Subscribe) {
+		if err := checkFieldResolverKey(schema, key); err != nil {
+			return err
+		}
+	}
+	for name := range resolvers.IsTypeOf {
+		if _, ok := schema.Type(name).(*Object); !ok {
+			return fmt.Errorf(`IsTypeOf defined in resolvers for "%v", but "%v" is not an object type in the schema.`, name, name)
+		}
+	}
+	for name := range resolvers.ResolveType {
+		switch schema.Type(name).(type) {
+		case *Interface, *Union:
+		default:
+			return fmt.Errorf(`ResolveType defined in resolvers for "%v", but "%v" is not an interface or union type in the schema.`, name, name)
+		}
+	}
+	for name := range resolvers.Scalars {
+		if _, ok := schema.Type(name).(*Scalar); !ok || isSpecifiedScalarType(schema.Type(name)) {
+			return fmt.Errorf(`Scalar defined in resolvers for "%v", but "%v" is not a custom scalar type in the schema.`, name, name)
+		}
+	}
+	return nil
+}
+
+func checkFieldResolverKey(schema Schema, key string) error {
+	parts := strings.Split(key, ".")
+	if len(parts) != 2 {
+		return fmt.Errorf(`Resolver "%v" must be keyed by "Type.field".`, key)
+	}
+	typeName, fieldName := parts[0], parts[1]
+
+	var fields FieldDefinitionMap
+	switch ttype := schema.Type(typeName).(type) {
+	case *Object:
+		fields = ttype.Fields()
+	case *Interface:
+		fields = ttype.Fields()
+	default:
+		return fmt.Errorf(`Resolver "%v" defined in resolvers, but type "%v" is not defined in the schema.`, key, typeName)
+	}
+	if _, ok := fields[fieldName]; !ok {
+		return fmt.Errorf(`Resolver "%v" defined in resolvers, but "%v" is not a field of type "%v".`, key, fieldName, typeName)
+	}
+	return nil
+}
+
+func sortedResolverKeys(fns map[string]FieldResolveFn) []string {
+	keys := []string{}
+	for key := range fns {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
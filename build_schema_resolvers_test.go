@@ -0,0 +1,241 @@
+package graphql_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/testutil"
+)
+
+type resolverCat struct {
+	Name  string
+	Meows bool
+}
+
+type resolverDog struct {
+	Name  string
+	Barks bool
+}
+
+func TestBuildSchemaWithResolvers_ResolvesFields(t *testing.T) {
+	schema, err := graphql.BuildSchemaWithResolvers(`
+		type Query {
+			hello(name: String = "world"): String
+			static: String
+		}
+	`, graphql.ResolverMap{
+		Fields: map[string]graphql.FieldResolveFn{
+			"Query.hello": func(p graphql.ResolveParams) (interface{}, error) {
+				return "Hello, " + p.Args["name"].(string), nil
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	result := graphql.Do(graphql.Params{
+		Schema:        *schema,
+		RequestString: `{ hello static a: hello(name: "GraphQL") }`,
+		RootObject:    map[string]interface{}{"static": "root value"},
+	})
+	expected := &graphql.Result{
+		Data: map[string]interface{}{
+			"hello":  "Hello, world",
+			"static": "root value",
+			"a":      "Hello, GraphQL",
+		},
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
+	}
+}
+
+func TestBuildSchemaWithResolvers_ResolvesAbstractTypes(t *testing.T) {
+	schema, err := graphql.BuildSchemaWithResolvers(`
+		interface Named {
+			name: String
+		}
+		type Cat implements Named {
+			name: String
+			meows: Boolean
+		}
+		type Dog implements Named {
+			name: String
+			barks: Boolean
+		}
+		union Pet = Cat | Dog
+		type Query {
+			named: [Named]
+			pets: [Pet]
+		}
+	`, graphql.ResolverMap{
+		Fields: map[string]graphql.FieldResolveFn{
+			"Query.named": func(p graphql.ResolveParams) (interface{}, error) {
+				return []interface{}{&resolverCat{"Garfield", false}, &resolverDog{"Odie", true}}, nil
+			},
+			"Query.pets": func(p graphql.ResolveParams) (interface{}, error) {
+				return []interface{}{&resolverDog{"Snoopy", true}, &resolverCat{"Tom", true}}, nil
+			},
+		},
+		ResolveType: map[string]graphql.ResolveTypeFn{
+			"Named": func(p graphql.ResolveTypeParams) *graphql.Object {
+				if _, ok := p.Value.(*resolverCat); ok {
+					return p.Info.Schema.Type("Cat").(*graphql.Object)
+				}
+				return p.Info.Schema.Type("Dog").(*graphql.Object)
+			},
+		},
+		IsTypeOf: map[string]graphql.IsTypeOfFn{
+			"Cat": func(p graphql.IsTypeOfParams) bool {
+				_, ok := p.Value.(*resolverCat)
+				return ok
+			},
+			"Dog": func(p graphql.IsTypeOfParams) bool {
+				_, ok := p.Value.(*resolverDog)
+				return ok
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	result := graphql.Do(graphql.Params{
+		Schema: *schema,
+		RequestString: `{
+			named { __typename name }
+			pets {
+				__typename
+				... on Cat { meows }
+				... on Dog { barks }
+			}
+		}`,
+	})
+	expected := &graphql.Result{
+		Data: map[string]interface{}{
+			"named": []interface{}{
+				map[string]interface{}{"__typename": "Cat", "name": "Garfield"},
+				map[string]interface{}{"__typename": "Dog", "name": "Odie"},
+			},
+			"pets": []interface{}{
+				map[string]interface{}{"__typename": "Dog", "barks": true},
+				map[string]interface{}{"__typename": "Cat", "meows": true},
+			},
+		},
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
+	}
+}
+
+func TestBuildSchemaWithResolvers_CustomScalars(t *testing.T) {
+	schema, err := graphql.BuildSchemaWithResolvers(`
+		scalar Upper
+		scalar Opaque
+		type Query {
+			echo(value: Upper): Upper
+			opaque: Opaque
+		}
+	`, graphql.ResolverMap{
+		Fields: map[string]graphql.FieldResolveFn{
+			"Query.echo": func(p graphql.ResolveParams) (interface{}, error) {
+				return p.Args["value"], nil
+			},
+			"Query.opaque": func(p graphql.ResolveParams) (interface{}, error) {
+				return 42, nil
+			},
+		},
+		Scalars: map[string]*graphql.ScalarResolver{
+			"Upper": {
+				Serialize: func(value interface{}) interface{} {
+					return strings.ToUpper(value.(string))
+				},
+				ParseValue: func(value interface{}) interface{} {
+					return strings.ToLower(value.(string))
+				},
+				ParseLiteral: func(valueAST ast.Value) interface{} {
+					if v, ok := valueAST.(*ast.StringValue); ok {
+						return strings.ToLower(v.Value)
+					}
+					return nil
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	result := graphql.Do(graphql.Params{
+		Schema:         *schema,
+		RequestString:  `query ($v: Upper) { literal: echo(value: "Hello") variable: echo(value: $v) opaque }`,
+		VariableValues: map[string]interface{}{"v": "World"},
+	})
+	expected := &graphql.Result{
+		Data: map[string]interface{}{
+			"literal":  "HELLO",
+			"variable": "WORLD",
+			"opaque":   42,
+		},
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
+	}
+}
+
+func TestBuildSchemaWithResolvers_RejectsUnknownResolvers(t *testing.T) {
+	sdl := `
+		scalar Date
+		interface Named {
+			name: String
+		}
+		type Query {
+			name: String
+			named: Named
+			date: Date
+		}
+	`
+	noop := func(p graphql.ResolveParams) (interface{}, error) {
+		return nil, nil
+	}
+	tests := []struct {
+		resolvers graphql.ResolverMap
+		expected  string
+	}{
+		{
+			graphql.ResolverMap{Fields: map[string]graphql.FieldResolveFn{"Query.missing": noop}},
+			`Resolver "Query.missing" defined in resolvers, but "missing" is not a field of type "Query".`,
+		},
+		{
+			graphql.ResolverMap{Fields: map[string]graphql.FieldResolveFn{"Missing.name": noop}},
+			`Resolver "Missing.name" defined in resolvers, but type "Missing" is not defined in the schema.`,
+		},
+		{
+			graphql.ResolverMap{Subscribe: map[string]graphql.FieldResolveFn{"name": noop}},
+			`Resolver "name" must be keyed by "Type.field".`,
+		},
+		{
+			graphql.ResolverMap{IsTypeOf: map[string]graphql.IsTypeOfFn{
+				"Named": func(p graphql.IsTypeOfParams) bool { return true },
+			}},
+			`IsTypeOf defined in resolvers for "Named", but "Named" is not an object type in the schema.`,
+		},
+		{
+			graphql.ResolverMap{ResolveType: map[string]graphql.ResolveTypeFn{
+				"Query": func(p graphql.ResolveTypeParams) *graphql.Object { return nil },
+			}},
+			`ResolveType defined in resolvers for "Query", but "Query" is not an interface or union type in the schema.`,
+		},
+		{
+			graphql.ResolverMap{Scalars: map[string]*graphql.ScalarResolver{"String": {}}},
+			`Scalar defined in resolvers for "String", but "String" is not a custom scalar type in the schema.`,
+		},
+	}
+	for _, test := range tests {
+		_, err := graphql.BuildSchemaWithResolvers(sdl, test.resolvers)
+		if err == nil || err.Error() != test.expected {
+			t.Fatalf("Expected error %q, got: %v", test.expected, err)
+		}
+	}
+}
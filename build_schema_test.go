@@ -6,6 +6,7 @@ import (
 
 	"github.com/graphql-go/graphql"
 	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/testutil"
 )
 
 func TestSimpleTypes(t *testing.T) {
@@ -502,11 +503,64 @@ func TestUnreferencedTypeImplementingReferencedUnion(t *testing.T) {
 }
 
 func TestDeprecatedDirective(t *testing.T) {
-	t.Skip("TODO")
+	sdl := `
+	  type Query {
+		  field1: String @deprecated
+		  field2: Int @deprecated(reason: "Because I said so")
+		  enum: MyEnum
+	  }
+
+	  enum MyEnum {
+		  VALUE
+		  OLD_VALUE @deprecated
+		  OTHER_VALUE @deprecated(reason: "Terrible reasons")
+	  }
+	`
+
+	schema, err := graphql.BuildSchema(sdl)
+	if err != nil {
+		t.Fatalf("Unexpected error %s", err.Error())
+	}
+
+	fields := schema.QueryType().Fields()
+	if reason := fields["field1"].DeprecationReason; reason != graphql.DefaultDeprecationReason {
+		t.Fatalf("Unexpected deprecation reason for field1: %q", reason)
+	}
+	if reason := fields["field2"].DeprecationReason; reason != "Because I said so" {
+		t.Fatalf("Unexpected deprecation reason for field2: %q", reason)
+	}
+
+	expected := map[string]string{
+		"VALUE":       "",
+		"OLD_VALUE":   graphql.DefaultDeprecationReason,
+		"OTHER_VALUE": "Terrible reasons",
+	}
+	enumType := schema.Type("MyEnum").(*graphql.Enum)
+	for _, value := range enumType.Values() {
+		if value.DeprecationReason != expected[value.Name] {
+			t.Fatalf("Unexpected deprecation reason for %v: %q", value.Name, value.DeprecationReason)
+		}
+	}
 }
 
 func TestSpecifiedByDirective(t *testing.T) {
-	t.Skip("TODO")
+	sdl := `
+	  scalar Foo @specifiedBy(url: "https://example.com/foo_spec")
+
+	  type Query {
+		  foo: Foo
+	  }
+	`
+
+	schema, err := graphql.BuildSchema(sdl)
+	if err != nil {
+		t.Fatalf("Unexpected error %s", err.Error())
+	}
+
+	scalarType := schema.Type("Foo").(*graphql.Scalar)
+	if url := scalarType.SpecifiedByURL(); url != "https://example.com/foo_spec" {
+		t.Fatalf("Unexpected specifiedBy URL: %q", url)
+	}
 }
 
 func TestExtendType(t *testing.T) {
@@ -702,16 +756,10 @@ func TestRejectsInvalidAst(t *testing.T) {
 	}
 }
 
-// TODO: Add more tests from graphql-js
-
-///////// Tests in graphql-js that do not pass because of graphql-go :(
-
 func TestSimpleInterfaceHierarchy(t *testing.T) {
-	t.Skip("graphql-go does not support interface 'implements'")
-
 	sdl := `
 		schema {
-			query: Child
+			query: Hello
 		}
 
 		interface Child implements Parent {
@@ -727,12 +775,28 @@ func TestSimpleInterfaceHierarchy(t *testing.T) {
 		}
 	`
 
-	_, err := graphql.BuildSchema(sdl)
+	schema, err := graphql.BuildSchema(sdl)
 	if err != nil {
 		t.Fatalf("Unexpected error %s", err.Error())
 	}
+
+	child, ok := schema.Type("Child").(*graphql.Interface)
+	if !ok {
+		t.Fatal("No Child interface")
+	}
+	parent := schema.Type("Parent").(*graphql.Interface)
+	if interfaces := child.Interfaces(); len(interfaces) != 1 || interfaces[0] != parent {
+		t.Fatalf("Unexpected interfaces of Child: %v", interfaces)
+	}
+	if !reflect.DeepEqual(schema.PossibleTypes(parent), []*graphql.Object{schema.QueryType()}) {
+		t.Fatalf("Unexpected possible types of Parent: %v", schema.PossibleTypes(parent))
+	}
 }
 
+// TODO: Add more tests from graphql-js
+
+///////// Tests in graphql-js that do not pass because of graphql-go :(
+
 func TestEmptyEnum(t *testing.T) {
 	t.Skip("graphql-go does not support empty types")
 
@@ -767,8 +831,45 @@ func TestEmptyInputObject(t *testing.T) {
 }
 
 func TestExtendInterface(t *testing.T) {
-	t.Skip("graphql-go parser.Parse does not support extending interfaces")
 	sdl := `
+	  type Query {
+		  some: SomeInterface
+	  }
+
+	  interface SomeInterface {
+		  first: String
+	  }
+
+	  extend interface SomeInterface {
+		  second: Int
+	  }
+
+	  extend interface SomeInterface {
+		  third: Float
+	  }
+	`
+	schema, err := graphql.BuildSchema(sdl)
+	if err != nil {
+		t.Fatalf("Unexpected error %s", err.Error())
+	}
+
+	someInterface, ok := schema.Type("SomeInterface").(*graphql.Interface)
+	if !ok {
+		t.Fatal("SomeInterface is not an Interface type")
+	}
+	for _, name := range []string{"first", "second", "third"} {
+		if _, ok := someInterface.Fields()[name]; !ok {
+			t.Fatalf("SomeInterface does not have field '%s'", name)
+		}
+	}
+}
+
+func TestExtendUnion(t *testing.T) {
+	sdl := `
+	  type Query {
+		  some: SomeUnion
+	  }
+
 	  union SomeUnion = FirstType
 
 	  extend union SomeUnion = SecondType
@@ -785,36 +886,31 @@ func TestExtendInterface(t *testing.T) {
 			third: Float
 		}
 	`
-	_, err := graphql.BuildSchema(sdl)
+	schema, err := graphql.BuildSchema(sdl)
 	if err != nil {
 		t.Fatalf("Unexpected error %s", err.Error())
 	}
-}
-
-func TestExtendUnion(t *testing.T) {
-	t.Skip("graphql-go parser.Parse does not support extending unions")
-	sdl := `
-	  interface SomeInterface {
-		  first: String
-	  }
-
-	  extend interface SomeInterface {
-		  second: Int
-	  }
 
-	  extend interface SomeInterface {
-		  third: Float
-	  }
-	`
-	_, err := graphql.BuildSchema(sdl)
-	if err != nil {
-		t.Fatalf("Unexpected error %s", err.Error())
+	someUnion, ok := schema.Type("SomeUnion").(*graphql.Union)
+	if !ok {
+		t.Fatal("SomeUnion is not a Union type")
+	}
+	typeNames := []string{}
+	for _, ttype := range someUnion.Types() {
+		typeNames = append(typeNames, ttype.Name())
+	}
+	expected := []string{"FirstType", "SecondType", "ThirdType"}
+	if !reflect.DeepEqual(typeNames, expected) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, typeNames))
 	}
 }
 
 func TestExtendEnum(t *testing.T) {
-	t.Skip("graphql-go parser.Parse does not support extending enums")
 	sdl := `
+	  type Query {
+		  some: SomeEnum
+	  }
+
 	  enum SomeEnum {
 		  FIRST
 	  }
@@ -827,15 +923,26 @@ func TestExtendEnum(t *testing.T) {
 		  THIRD
 	  }
 	`
-	_, err := graphql.BuildSchema(sdl)
+	schema, err := graphql.BuildSchema(sdl)
 	if err != nil {
 		t.Fatalf("Unexpected error %s", err.Error())
 	}
+
+	someEnum, ok := schema.Type("SomeEnum").(*graphql.Enum)
+	if !ok {
+		t.Fatal("SomeEnum is not an Enum type")
+	}
+	if len(someEnum.Values()) != 3 {
+		t.Fatalf("Unexpected enum values: %v", someEnum.Values())
+	}
 }
 
 func TestExtendInputType(t *testing.T) {
-	t.Skip("graphql-go parser.Parse does not support extending input types")
 	sdl := `
+	  type Query {
+		  some(input: SomeInput): String
+	  }
+
 	  input SomeInput {
 		  first: String
 	  }
@@ -848,10 +955,20 @@ func TestExtendInputType(t *testing.T) {
 		  third: Float
 	  }
 	`
-	_, err := graphql.BuildSchema(sdl)
+	schema, err := graphql.BuildSchema(sdl)
 	if err != nil {
 		t.Fatalf("Unexpected error %s", err.Error())
 	}
+
+	someInput, ok := schema.Type("SomeInput").(*graphql.InputObject)
+	if !ok {
+		t.Fatal("SomeInput is not an InputObject type")
+	}
+	for _, name := range []string{"first", "second", "third"} {
+		if _, ok := someInput.Fields()[name]; !ok {
+			t.Fatalf("SomeInput does not have field '%s'", name)
+		}
+	}
 }
 
 func TestExtendScalar(t *testing.T) {
@@ -0,0 +1,68 @@
+// Command schemadiff compares two schemas written in SDL and reports the
+// breaking and dangerous changes from the old schema to the new one.
+//
+// Usage:
+//
+//	schemadiff [-dangerous] old.graphql new.graphql
+//
+// It exits with status 1 if there are breaking changes, so that it can be
+// used to block merges in CI.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/graphql-go/graphql"
+)
+
+func main() {
+	dangerous := flag.Bool("dangerous", false, "also fail on dangerous changes")
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: schemadiff [-dangerous] old.graphql new.graphql\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 2 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	oldSchema, err := buildSchema(flag.Arg(0))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "schemadiff: %v\n", err)
+		os.Exit(2)
+	}
+	newSchema, err := buildSchema(flag.Arg(1))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "schemadiff: %v\n", err)
+		os.Exit(2)
+	}
+
+	breakingChanges := graphql.FindBreakingChanges(*oldSchema, *newSchema)
+	dangerousChanges := graphql.FindDangerousChanges(*oldSchema, *newSchema)
+	for _, change := range breakingChanges {
+		fmt.Printf("BREAKING %v: %v\n", change.Type, change.Description)
+	}
+	for _, change := range dangerousChanges {
+		fmt.Printf("DANGEROUS %v: %v\n", change.Type, change.Description)
+	}
+
+	if len(breakingChanges) > 0 || (*dangerous && len(dangerousChanges) > 0) {
+		os.Exit(1)
+	}
+}
+
+func buildSchema(filename string) (*graphql.Schema, error) {
+	b, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	schema, err := graphql.BuildSchema(string(b))
+	if err != nil {
+		return nil, fmt.Errorf("%v: %v", filename, err)
+	}
+	return schema, nil
+}
@@ -0,0 +1,275 @@
+// Package dataloader batches and caches the loads of resolvers, so that the
+// objects referenced by the items of a list are loaded with a single call
+// rather than one call per item.
+//
+// A Loader is created for each request, typically when building its context,
+// as it caches the loaded values for the whole request. Resolvers call Load,
+// which returns a thunk to be returned as the value of the field:
+//
+//	Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+//		loader := p.Context.Value(userLoaderKey).(*dataloader.Loader[string, *User])
+//		return loader.Load(p.Context, p.Source.(*Post).AuthorID).Resolve(), nil
+//	},
+//
+// The keys loaded by the resolvers of a level of the result are dispatched in
+// a single batch when the executor starts resolving the thunks of the level,
+// or when one of the thunks is called, whichever comes first.
+package dataloader
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/graphql-go/graphql"
+)
+
+// BatchFunc loads the values of the given keys. The values, and the errors
+// if any, are in the order of the keys. The errors may also hold a single
+// error, which is then the error of every key.
+type BatchFunc[K comparable, V any] func(ctx context.Context, keys []K) ([]V, []error)
+
+// Thunk awaits a value being loaded.
+type Thunk[V any] func() (V, error)
+
+// Resolve returns the thunk as the value of a field, to be returned by a
+// graphql.FieldResolveFn.
+func (t Thunk[V]) Resolve() func() (interface{}, error) {
+	return func() (interface{}, error) {
+		return t()
+	}
+}
+
+// Errors holds the errors of LoadMany, in the order of the keys, with a nil
+// error for each key which was loaded.
+type Errors []error
+
+func (e Errors) Error() string {
+	messages := []string{}
+	for _, err := range e {
+		if err != nil {
+			messages = append(messages, err.Error())
+		}
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Option configures a Loader.
+type Option func(*options)
+
+type options struct {
+	maxBatch int
+	noCache  bool
+}
+
+// WithMaxBatch limits the number of keys of a batch. A batch is dispatched
+// as soon as it is full.
+func WithMaxBatch(maxBatch int) Option {
+	return func(o *options) {
+		o.maxBatch = maxBatch
+	}
+}
+
+// WithoutCache disables the cache, so that every load is batched even if its
+// key was already loaded.
+func WithoutCache() Option {
+	return func(o *options) {
+		o.noCache = true
+	}
+}
+
+// Loader batches and caches the loads of values of type V by keys of type K.
+// It is safe for concurrent use.
+type Loader[K comparable, V any] struct {
+	batchFn BatchFunc[K, V]
+	options options
+
+	mu    sync.Mutex
+	cache map[K]*entry[V]
+	// batch is the batch to which loads are added, until it is dispatched
+	batch *batch[K, V]
+}
+
+// entry is the value of a key, which is set once done is closed.
+type entry[V any] struct {
+	done  chan struct{}
+	value V
+	err   error
+	// dispatch dispatches the batch loading the entry
+	dispatch func()
+}
+
+type batch[K comparable, V any] struct {
+	ctx        context.Context
+	keys       []K
+	entries    []*entry[V]
+	dispatched sync.Once
+}
+
+// New returns a Loader loading its values with batchFn.
+func New[K comparable, V any](batchFn BatchFunc[K, V], opts ...Option) *Loader[K, V] {
+	l := &Loader[K, V]{
+		batchFn: batchFn,
+		cache:   map[K]*entry[V]{},
+	}
+	for _, opt := range opts {
+		opt(&l.options)
+	}
+	return l
+}
+
+// Load loads the value of key. The key is added to the current batch, which
+// is given ctx if it is the first key of the batch.
+func (l *Loader[K, V]) Load(ctx context.Context, key K) Thunk[V] {
+	l.mu.Lock()
+	if e, ok := l.cache[key]; ok {
+		l.mu.Unlock()
+		return e.thunk()
+	}
+
+	e := &entry[V]{done: make(chan struct{})}
+	if !l.options.noCache {
+		l.cache[key] = e
+	}
+	b := l.batch
+	isNew := b == nil
+	if isNew {
+		b = &batch[K, V]{ctx: ctx}
+		l.batch = b
+	}
+	e.dispatch = func() { l.dispatch(b) }
+	b.keys = append(b.keys, key)
+	b.entries = append(b.entries, e)
+	isFull := l.options.maxBatch > 0 && len(b.keys) >= l.options.maxBatch
+	l.mu.Unlock()
+
+	if isFull {
+		e.dispatch()
+	} else if isNew {
+		graphql.RegisterDispatcher(ctx, graphql.DispatcherFunc(e.dispatch))
+	}
+	return e.thunk()
+}
+
+// LoadMany loads the values of keys. The thunk returns Errors if any of the
+// keys failed to load.
+func (l *Loader[K, V]) LoadMany(ctx context.Context, keys []K) Thunk[[]V] {
+	thunks := make([]Thunk[V], len(keys))
+	for i, key := range keys {
+		thunks[i] = l.Load(ctx, key)
+	}
+	return func() ([]V, error) {
+		values := make([]V, len(keys))
+		var errs Errors
+		for i, thunk := range thunks {
+			value, err := thunk()
+			if err != nil {
+				if errs == nil {
+					errs = make(Errors, len(keys))
+				}
+				errs[i] = err
+				continue
+			}
+			values[i] = value
+		}
+		if errs != nil {
+			return values, errs
+		}
+		return values, nil
+	}
+}
+
+// Prime caches the value of key, unless it is already cached.
+func (l *Loader[K, V]) Prime(key K, value V) {
+	if l.options.noCache {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, ok := l.cache[key]; ok {
+		return
+	}
+	e := &entry[V]{done: make(chan struct{}), value: value}
+	close(e.done)
+	l.cache[key] = e
+}
+
+// Clear removes the value of key from the cache, such as after a mutation
+// changed it.
+func (l *Loader[K, V]) Clear(key K) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.cache, key)
+}
+
+// ClearAll empties the cache.
+func (l *Loader[K, V]) ClearAll() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.cache = map[K]*entry[V]{}
+}
+
+// dispatch loads the keys of b, unless it was already dispatched.
+func (l *Loader[K, V]) dispatch(b *batch[K, V]) {
+	b.dispatched.Do(func() {
+		l.mu.Lock()
+		if l.batch == b {
+			l.batch = nil
+		}
+		l.mu.Unlock()
+		go b.load(l.batchFn)
+	})
+}
+
+// load loads the keys of b, setting the values of its entries.
+func (b *batch[K, V]) load(batchFn BatchFunc[K, V]) {
+	var (
+		values []V
+		errs   []error
+	)
+	defer func() {
+		if r := recover(); r != nil {
+			values, errs = nil, []error{fmt.Errorf("dataloader: panic in the batch function: %v", r)}
+		}
+		if len(errs) > 1 && len(errs) != len(b.keys) {
+			errs = []error{fmt.Errorf("dataloader: the batch function returned %v errors for %v keys", len(errs), len(b.keys))}
+		}
+		for i, e := range b.entries {
+			var err error
+			switch len(errs) {
+			case len(b.keys):
+				err = errs[i]
+			case 1:
+				err = errs[0]
+			}
+			if err == nil && len(values) != len(b.keys) {
+				err = fmt.Errorf("dataloader: the batch function returned %v values for %v keys", len(values), len(b.keys))
+			}
+			if err != nil {
+				e.err = err
+			} else {
+				e.value = values[i]
+			}
+			close(e.done)
+		}
+	}()
+
+	ctx := b.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	values, errs = batchFn(ctx, b.keys)
+}
+
+// thunk returns a thunk awaiting the value of e, after making sure that its
+// batch is dispatched.
+func (e *entry[V]) thunk() Thunk[V] {
+	return func() (V, error) {
+		if e.dispatch != nil {
+			e.dispatch()
+		}
+		<-e.done
+		return e.value, e.err
+	}
+}
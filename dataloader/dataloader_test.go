@@ -0,0 +1,306 @@
+package dataloader_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/dataloader"
+	"github.com/graphql-go/graphql/testutil"
+)
+
+type user struct {
+	ID           string
+	Name         string
+	BestFriendID string
+}
+
+var users = map[string]*user{
+	"1": {ID: "1", Name: "Luke", BestFriendID: "2"},
+	"2": {ID: "2", Name: "Leia", BestFriendID: "3"},
+	"3": {ID: "3", Name: "Han", BestFriendID: "4"},
+	"4": {ID: "4", Name: "Chewbacca", BestFriendID: "3"},
+}
+
+// batchRecorder records the keys of the batches of a loader.
+type batchRecorder struct {
+	mu      sync.Mutex
+	batches [][]string
+}
+
+func (r *batchRecorder) loadUsers(ctx context.Context, keys []string) ([]*user, []error) {
+	r.mu.Lock()
+	batch := append([]string{}, keys...)
+	sort.Strings(batch)
+	r.batches = append(r.batches, batch)
+	r.mu.Unlock()
+
+	values := make([]*user, len(keys))
+	errs := make([]error, len(keys))
+	for i, key := range keys {
+		if values[i] = users[key]; values[i] == nil {
+			errs[i] = errors.New("user " + key + " not found")
+		}
+	}
+	return values, errs
+}
+
+type loaderKey struct{}
+
+func userLoader(ctx context.Context) *dataloader.Loader[string, *user] {
+	return ctx.Value(loaderKey{}).(*dataloader.Loader[string, *user])
+}
+
+func newSchema(t *testing.T) graphql.Schema {
+	userType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "User",
+		Fields: graphql.Fields{
+			"name": &graphql.Field{
+				Type: graphql.String,
+			},
+		},
+	})
+	userType.AddFieldConfig("bestFriend", &graphql.Field{
+		Type: userType,
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return userLoader(p.Context).Load(p.Context, p.Source.(*user).BestFriendID).Resolve(), nil
+		},
+	})
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Query",
+			Fields: graphql.Fields{
+				"posts": &graphql.Field{
+					Type: graphql.NewList(graphql.NewObject(graphql.ObjectConfig{
+						Name: "Post",
+						Fields: graphql.Fields{
+							"author": &graphql.Field{
+								Type: userType,
+								Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+									return userLoader(p.Context).Load(p.Context, p.Source.(string)).Resolve(), nil
+								},
+							},
+						},
+					})),
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						// the posts are represented by the ids of their authors
+						return []interface{}{"1", "2", "1", "3", "5"}, nil
+					},
+				},
+			},
+		}),
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return schema
+}
+
+func TestLoader_BatchesTheLoadsOfEachLevel(t *testing.T) {
+	recorder := &batchRecorder{}
+	ctx := context.WithValue(context.Background(), loaderKey{}, dataloader.New(recorder.loadUsers))
+
+	result := graphql.Do(graphql.Params{
+		Schema:        newSchema(t),
+		RequestString: `{ posts { author { name bestFriend { name bestFriend { name } } } } }`,
+		Context:       ctx,
+	})
+
+	luke := map[string]interface{}{
+		"name": "Luke",
+		"bestFriend": map[string]interface{}{
+			"name":       "Leia",
+			"bestFriend": map[string]interface{}{"name": "Han"},
+		},
+	}
+	expectedData := map[string]interface{}{
+		"posts": []interface{}{
+			map[string]interface{}{"author": luke},
+			map[string]interface{}{"author": map[string]interface{}{
+				"name": "Leia",
+				"bestFriend": map[string]interface{}{
+					"name":       "Han",
+					"bestFriend": map[string]interface{}{"name": "Chewbacca"},
+				},
+			}},
+			map[string]interface{}{"author": luke},
+			map[string]interface{}{"author": map[string]interface{}{
+				"name": "Han",
+				"bestFriend": map[string]interface{}{
+					"name":       "Chewbacca",
+					"bestFriend": map[string]interface{}{"name": "Han"},
+				},
+			}},
+			map[string]interface{}{"author": nil},
+		},
+	}
+	if !reflect.DeepEqual(expectedData, result.Data) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expectedData, result.Data))
+	}
+	if len(result.Errors) != 1 || result.Errors[0].Message != "user 5 not found" {
+		t.Fatalf("Unexpected errors: %v", result.Errors)
+	}
+
+	// the keys already loaded are cached
+	expectedBatches := [][]string{{"1", "2", "3", "5"}, {"4"}}
+	if !reflect.DeepEqual(expectedBatches, recorder.batches) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expectedBatches, recorder.batches))
+	}
+}
+
+func TestLoader_DispatchesTheLoadersOfALevelTogether(t *testing.T) {
+	started := make(chan struct{})
+	first := dataloader.New(func(ctx context.Context, keys []string) ([]string, []error) {
+		// without the executor dispatching both loaders, the second one
+		// would only be dispatched once the thunk of the first one returned
+		select {
+		case <-started:
+			return keys, nil
+		case <-time.After(time.Second):
+			return nil, []error{errors.New("the second loader was not dispatched")}
+		}
+	})
+	second := dataloader.New(func(ctx context.Context, keys []string) ([]string, []error) {
+		close(started)
+		return keys, nil
+	})
+	field := func(loader *dataloader.Loader[string, string]) *graphql.Field {
+		return &graphql.Field{
+			Type: graphql.String,
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return loader.Load(p.Context, p.Info.FieldName).Resolve(), nil
+			},
+		}
+	}
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Query",
+			Fields: graphql.Fields{
+				"a": field(first),
+				"b": field(second),
+			},
+		}),
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	result := graphql.Do(graphql.Params{
+		Schema:        schema,
+		RequestString: `{ a b }`,
+		Context:       context.Background(),
+	})
+	expected := &graphql.Result{
+		Data: map[string]interface{}{"a": "a", "b": "b"},
+	}
+	if !reflect.DeepEqual(expected, result) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
+	}
+}
+
+func TestLoader_SplitsBatchesLargerThanMaxBatch(t *testing.T) {
+	recorder := &batchRecorder{}
+	loader := dataloader.New(recorder.loadUsers, dataloader.WithMaxBatch(2))
+
+	thunk := loader.LoadMany(context.Background(), []string{"1", "2", "3", "4", "1"})
+	values, err := thunk()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	names := []string{}
+	for _, value := range values {
+		names = append(names, value.Name)
+	}
+	expectedNames := []string{"Luke", "Leia", "Han", "Chewbacca", "Luke"}
+	if !reflect.DeepEqual(expectedNames, names) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expectedNames, names))
+	}
+	// the full batches are loaded concurrently
+	sort.Slice(recorder.batches, func(i, j int) bool {
+		return recorder.batches[i][0] < recorder.batches[j][0]
+	})
+	expectedBatches := [][]string{{"1", "2"}, {"3", "4"}}
+	if !reflect.DeepEqual(expectedBatches, recorder.batches) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expectedBatches, recorder.batches))
+	}
+}
+
+func TestLoader_CachesValues(t *testing.T) {
+	recorder := &batchRecorder{}
+	loader := dataloader.New(recorder.loadUsers)
+	ctx := context.Background()
+
+	loader.Prime("4", &user{ID: "4", Name: "Chewie"})
+	if _, err := loader.LoadMany(ctx, []string{"1", "4"})(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := loader.Load(ctx, "1")(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	loader.Clear("1")
+	if _, err := loader.Load(ctx, "1")(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if value, _ := loader.Load(ctx, "4")(); value.Name != "Chewie" {
+		t.Fatalf("Unexpected primed value: %v", value.Name)
+	}
+
+	expectedBatches := [][]string{{"1"}, {"1"}}
+	if !reflect.DeepEqual(expectedBatches, recorder.batches) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expectedBatches, recorder.batches))
+	}
+}
+
+func TestLoader_ReportsErrors(t *testing.T) {
+	ctx := context.Background()
+
+	loader := dataloader.New((&batchRecorder{}).loadUsers)
+	values, err := loader.LoadMany(ctx, []string{"1", "5"})()
+	errs, ok := err.(dataloader.Errors)
+	if !ok || len(errs) != 2 || errs[0] != nil || errs[1].Error() != "user 5 not found" {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if values[0].Name != "Luke" || values[1] != nil {
+		t.Fatalf("Unexpected values: %v", values)
+	}
+
+	tests := map[string]struct {
+		batchFn  dataloader.BatchFunc[string, string]
+		expected string
+	}{
+		"batch error": {
+			batchFn: func(ctx context.Context, keys []string) ([]string, []error) {
+				return nil, []error{errors.New("unavailable")}
+			},
+			expected: "unavailable",
+		},
+		"missing values": {
+			batchFn: func(ctx context.Context, keys []string) ([]string, []error) {
+				return keys[:1], nil
+			},
+			expected: "dataloader: the batch function returned 1 values for 2 keys",
+		},
+		"panic": {
+			batchFn: func(ctx context.Context, keys []string) ([]string, []error) {
+				panic("oops")
+			},
+			expected: "dataloader: panic in the batch function: oops",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			loader := dataloader.New(test.batchFn)
+			a, b := loader.Load(ctx, "a"), loader.Load(ctx, "b")
+			for _, thunk := range []dataloader.Thunk[string]{a, b} {
+				if _, err := thunk(); err == nil || err.Error() != test.expected {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+			}
+		})
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"reflect"
 	"regexp"
+	"time"
 
 	"github.com/graphql-go/graphql/language/ast"
 )
@@ -193,13 +194,12 @@ func GetNamed(ttype Type) Named {
 //
 // Example:
 //
-//    var OddType = new Scalar({
-//      name: 'Odd',
-//      serialize(value) {
-//        return value % 2 === 1 ? value : null;
-//      }
-//    });
-//
+//	var OddType = new Scalar({
+//	  name: 'Odd',
+//	  serialize(value) {
+//	    return value % 2 === 1 ? value : null;
+//	  }
+//	});
 type Scalar struct {
 	PrivateName        string `json:"name"`
 	PrivateDescription string `json:"description"`
@@ -219,11 +219,12 @@ type ParseLiteralFn func(valueAST ast.Value) interface{}
 
 // ScalarConfig options for creating a new GraphQLScalar
 type ScalarConfig struct {
-	Name         string `json:"name"`
-	Description  string `json:"description"`
-	Serialize    SerializeFn
-	ParseValue   ParseValueFn
-	ParseLiteral ParseLiteralFn
+	Name           string `json:"name"`
+	Description    string `json:"description"`
+	SpecifiedByURL string `json:"specifiedByURL"`
+	Serialize      SerializeFn
+	ParseValue     ParseValueFn
+	ParseLiteral   ParseLiteralFn
 }
 
 // NewScalar creates a new GraphQLScalar
@@ -286,6 +287,11 @@ func (st *Scalar) ParseLiteral(valueAST ast.Value) interface{} {
 	}
 	return st.scalarConfig.ParseLiteral(valueAST)
 }
+
+// SpecifiedByURL returns the URL of the specification for this custom scalar, if any
+func (st *Scalar) SpecifiedByURL() string {
+	return st.scalarConfig.SpecifiedByURL
+}
 func (st *Scalar) Name() string {
 	return st.PrivateName
 }
@@ -306,19 +312,19 @@ func (st *Scalar) Error() error {
 // have a name, but most importantly describe their fields.
 // Example:
 //
-//    var AddressType = new Object({
-//      name: 'Address',
-//      fields: {
-//        street: { type: String },
-//        number: { type: Int },
-//        formatted: {
-//          type: String,
-//          resolve(obj) {
-//            return obj.number + ' ' + obj.street
-//          }
-//        }
-//      }
-//    });
+//	var AddressType = new Object({
+//	  name: 'Address',
+//	  fields: {
+//	    street: { type: String },
+//	    number: { type: Int },
+//	    formatted: {
+//	      type: String,
+//	      resolve(obj) {
+//	        return obj.number + ' ' + obj.street
+//	      }
+//	    }
+//	  }
+//	});
 //
 // When two types need to refer to each other, or a type needs to refer to
 // itself in a field, you can use a function expression (aka a closure or a
@@ -326,13 +332,13 @@ func (st *Scalar) Error() error {
 //
 // Example:
 //
-//    var PersonType = new Object({
-//      name: 'Person',
-//      fields: () => ({
-//        name: { type: String },
-//        bestFriend: { type: PersonType },
-//      })
-//    });
+//	var PersonType = new Object({
+//	  name: 'Person',
+//	  fields: () => ({
+//	    name: { type: String },
+//	    bestFriend: { type: PersonType },
+//	  })
+//	});
 //
 // /
 type Object struct {
@@ -419,7 +425,7 @@ func (gt *Object) Name() string {
 	return gt.PrivateName
 }
 func (gt *Object) Description() string {
-	return ""
+	return gt.PrivateDescription
 }
 func (gt *Object) String() string {
 	return gt.PrivateName
@@ -469,7 +475,7 @@ func (gt *Object) Error() error {
 	return gt.err
 }
 
-func defineInterfaces(ttype *Object, interfaces []*Interface) ([]*Interface, error) {
+func defineInterfaces(ttype Named, interfaces []*Interface) ([]*Interface, error) {
 	ifaces := []*Interface{}
 
 	if len(interfaces) == 0 {
@@ -536,6 +542,8 @@ func defineFieldMap(ttype Named, fieldMap Fields) (FieldDefinitionMap, error) {
 			Resolve:           field.Resolve,
 			Subscribe:         field.Subscribe,
 			DeprecationReason: field.DeprecationReason,
+			Timeout:           field.Timeout,
+			Directives:        field.Directives,
 		}
 
 		fieldDef.Args = []*Argument{}
@@ -587,6 +595,31 @@ type ResolveParams struct {
 
 type FieldResolveFn func(p ResolveParams) (interface{}, error)
 
+// FieldMiddleware wraps the resolvers of the fields of a schema, including
+// DefaultResolveFn, with cross-cutting logic such as authorization checks or
+// logging. The returned FieldResolveFn calls next to resolve the field, or
+// returns without calling it, e.g. to deny access to the field. It may only
+// apply to some fields, selected with the ResolveInfo of the ResolveParams:
+//
+//	func(next graphql.FieldResolveFn) graphql.FieldResolveFn {
+//		return func(p graphql.ResolveParams) (interface{}, error) {
+//			if p.Info.FieldDefinition().Directive("auth") != nil && !isAuthenticated(p.Context) {
+//				return nil, errUnauthenticated
+//			}
+//			return next(p)
+//		}
+//	}
+type FieldMiddleware func(next FieldResolveFn) FieldResolveFn
+
+// applyFieldMiddleware wraps resolveFn with the middleware, the first
+// middleware being the outermost.
+func applyFieldMiddleware(middleware []FieldMiddleware, resolveFn FieldResolveFn) FieldResolveFn {
+	for i := len(middleware) - 1; i >= 0; i-- {
+		resolveFn = middleware[i](resolveFn)
+	}
+	return resolveFn
+}
+
 type ResolveInfo struct {
 	FieldName      string
 	FieldASTs      []*ast.Field
@@ -600,6 +633,18 @@ type ResolveInfo struct {
 	VariableValues map[string]interface{}
 }
 
+// FieldDefinition returns the definition of the resolved field, or nil if
+// the parent type has no such field.
+func (info ResolveInfo) FieldDefinition() *FieldDefinition {
+	switch parentType := info.ParentType.(type) {
+	case *Object:
+		return parentType.Fields()[info.FieldName]
+	case *Interface:
+		return parentType.Fields()[info.FieldName]
+	}
+	return nil
+}
+
 type Fields map[string]*Field
 
 type Field struct {
@@ -610,6 +655,14 @@ type Field struct {
 	Subscribe         FieldResolveFn      `json:"-"`
 	DeprecationReason string              `json:"deprecationReason"`
 	Description       string              `json:"description"`
+
+	// Timeout bounds the time taken to resolve the field, which is resolved
+	// to null with a FieldTimeoutError once it elapses.
+	Timeout time.Duration `json:"-"`
+
+	// Directives are the directives of the definition of the field in the
+	// schema language, e.g. to be checked by FieldMiddleware.
+	Directives []*ast.Directive `json:"-"`
 }
 
 type FieldConfigArgument map[string]*ArgumentConfig
@@ -622,13 +675,29 @@ type ArgumentConfig struct {
 
 type FieldDefinitionMap map[string]*FieldDefinition
 type FieldDefinition struct {
-	Name              string         `json:"name"`
-	Description       string         `json:"description"`
-	Type              Output         `json:"type"`
-	Args              []*Argument    `json:"args"`
-	Resolve           FieldResolveFn `json:"-"`
-	Subscribe         FieldResolveFn `json:"-"`
-	DeprecationReason string         `json:"deprecationReason"`
+	Name              string           `json:"name"`
+	Description       string           `json:"description"`
+	Type              Output           `json:"type"`
+	Args              []*Argument      `json:"args"`
+	Resolve           FieldResolveFn   `json:"-"`
+	Subscribe         FieldResolveFn   `json:"-"`
+	DeprecationReason string           `json:"deprecationReason"`
+	Timeout           time.Duration    `json:"-"`
+	Directives        []*ast.Directive `json:"-"`
+}
+
+// Directive returns the directive of the definition of the field with the
+// given name, or nil if it has none.
+func (fd *FieldDefinition) Directive(name string) *ast.Directive {
+	if fd == nil {
+		return nil
+	}
+	for _, directive := range fd.Directives {
+		if directive.Name != nil && directive.Name.Value == name {
+			return directive
+		}
+	}
+	return nil
 }
 
 type FieldArgument struct {
@@ -668,26 +737,30 @@ func (st *Argument) Error() error {
 //
 // Example:
 //
-//     var EntityType = new Interface({
-//       name: 'Entity',
-//       fields: {
-//         name: { type: String }
-//       }
-//     });
-//
+//	var EntityType = new Interface({
+//	  name: 'Entity',
+//	  fields: {
+//	    name: { type: String }
+//	  }
+//	});
 //
+// An Interface may itself implement other interfaces, in which case it must
+// also list every interface implemented by those interfaces.
 type Interface struct {
 	PrivateName        string `json:"name"`
 	PrivateDescription string `json:"description"`
 	ResolveType        ResolveTypeFn
 
-	typeConfig        InterfaceConfig
-	initialisedFields bool
-	fields            FieldDefinitionMap
-	err               error
+	typeConfig            InterfaceConfig
+	initialisedFields     bool
+	fields                FieldDefinitionMap
+	initialisedInterfaces bool
+	interfaces            []*Interface
+	err                   error
 }
 type InterfaceConfig struct {
 	Name        string      `json:"name"`
+	Interfaces  interface{} `json:"interfaces"`
 	Fields      interface{} `json:"fields"`
 	ResolveType ResolveTypeFn
 	Description string `json:"description"`
@@ -763,6 +836,29 @@ func (it *Interface) Fields() (fields FieldDefinitionMap) {
 	return it.fields
 }
 
+func (it *Interface) Interfaces() []*Interface {
+	if it.initialisedInterfaces {
+		return it.interfaces
+	}
+
+	var configInterfaces []*Interface
+	switch iface := it.typeConfig.Interfaces.(type) {
+	case InterfacesThunk:
+		configInterfaces = iface()
+	case []*Interface:
+		configInterfaces = iface
+	case nil:
+	default:
+		it.err = fmt.Errorf("Unknown Interface.Interfaces type: %T", it.typeConfig.Interfaces)
+		it.initialisedInterfaces = true
+		return nil
+	}
+
+	it.interfaces, it.err = defineInterfaces(it, configInterfaces)
+	it.initialisedInterfaces = true
+	return it.interfaces
+}
+
 func (it *Interface) String() string {
 	return it.PrivateName
 }
@@ -779,18 +875,18 @@ func (it *Interface) Error() error {
 //
 // Example:
 //
-//     var PetType = new Union({
-//       name: 'Pet',
-//       types: [ DogType, CatType ],
-//       resolveType(value) {
-//         if (value instanceof Dog) {
-//           return DogType;
-//         }
-//         if (value instanceof Cat) {
-//           return CatType;
-//         }
-//       }
-//     });
+//	var PetType = new Union({
+//	  name: 'Pet',
+//	  types: [ DogType, CatType ],
+//	  resolveType(value) {
+//	    if (value instanceof Dog) {
+//	      return DogType;
+//	    }
+//	    if (value instanceof Cat) {
+//	      return CatType;
+//	    }
+//	  }
+//	});
 type Union struct {
 	PrivateName        string `json:"name"`
 	PrivateDescription string `json:"description"`
@@ -1049,18 +1145,18 @@ func (gt *Enum) getNameLookup() map[string]*EnumValueDefinition {
 // An input object defines a structured collection of fields which may be
 // supplied to a field argument.
 //
-// Using `NonNull` will ensure that a value must be provided by the query
+// Using `NonNull` will ensure that a value must be provided by the query.
 //
 // Example:
 //
-//     var GeoPoint = new InputObject({
-//       name: 'GeoPoint',
-//       fields: {
-//         lat: { type: new NonNull(Float) },
-//         lon: { type: new NonNull(Float) },
-//         alt: { type: Float, defaultValue: 0 },
-//       }
-//     });
+//	var GeoPoint = new InputObject({
+//	  name: 'GeoPoint',
+//	  fields: {
+//	    lat: { type: new NonNull(Float) },
+//	    lon: { type: new NonNull(Float) },
+//	    alt: { type: Float, defaultValue: 0 },
+//	  }
+//	});
 type InputObject struct {
 	PrivateName        string `json:"name"`
 	PrivateDescription string `json:"description"`
@@ -1199,14 +1295,13 @@ func (gt *InputObject) Error() error {
 //
 // Example:
 //
-//     var PersonType = new Object({
-//       name: 'Person',
-//       fields: () => ({
-//         parents: { type: new List(Person) },
-//         children: { type: new List(Person) },
-//       })
-//     })
-//
+//	var PersonType = new Object({
+//	  name: 'Person',
+//	  fields: () => ({
+//	    parents: { type: new List(Person) },
+//	    children: { type: new List(Person) },
+//	  })
+//	})
 type List struct {
 	OfType Type `json:"ofType"`
 
@@ -1250,12 +1345,12 @@ func (gl *List) Error() error {
 //
 // Example:
 //
-//     var RowType = new Object({
-//       name: 'Row',
-//       fields: () => ({
-//         id: { type: new NonNull(String) },
-//       })
-//     })
+//	var RowType = new Object({
+//	  name: 'Row',
+//	  fields: () => ({
+//	    id: { type: new NonNull(String) },
+//	  })
+//	})
 //
 // Note: the enforcement of non-nullability occurs within the executor.
 type NonNull struct {
@@ -164,3 +164,12 @@ var SpecifiedByDirective = NewDirective(DirectiveConfig{
 		DirectiveLocationScalar,
 	},
 })
+
+// specifiedDirectivesWithSpecifiedBy returns a new slice of the
+// SpecifiedDirectives followed by the SpecifiedByDirective, so that appending
+// to it never alters SpecifiedDirectives.
+func specifiedDirectivesWithSpecifiedBy() []*Directive {
+	directives := make([]*Directive, 0, len(SpecifiedDirectives)+1)
+	directives = append(directives, SpecifiedDirectives...)
+	return append(directives, SpecifiedByDirective)
+}
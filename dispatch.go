@@ -0,0 +1,74 @@
+package graphql
+
+import (
+	"context"
+	"sync"
+)
+
+// Dispatcher dispatches the work batched by resolvers, such as the loads of a
+// dataloader, whose results are awaited by the thunks they returned.
+type Dispatcher interface {
+	Dispatch()
+}
+
+// DispatcherFunc is a function used as a Dispatcher.
+type DispatcherFunc func()
+
+// Dispatch calls f.
+func (f DispatcherFunc) Dispatch() {
+	f()
+}
+
+type dispatchersKey struct{}
+
+// dispatchers holds the Dispatchers registered by the resolvers of an
+// execution, which are dispatched before the executor resolves the thunks of
+// the next level of the result.
+type dispatchers struct {
+	mu      sync.Mutex
+	pending []Dispatcher
+}
+
+// RegisterDispatcher registers d with the execution of a resolver given its
+// context, so that d is dispatched once, before the executor resolves the
+// thunks of the next level of the result. Batching the work of the resolvers
+// of a level this way, siblings such as the items of a list share a single
+// batch. It reports whether ctx is the context of a resolver; otherwise d is
+// not registered and the work must be dispatched when its thunks are called.
+func RegisterDispatcher(ctx context.Context, d Dispatcher) bool {
+	if ctx == nil {
+		return false
+	}
+	registered, ok := ctx.Value(dispatchersKey{}).(*dispatchers)
+	if !ok {
+		return false
+	}
+	registered.mu.Lock()
+	defer registered.mu.Unlock()
+	registered.pending = append(registered.pending, d)
+	return true
+}
+
+// withDispatchers returns the context given to resolvers, through which they
+// register their Dispatchers.
+func withDispatchers(ctx context.Context, d *dispatchers) context.Context {
+	if ctx == nil {
+		return nil
+	}
+	return context.WithValue(ctx, dispatchersKey{}, d)
+}
+
+// dispatch dispatches the pending Dispatchers, in the order in which they were
+// registered.
+func (d *dispatchers) dispatch() {
+	if d == nil {
+		return
+	}
+	d.mu.Lock()
+	pending := d.pending
+	d.pending = nil
+	d.mu.Unlock()
+	for _, dispatcher := range pending {
+		dispatcher.Dispatch()
+	}
+}
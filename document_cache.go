@@ -0,0 +1,196 @@
+package graphql
+
+import (
+	"container/list"
+	"sync"
+	"sync/atomic"
+
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+)
+
+// DefaultDocumentCacheSize is the size, in bytes, suggested for the caches
+// created with NewLRUDocumentCache.
+const DefaultDocumentCacheSize = 1 << 20
+
+// DefaultDocumentCache is the DocumentCache used by Do and Subscribe when
+// Params.DocumentCache is nil. It is nil, so that documents are only cached
+// once a cache is set, e.g.
+//
+//	graphql.DefaultDocumentCache = graphql.NewLRUDocumentCache(graphql.DefaultDocumentCacheSize)
+var DefaultDocumentCache DocumentCache
+
+// DocumentCache caches the documents parsed and validated by Do and
+// Subscribe, so that repeated operations are neither parsed nor validated
+// again. Only valid documents are cached. Implementations must be safe for
+// concurrent use.
+type DocumentCache interface {
+	// Get returns the document cached with the given key, if any.
+	Get(key DocumentCacheKey) (*CachedDocument, bool)
+
+	// Add caches the given document with the given key.
+	Add(key DocumentCacheKey, document *CachedDocument)
+}
+
+// DocumentCacheKey identifies a document by its query text and by the schema
+// against which it was validated.
+type DocumentCacheKey struct {
+	Query    string
+	SchemaID uint64
+}
+
+// CachedDocument is a parsed document along with the result of its
+// validation with the SpecifiedRules. It is shared by the requests executing
+// the document, so it must not be modified.
+type CachedDocument struct {
+	AST              *ast.Document
+	ValidationResult ValidationResult
+}
+
+var schemaIDs uint64
+
+func nextSchemaID() uint64 {
+	return atomic.AddUint64(&schemaIDs, 1)
+}
+
+// lruDocumentCache is a DocumentCache evicting the least recently used
+// documents once the total size of their query texts exceeds its size.
+type lruDocumentCache struct {
+	mu   sync.Mutex
+	size int
+	// used is the total size of the query texts of the entries
+	used    int
+	entries map[DocumentCacheKey]*list.Element
+	// order holds the entries from the most to the least recently used
+	order *list.List
+}
+
+type lruDocumentCacheEntry struct {
+	key      DocumentCacheKey
+	document *CachedDocument
+}
+
+// NewLRUDocumentCache returns a DocumentCache holding the documents whose
+// query texts total at most size bytes, evicting the least recently used
+// ones. Larger documents are not cached.
+func NewLRUDocumentCache(size int) DocumentCache {
+	return &lruDocumentCache{
+		size:    size,
+		entries: map[DocumentCacheKey]*list.Element{},
+		order:   list.New(),
+	}
+}
+
+func (c *lruDocumentCache) Get(key DocumentCacheKey) (*CachedDocument, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(element)
+	return element.Value.(*lruDocumentCacheEntry).document, true
+}
+
+func (c *lruDocumentCache) Add(key DocumentCacheKey, document *CachedDocument) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if element, ok := c.entries[key]; ok {
+		element.Value.(*lruDocumentCacheEntry).document = document
+		c.order.MoveToFront(element)
+		return
+	}
+	if len(key.Query) > c.size {
+		return
+	}
+	for c.used+len(key.Query) > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		oldestKey := oldest.Value.(*lruDocumentCacheEntry).key
+		delete(c.entries, oldestKey)
+		c.used -= len(oldestKey.Query)
+	}
+	c.entries[key] = c.order.PushFront(&lruDocumentCacheEntry{key: key, document: document})
+	c.used += len(key.Query)
+}
+
+// documentLookup is the lookup of the document of a request in its
+// DocumentCache.
+type documentLookup struct {
+	cache DocumentCache
+	key   DocumentCacheKey
+	// document is the cached document, if it was found
+	document *CachedDocument
+}
+
+func lookupDocument(p Params) *documentLookup {
+	lookup := &documentLookup{
+		cache: p.DocumentCache,
+		key: DocumentCacheKey{
+			Query:    p.RequestString,
+			SchemaID: p.Schema.id,
+		},
+	}
+	if lookup.cache == nil {
+		lookup.cache = DefaultDocumentCache
+	}
+	// schemas which were not created with NewSchema have no identity
+	if lookup.cache == nil || lookup.key.SchemaID == 0 {
+		lookup.cache = nil
+		return lookup
+	}
+	lookup.document, _ = lookup.cache.Get(lookup.key)
+	return lookup
+}
+
+// parse returns the cached document, or parses the document of p.
+func (l *documentLookup) parse(p Params) (*ast.Document, error) {
+	if l.document != nil {
+		return l.document.AST, nil
+	}
+	return parser.Parse(parser.ParseParams{
+		Source: source.NewSource(&source.Source{
+			Body: []byte(p.RequestString),
+			Name: "GraphQL request",
+		}),
+	})
+}
+
+// validate validates the document with the rules selected by p. Valid
+// documents are cached along with the result of their validation with the
+// SpecifiedRules, while the ValidationRules of p run on each request.
+func (l *documentLookup) validate(p Params, AST *ast.Document) ValidationResult {
+	if p.SkipValidation {
+		return ValidationResult{IsValid: true}
+	}
+
+	result := ValidationResult{IsValid: true}
+	if !p.ReplaceSpecifiedRules {
+		if l.document != nil {
+			result = l.document.ValidationResult
+		} else {
+			result = ValidateDocument(&p.Schema, AST, SpecifiedRules)
+			if l.cache != nil && result.IsValid {
+				l.cache.Add(l.key, &CachedDocument{
+					AST:              AST,
+					ValidationResult: result,
+				})
+			}
+		}
+	}
+
+	// ValidateDocument would run the SpecifiedRules given no rules
+	if len(p.ValidationRules) == 0 {
+		return result
+	}
+	additional := validateDocument(&p.Schema, AST, p.ValidationRules, p.VariableValues)
+	errors := make([]gqlerrors.FormattedError, 0, len(result.Errors)+len(additional.Errors))
+	errors = append(errors, result.Errors...)
+	errors = append(errors, additional.Errors...)
+	return ValidationResult{
+		IsValid: result.IsValid && additional.IsValid,
+		Errors:  errors,
+	}
+}
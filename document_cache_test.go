@@ -0,0 +1,205 @@
+package graphql_test
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/location"
+	"github.com/graphql-go/graphql/testutil"
+)
+
+// countingDocumentCache is a DocumentCache counting its hits and misses.
+type countingDocumentCache struct {
+	graphql.DocumentCache
+
+	mu     sync.Mutex
+	hits   int
+	misses int
+}
+
+func newCountingDocumentCache() *countingDocumentCache {
+	return &countingDocumentCache{DocumentCache: graphql.NewLRUDocumentCache(graphql.DefaultDocumentCacheSize)}
+}
+
+func (c *countingDocumentCache) Get(key graphql.DocumentCacheKey) (*graphql.CachedDocument, bool) {
+	document, ok := c.DocumentCache.Get(key)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if ok {
+		c.hits++
+	} else {
+		c.misses++
+	}
+	return document, ok
+}
+
+func TestLRUDocumentCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	// the query texts have 5 bytes each
+	cache := graphql.NewLRUDocumentCache(10)
+	keys := []graphql.DocumentCacheKey{
+		{Query: "{ a }", SchemaID: 1},
+		{Query: "{ b }", SchemaID: 1},
+		{Query: "{ c }", SchemaID: 1},
+	}
+	documents := []*graphql.CachedDocument{{}, {}, {}}
+
+	cache.Add(keys[0], documents[0])
+	cache.Add(keys[1], documents[1])
+	if document, ok := cache.Get(keys[0]); !ok || document != documents[0] {
+		t.Fatalf("Expected the first document to be cached")
+	}
+	cache.Add(keys[2], documents[2])
+
+	if _, ok := cache.Get(keys[1]); ok {
+		t.Fatalf("Expected the least recently used document to be evicted")
+	}
+	for _, i := range []int{0, 2} {
+		if document, ok := cache.Get(keys[i]); !ok || document != documents[i] {
+			t.Fatalf("Expected document %v to be cached", i)
+		}
+	}
+}
+
+func TestLRUDocumentCacheDoesNotCacheLargerDocuments(t *testing.T) {
+	cache := graphql.NewLRUDocumentCache(10)
+	small := graphql.DocumentCacheKey{Query: "{ a }", SchemaID: 1}
+	large := graphql.DocumentCacheKey{Query: "{ a b c d }", SchemaID: 1}
+
+	cache.Add(small, &graphql.CachedDocument{})
+	cache.Add(large, &graphql.CachedDocument{})
+	if _, ok := cache.Get(large); ok {
+		t.Fatalf("Expected the document larger than the cache not to be cached")
+	}
+	if _, ok := cache.Get(small); !ok {
+		t.Fatalf("Expected the small document to stay cached")
+	}
+}
+
+func TestDoReusesCachedDocuments(t *testing.T) {
+	cache := newCountingDocumentCache()
+	expected := &graphql.Result{
+		Data: map[string]interface{}{
+			"hero": map[string]interface{}{
+				"name": "R2-D2",
+			},
+		},
+	}
+	for i := 0; i < 3; i++ {
+		result := graphql.Do(graphql.Params{
+			Schema:        testutil.StarWarsSchema,
+			RequestString: `{ hero { name } }`,
+			DocumentCache: cache,
+		})
+		if !reflect.DeepEqual(expected, result) {
+			t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
+		}
+	}
+	if cache.misses != 1 || cache.hits != 2 {
+		t.Fatalf("Expected 1 miss and 2 hits, got %v misses and %v hits", cache.misses, cache.hits)
+	}
+}
+
+func TestDoRunsValidationRulesOnCachedDocuments(t *testing.T) {
+	cache := newCountingDocumentCache()
+	query := `{ hero { friends { name } } }`
+
+	result := graphql.Do(graphql.Params{
+		Schema:        testutil.StarWarsSchema,
+		RequestString: query,
+		DocumentCache: cache,
+	})
+	if result.HasErrors() {
+		t.Fatalf("Unexpected errors: %v", result.Errors)
+	}
+
+	result = graphql.Do(graphql.Params{
+		Schema:          testutil.StarWarsSchema,
+		RequestString:   query,
+		DocumentCache:   cache,
+		ValidationRules: []graphql.ValidationRuleFn{graphql.MaxDepthRule(2)},
+	})
+	if cache.hits != 1 {
+		t.Fatalf("Expected the document to be cached")
+	}
+	expectedErrors := []gqlerrors.FormattedError{
+		{
+			Message:    `Operation has a depth of 3, which exceeds the maximum depth of 2.`,
+			Extensions: map[string]interface{}{"code": gqlerrors.CodeValidationFailed, "rule": "MaxDepthRule"},
+			Locations:  []location.SourceLocation{{Line: 1, Column: 1}},
+		},
+	}
+	if !testutil.EqualFormattedErrors(expectedErrors, result.Errors) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expectedErrors, result.Errors))
+	}
+}
+
+func TestDoDoesNotCacheInvalidDocuments(t *testing.T) {
+	cache := newCountingDocumentCache()
+	for i := 0; i < 2; i++ {
+		result := graphql.Do(graphql.Params{
+			Schema:        testutil.StarWarsSchema,
+			RequestString: `{ unknown }`,
+			DocumentCache: cache,
+		})
+		if result.Data != nil || len(result.Errors) != 1 {
+			t.Fatalf("Expected a validation error, got %v", result)
+		}
+	}
+	if cache.misses != 2 || cache.hits != 0 {
+		t.Fatalf("Expected 2 misses and no hits, got %v misses and %v hits", cache.misses, cache.hits)
+	}
+}
+
+func TestDoDoesNotShareCachedDocumentsBetweenSchemas(t *testing.T) {
+	cache := newCountingDocumentCache()
+	newSchema := func(field string) graphql.Schema {
+		schema, err := graphql.NewSchema(graphql.SchemaConfig{
+			Query: graphql.NewObject(graphql.ObjectConfig{
+				Name: "Query",
+				Fields: graphql.Fields{
+					field: &graphql.Field{
+						Type: graphql.String,
+						Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+							return "ok", nil
+						},
+					},
+				},
+			}),
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		return schema
+	}
+
+	result := graphql.Do(graphql.Params{
+		Schema:        newSchema("a"),
+		RequestString: `{ a }`,
+		DocumentCache: cache,
+	})
+	if result.HasErrors() {
+		t.Fatalf("Unexpected errors: %v", result.Errors)
+	}
+
+	result = graphql.Do(graphql.Params{
+		Schema:        newSchema("b"),
+		RequestString: `{ a }`,
+		DocumentCache: cache,
+	})
+	expectedErrors := []gqlerrors.FormattedError{
+		{
+			Message:    `Cannot query field "a" on type "Query". Did you mean "b"?`,
+			Extensions: map[string]interface{}{"code": gqlerrors.CodeValidationFailed, "rule": "FieldsOnCorrectTypeRule"},
+			Locations:  []location.SourceLocation{{Line: 1, Column: 3}},
+		},
+	}
+	if !testutil.EqualFormattedErrors(expectedErrors, result.Errors) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expectedErrors, result.Errors))
+	}
+	if cache.hits != 0 {
+		t.Fatalf("Expected no cache hits, got %v", cache.hits)
+	}
+}
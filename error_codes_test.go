@@ -0,0 +1,285 @@
+package graphql_test
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/location"
+	"github.com/graphql-go/graphql/testutil"
+)
+
+type forbiddenError struct{}
+
+func (forbiddenError) Error() string {
+	return "forbidden"
+}
+
+func (forbiddenError) Extensions() map[string]interface{} {
+	return map[string]interface{}{"code": "FORBIDDEN"}
+}
+
+func newErrorCodesSchema(t *testing.T) graphql.Schema {
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Query",
+			Fields: graphql.Fields{
+				"echo": &graphql.Field{
+					Type: graphql.Int,
+					Args: graphql.FieldConfigArgument{
+						"n": &graphql.ArgumentConfig{Type: graphql.Int},
+					},
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return p.Args["n"], nil
+					},
+				},
+				"nonNull": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.String),
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return nil, nil
+					},
+				},
+				"failing": &graphql.Field{
+					Type: graphql.String,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return nil, errors.New("failed")
+					},
+				},
+				"forbidden": &graphql.Field{
+					Type: graphql.String,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return nil, forbiddenError{}
+					},
+				},
+				"failingNonNull": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.String),
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return nil, errors.New("failed")
+					},
+				},
+				"failingThunk": &graphql.Field{
+					Type: graphql.String,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return func() (interface{}, error) {
+							return nil, errors.New("failed")
+						}, nil
+					},
+				},
+				"forbiddenThunk": &graphql.Field{
+					Type: graphql.String,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return func() (interface{}, error) {
+							return nil, forbiddenError{}
+						}, nil
+					},
+				},
+			},
+		}),
+		Subscription: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Subscription",
+			Fields: graphql.Fields{
+				"nonNull": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.String),
+					Subscribe: func(p graphql.ResolveParams) (interface{}, error) {
+						c := make(chan interface{}, 1)
+						c <- "event"
+						close(c)
+						return c, nil
+					},
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return nil, nil
+					},
+				},
+			},
+		}),
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return schema
+}
+
+func TestDoReportsTheCodesOfErrors(t *testing.T) {
+	schema := newErrorCodesSchema(t)
+	tests := map[string]struct {
+		params     graphql.Params
+		extensions map[string]interface{}
+	}{
+		"syntax error": {
+			params:     graphql.Params{RequestString: `{ echo`},
+			extensions: map[string]interface{}{"code": gqlerrors.CodeParseFailed},
+		},
+		"validation error": {
+			params: graphql.Params{RequestString: `{ unknown }`},
+			extensions: map[string]interface{}{
+				"code": gqlerrors.CodeValidationFailed,
+				"rule": "FieldsOnCorrectTypeRule",
+			},
+		},
+		"additional validation rule": {
+			params: graphql.Params{
+				RequestString:   `{ a: echo b: echo }`,
+				ValidationRules: []graphql.ValidationRuleFn{graphql.MaxAliasesRule(1)},
+			},
+			extensions: map[string]interface{}{
+				"code": gqlerrors.CodeValidationFailed,
+				"rule": "MaxAliasesRule",
+			},
+		},
+		"invalid variable": {
+			params: graphql.Params{
+				RequestString:  `query Q($n: Int) { echo(n: $n) }`,
+				VariableValues: map[string]interface{}{"n": "one"},
+			},
+			extensions: map[string]interface{}{"code": gqlerrors.CodeBadUserInput},
+		},
+		"unknown operation": {
+			params: graphql.Params{
+				RequestString: `query Q { echo }`,
+				OperationName: "Unknown",
+			},
+			extensions: map[string]interface{}{"code": gqlerrors.CodeBadUserInput},
+		},
+		"null non-null field": {
+			params:     graphql.Params{RequestString: `{ nonNull }`},
+			extensions: map[string]interface{}{"code": gqlerrors.CodeInternalServerError},
+		},
+		"resolver error": {
+			params:     graphql.Params{RequestString: `{ failing }`},
+			extensions: map[string]interface{}{"code": gqlerrors.CodeInternalServerError},
+		},
+		"resolver error with a code": {
+			params:     graphql.Params{RequestString: `{ forbidden }`},
+			extensions: map[string]interface{}{"code": "FORBIDDEN"},
+		},
+		"resolver error of a non-null field": {
+			params:     graphql.Params{RequestString: `{ failingNonNull }`},
+			extensions: map[string]interface{}{"code": gqlerrors.CodeInternalServerError},
+		},
+		"thunk error": {
+			params:     graphql.Params{RequestString: `{ failingThunk }`},
+			extensions: map[string]interface{}{"code": gqlerrors.CodeInternalServerError},
+		},
+		"thunk error with a code": {
+			params:     graphql.Params{RequestString: `{ forbiddenThunk }`},
+			extensions: map[string]interface{}{"code": "FORBIDDEN"},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			test.params.Schema = schema
+			result := graphql.Do(test.params)
+			if len(result.Errors) != 1 {
+				t.Fatalf("Expected a single error, got %v", result.Errors)
+			}
+			if !reflect.DeepEqual(test.extensions, result.Errors[0].Extensions) {
+				t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(test.extensions, result.Errors[0].Extensions))
+			}
+		})
+	}
+}
+
+func TestValidationRuleFn_Name(t *testing.T) {
+	tests := map[string]struct {
+		rule     graphql.ValidationRuleFn
+		expected string
+	}{
+		"specified rule": {
+			rule:     graphql.FieldsOnCorrectTypeRule,
+			expected: "FieldsOnCorrectTypeRule",
+		},
+		"rule returned by a function": {
+			rule:     graphql.MaxDepthRule(1),
+			expected: "MaxDepthRule",
+		},
+		"anonymous function": {
+			rule: func(context *graphql.ValidationContext) *graphql.ValidationRuleInstance {
+				return graphql.ScalarLeafsRule(context)
+			},
+			expected: "",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if name := test.rule.Name(); name != test.expected {
+				t.Fatalf("Expected %q, got %q", test.expected, name)
+			}
+		})
+	}
+}
+
+// maskInternalErrors replaces the messages of the internal errors.
+func maskInternalErrors(err gqlerrors.FormattedError) gqlerrors.FormattedError {
+	if err.Code() == gqlerrors.CodeInternalServerError || err.Code() == "" {
+		err.Message = "Internal server error"
+	}
+	return err.WithExtension("formatted", true)
+}
+
+func TestDoFormatsErrorsWithErrorFormatter(t *testing.T) {
+	var originalErrors []error
+	result := graphql.Do(graphql.Params{
+		Schema:        newErrorCodesSchema(t),
+		RequestString: `{ failing forbidden }`,
+		ErrorFormatter: func(err gqlerrors.FormattedError) gqlerrors.FormattedError {
+			originalErrors = append(originalErrors, err.OriginalError())
+			return maskInternalErrors(err)
+		},
+	})
+	expected := &graphql.Result{
+		Data: map[string]interface{}{"failing": nil, "forbidden": nil},
+		Errors: []gqlerrors.FormattedError{
+			{
+				Message:    "Internal server error",
+				Locations:  []location.SourceLocation{{Line: 1, Column: 3}},
+				Path:       []interface{}{"failing"},
+				Extensions: map[string]interface{}{"code": gqlerrors.CodeInternalServerError, "formatted": true},
+			},
+			{
+				Message:    "forbidden",
+				Locations:  []location.SourceLocation{{Line: 1, Column: 11}},
+				Path:       []interface{}{"forbidden"},
+				Extensions: map[string]interface{}{"code": "FORBIDDEN", "formatted": true},
+			},
+		},
+	}
+	sort.Slice(result.Errors, func(i, j int) bool {
+		return result.Errors[i].Locations[0].Column < result.Errors[j].Locations[0].Column
+	})
+	if !testutil.EqualResults(expected, result) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
+	}
+	if len(originalErrors) != 2 || originalErrors[0] == nil || originalErrors[1] == nil {
+		t.Fatalf("Expected the original errors to be given to the formatter, got %v", originalErrors)
+	}
+}
+
+func TestSubscribeFormatsErrorsWithErrorFormatter(t *testing.T) {
+	results := graphql.Subscribe(graphql.Params{
+		Schema:         newErrorCodesSchema(t),
+		RequestString:  `subscription { nonNull }`,
+		ErrorFormatter: maskInternalErrors,
+	})
+	count := 0
+	for result := range results {
+		count++
+		expected := []gqlerrors.FormattedError{{
+			Message:   "Internal server error",
+			Locations: []location.SourceLocation{{Line: 1, Column: 16}},
+			Path:      []interface{}{"nonNull"},
+			Extensions: map[string]interface{}{
+				"code":      gqlerrors.CodeInternalServerError,
+				"formatted": true,
+			},
+		}}
+		if !testutil.EqualFormattedErrors(expected, result.Errors) {
+			t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result.Errors))
+		}
+	}
+	if count != 1 {
+		t.Fatalf("Expected a single result, got %v", count)
+	}
+}
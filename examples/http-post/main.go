@@ -1,38 +1,18 @@
 package main
 
 import (
-	"encoding/json"
 	"fmt"
 	"net/http"
 
-	"github.com/graphql-go/graphql"
 	"github.com/graphql-go/graphql/examples/todo/schema"
+	"github.com/graphql-go/graphql/handler"
 )
 
-type postData struct {
-	Query     string                 `json:"query"`
-	Operation string                 `json:"operation"`
-	Variables map[string]interface{} `json:"variables"`
-}
-
 func main() {
-	http.HandleFunc("/graphql", func(w http.ResponseWriter, req *http.Request) {
-		var p postData
-		if err := json.NewDecoder(req.Body).Decode(&p); err != nil {
-			w.WriteHeader(400)
-			return
-		}
-		result := graphql.Do(graphql.Params{
-			Context:        req.Context(),
-			Schema:         schema.TodoSchema,
-			RequestString:  p.Query,
-			VariableValues: p.Variables,
-			OperationName:  p.Operation,
-		})
-		if err := json.NewEncoder(w).Encode(result); err != nil {
-			fmt.Printf("could not write result to response: %s", err)
-		}
-	})
+	http.Handle("/graphql", handler.New(handler.Config{
+		Schema: &schema.TodoSchema,
+		Pretty: true,
+	}))
 
 	fmt.Println("Now server is running on port 8080")
 
@@ -7,6 +7,7 @@ import (
 	"reflect"
 	"sort"
 	"strings"
+	"sync"
 
 	"github.com/graphql-go/graphql/gqlerrors"
 	"github.com/graphql-go/graphql/language/ast"
@@ -22,9 +23,21 @@ type ExecuteParams struct {
 	// Context may be provided to pass application-specific per-request
 	// information to resolve functions.
 	Context context.Context
+
+	// Concurrency is the maximum number of sibling fields of a query resolved
+	// in parallel. Fields are resolved one at a time when it is 0 or 1, and
+	// the top-level fields of mutations are always resolved one at a time.
+	// Resolvers and extensions must then be safe for concurrent use.
+	Concurrency int
 }
 
 func Execute(p ExecuteParams) (result *Result) {
+	return execute(p, nil)
+}
+
+// execute executes the operation, delivering the fragments and lists marked
+// with @defer and @stream through the given publisher if there is one.
+func execute(p ExecuteParams, publisher *incrementalPublisher) (result *Result) {
 	// Use background context if no context was provided
 	ctx := p.Context
 	if ctx == nil {
@@ -32,29 +45,39 @@ func Execute(p ExecuteParams) (result *Result) {
 	}
 	// run executionDidStart functions from extensions
 	extErrs, executionFinishFn := handleExtensionsExecutionDidStart(&p)
+
+	// the extensions which started are notified on every path
+	defer func() {
+		finish := func(result *Result) {
+			extErrs := executionFinishFn(result)
+			if len(extErrs) != 0 {
+				result.Errors = append(result.Errors, extErrs...)
+			}
+
+			addExtensionResults(p.Schema.extensions, p.Context, result)
+		}
+		// incremental executions finish with their last payload
+		if publisher != nil {
+			publisher.finish = finish
+			return
+		}
+		finish(result)
+	}()
+
 	if len(extErrs) != 0 {
 		return &Result{
 			Errors: extErrs,
 		}
 	}
 
-	defer func() {
-		extErrs = executionFinishFn(result)
-		if len(extErrs) != 0 {
-			result.Errors = append(result.Errors, extErrs...)
-		}
-
-		addExtensionResults(&p, result)
-	}()
-
 	resultChannel := make(chan *Result, 2)
 
 	go func() {
 		result := &Result{}
 
 		defer func() {
-			if err := recover(); err != nil {
-				result.Errors = append(result.Errors, gqlerrors.FormatError(err.(error)))
+			if r := recover(); r != nil {
+				result.Errors = append(result.Errors, formatRecovered(r))
 			}
 			resultChannel <- result
 		}()
@@ -67,10 +90,13 @@ func Execute(p ExecuteParams) (result *Result) {
 			Args:          p.Args,
 			Result:        result,
 			Context:       p.Context,
+			Incremental:   publisher,
+			Concurrency:   p.Concurrency,
 		})
 
 		if err != nil {
-			result.Errors = append(result.Errors, gqlerrors.FormatError(err.(error)))
+			// the operation or the variables of the request are invalid
+			result.Errors = append(result.Errors, gqlerrors.FormatError(err).WithCode(gqlerrors.CodeBadUserInput))
 			resultChannel <- result
 			return
 		}
@@ -84,8 +110,9 @@ func Execute(p ExecuteParams) (result *Result) {
 
 	select {
 	case <-ctx.Done():
+		// the abandoned execution stops invoking resolvers, as ctx is done
 		result := &Result{}
-		result.Errors = append(result.Errors, gqlerrors.FormatError(ctx.Err()))
+		result.Errors = append(result.Errors, cancelledError(ctx.Err()))
 		return result
 	case r := <-resultChannel:
 		return r
@@ -100,6 +127,8 @@ type buildExecutionCtxParams struct {
 	Args          map[string]interface{}
 	Result        *Result
 	Context       context.Context
+	Incremental   *incrementalPublisher
+	Concurrency   int
 }
 
 type executionContext struct {
@@ -110,6 +139,20 @@ type executionContext struct {
 	VariableValues map[string]interface{}
 	Errors         []gqlerrors.FormattedError
 	Context        context.Context
+
+	// Incremental is only set when executing with ExecuteIncremental, in
+	// which case IncrementalRecord is the payload being executed, or nil for
+	// the initial payload.
+	Incremental       *incrementalPublisher
+	IncrementalRecord *incrementalRecord
+
+	// dispatchers are registered by the resolvers through their context
+	dispatchers *dispatchers
+
+	// workers bounds the number of goroutines resolving fields in parallel,
+	// it is nil when fields are resolved one at a time
+	workers  chan struct{}
+	errorsMu *sync.Mutex
 }
 
 func buildExecutionContext(p buildExecutionCtxParams) (*executionContext, error) {
@@ -154,7 +197,15 @@ func buildExecutionContext(p buildExecutionCtxParams) (*executionContext, error)
 	eCtx.Root = p.Root
 	eCtx.Operation = operation
 	eCtx.VariableValues = variableValues
-	eCtx.Context = p.Context
+	eCtx.dispatchers = &dispatchers{}
+	eCtx.Context = withDispatchers(p.Context, eCtx.dispatchers)
+	eCtx.Incremental = p.Incremental
+	eCtx.errorsMu = &sync.Mutex{}
+	// payloads are delivered in the order in which they are created, so
+	// incremental delivery resolves fields one at a time
+	if p.Concurrency > 1 && p.Incremental == nil {
+		eCtx.workers = make(chan struct{}, p.Concurrency-1)
+	}
 	return eCtx, nil
 }
 
@@ -167,13 +218,17 @@ type executeOperationParams struct {
 func executeOperation(p executeOperationParams) *Result {
 	operationType, err := getOperationRootType(p.ExecutionContext.Schema, p.Operation)
 	if err != nil {
-		return &Result{Errors: gqlerrors.FormatErrors(err)}
+		return &Result{Errors: []gqlerrors.FormattedError{
+			gqlerrors.FormatError(err).WithCode(gqlerrors.CodeBadUserInput),
+		}}
 	}
 
+	deferredFragments := newDeferredFragments(p.ExecutionContext)
 	fields := collectFields(collectFieldsParams{
-		ExeContext:   p.ExecutionContext,
-		RuntimeType:  operationType,
-		SelectionSet: p.Operation.GetSelectionSet(),
+		ExeContext:        p.ExecutionContext,
+		RuntimeType:       operationType,
+		SelectionSet:      p.Operation.GetSelectionSet(),
+		DeferredFragments: deferredFragments,
 	})
 
 	executeFieldsParams := executeFieldsParams{
@@ -182,6 +237,7 @@ func executeOperation(p executeOperationParams) *Result {
 		Source:           p.Root,
 		Fields:           fields,
 	}
+	deferFragments(executeFieldsParams, deferredFragments)
 
 	if p.Operation.GetOperation() == ast.OperationTypeMutation {
 		return executeFieldsSerially(executeFieldsParams)
@@ -277,7 +333,7 @@ func executeFieldsSerially(p executeFieldsParams) *Result {
 func executeFields(p executeFieldsParams) *Result {
 	finalResults := executeSubFields(p)
 
-	dethunkMapWithBreadthFirstTraversal(finalResults)
+	dethunkMapWithBreadthFirstTraversal(p.ExecutionContext, finalResults)
 
 	return &Result{
 		Data:   finalResults,
@@ -294,6 +350,10 @@ func executeSubFields(p executeFieldsParams) map[string]interface{} {
 		p.Fields = map[string][]*ast.Field{}
 	}
 
+	if p.ExecutionContext.workers != nil && len(p.Fields) > 1 {
+		return executeSubFieldsConcurrently(p)
+	}
+
 	finalResults := make(map[string]interface{}, len(p.Fields))
 	for responseName, fieldASTs := range p.Fields {
 		fieldPath := p.Path.WithKey(responseName)
@@ -307,6 +367,55 @@ func executeSubFields(p executeFieldsParams) map[string]interface{} {
 	return finalResults
 }
 
+// executeSubFieldsConcurrently resolves the fields in parallel, as long as workers are available,
+// resolving them in the calling goroutine otherwise. A panic of a field, which nulls the parent
+// object, is raised again in the calling goroutine once all the fields are resolved.
+func executeSubFieldsConcurrently(p executeFieldsParams) map[string]interface{} {
+	eCtx := p.ExecutionContext
+	fields := orderedFields(p.Fields)
+	resolved := make([]interface{}, len(fields))
+	states := make([]resolveFieldResultState, len(fields))
+	recovered := make([]interface{}, len(fields))
+
+	resolve := func(i int) {
+		defer func() {
+			recovered[i] = recover()
+		}()
+		fieldPath := p.Path.WithKey(fields[i].responseName)
+		resolved[i], states[i] = resolveField(eCtx, p.ParentType, p.Source, fields[i].fieldASTs, fieldPath)
+	}
+
+	var wg sync.WaitGroup
+	for i := range fields {
+		select {
+		case eCtx.workers <- struct{}{}:
+			wg.Add(1)
+			go func(i int) {
+				defer func() {
+					<-eCtx.workers
+					wg.Done()
+				}()
+				resolve(i)
+			}(i)
+		default:
+			resolve(i)
+		}
+	}
+	wg.Wait()
+
+	finalResults := make(map[string]interface{}, len(fields))
+	for i, field := range fields {
+		if recovered[i] != nil {
+			panic(recovered[i])
+		}
+		if states[i].hasNoFieldDefs {
+			continue
+		}
+		finalResults[field.responseName] = resolved[i]
+	}
+	return finalResults
+}
+
 // dethunkQueue is a structure that allows us to execute a classic breadth-first traversal.
 type dethunkQueue struct {
 	DethunkFuncs []func()
@@ -316,23 +425,28 @@ func (d *dethunkQueue) push(f func()) {
 	d.DethunkFuncs = append(d.DethunkFuncs, f)
 }
 
-func (d *dethunkQueue) shift() func() {
-	f := d.DethunkFuncs[0]
-	d.DethunkFuncs = d.DethunkFuncs[1:]
-	return f
+// run calls the queued functions a level at a time. Before each level, the work batched by the
+// resolvers, such as the loads of a dataloader, is dispatched, so that the thunks of the level
+// await batches holding the loads of all their siblings.
+func (d *dethunkQueue) run(eCtx *executionContext) {
+	for len(d.DethunkFuncs) > 0 {
+		level := d.DethunkFuncs
+		d.DethunkFuncs = []func(){}
+		eCtx.dispatchers.dispatch()
+		for _, f := range level {
+			f()
+		}
+	}
 }
 
 // dethunkWithBreadthFirstTraversal performs a breadth-first descent of the map, calling any thunks
 // in the map values and replacing each thunk with that thunk's return value. This parallels
 // the reference graphql-js implementation, which calls Promise.all on thunks at each depth (which
 // is an implicit parallel descent).
-func dethunkMapWithBreadthFirstTraversal(finalResults map[string]interface{}) {
+func dethunkMapWithBreadthFirstTraversal(eCtx *executionContext, finalResults map[string]interface{}) {
 	dethunkQueue := &dethunkQueue{DethunkFuncs: []func(){}}
-	dethunkMapBreadthFirst(finalResults, dethunkQueue)
-	for len(dethunkQueue.DethunkFuncs) > 0 {
-		f := dethunkQueue.shift()
-		f()
-	}
+	dethunkQueue.push(func() { dethunkMapBreadthFirst(finalResults, dethunkQueue) })
+	dethunkQueue.run(eCtx)
 }
 
 func dethunkMapBreadthFirst(m map[string]interface{}, dethunkQueue *dethunkQueue) {
@@ -401,6 +515,9 @@ type collectFieldsParams struct {
 	SelectionSet         *ast.SelectionSet
 	Fields               map[string][]*ast.Field
 	VisitedFragmentNames map[string]bool
+	// DeferredFragments collects the fragments marked with @defer instead of
+	// their fields being added to Fields. If nil, @defer is ignored.
+	DeferredFragments *[]*deferredFragment
 }
 
 // Given a selectionSet, adds all of the fields in that selection to
@@ -443,6 +560,15 @@ func collectFields(p collectFieldsParams) (fields map[string][]*ast.Field) {
 				SelectionSet:         selection.SelectionSet,
 				Fields:               fields,
 				VisitedFragmentNames: p.VisitedFragmentNames,
+				DeferredFragments:    p.DeferredFragments,
+			}
+			if label, ok := getDeferLabel(p.ExeContext, p.DeferredFragments, selection.Directives); ok {
+				innerParams.Fields = map[string][]*ast.Field{}
+				*p.DeferredFragments = append(*p.DeferredFragments, &deferredFragment{
+					label:  label,
+					fields: collectFields(innerParams),
+				})
+				continue
 			}
 			collectFields(innerParams)
 		case *ast.FragmentSpread:
@@ -470,6 +596,15 @@ func collectFields(p collectFieldsParams) (fields map[string][]*ast.Field) {
 					SelectionSet:         fragment.GetSelectionSet(),
 					Fields:               fields,
 					VisitedFragmentNames: p.VisitedFragmentNames,
+					DeferredFragments:    p.DeferredFragments,
+				}
+				if label, ok := getDeferLabel(p.ExeContext, p.DeferredFragments, selection.Directives); ok {
+					innerParams.Fields = map[string][]*ast.Field{}
+					*p.DeferredFragments = append(*p.DeferredFragments, &deferredFragment{
+						label:  label,
+						fields: collectFields(innerParams),
+					})
+					continue
 				}
 				collectFields(innerParams)
 			}
@@ -586,7 +721,23 @@ func handleFieldError(r interface{}, fieldNodes []ast.Node, path *ResponsePath,
 	if _, ok := returnType.(*NonNull); ok {
 		panic(err)
 	}
-	eCtx.Errors = append(eCtx.Errors, gqlerrors.FormatError(err))
+	eCtx.addErrors(internalError(err))
+}
+
+// internalError formats an error of the executor or of a field, which is an
+// error of the server rather than of the request, such as a null returned for
+// a non-null field or an error returned by a resolver. Errors with a code,
+// such as a gqlerrors.ExtendedError, keep their code.
+func internalError(err error) gqlerrors.FormattedError {
+	return gqlerrors.FormatError(err).WithCode(gqlerrors.CodeInternalServerError)
+}
+
+// addErrors adds errors to the result, which may be done by several goroutines when fields are
+// resolved in parallel.
+func (eCtx *executionContext) addErrors(errs ...gqlerrors.FormattedError) {
+	eCtx.errorsMu.Lock()
+	defer eCtx.errorsMu.Unlock()
+	eCtx.Errors = append(eCtx.Errors, errs...)
 }
 
 // Resolves the field on the given source object. In particular, this
@@ -620,6 +771,8 @@ func resolveField(eCtx *executionContext, parentType *Object, source interface{}
 	if resolveFn == nil {
 		resolveFn = DefaultResolveFn
 	}
+	// the middleware runs within the timeout and the recovery of the resolver
+	resolveFn = applyFieldMiddleware(eCtx.Schema.fieldMiddleware, resolveFn)
 
 	// Build a map of arguments from the field.arguments AST, using the
 	// variables scope to fulfill any variable references.
@@ -641,21 +794,33 @@ func resolveField(eCtx *executionContext, parentType *Object, source interface{}
 
 	var resolveFnError error
 
-	extErrs, resolveFieldFinishFn := handleExtensionsResolveFieldDidStart(eCtx.Schema.extensions, eCtx, &info)
+	// stop resolving fields once the request is cancelled
+	if err := contextErr(eCtx.Context); err != nil {
+		panic(err)
+	}
+
+	extErrs, ctx, resolveFieldFinishFn := handleExtensionsResolveFieldDidStart(eCtx.Schema.extensions, eCtx, &info)
 	if len(extErrs) != 0 {
-		eCtx.Errors = append(eCtx.Errors, extErrs...)
+		eCtx.addErrors(extErrs...)
 	}
 
-	result, resolveFnError = resolveFn(ResolveParams{
+	resolveParams := ResolveParams{
 		Source:  source,
 		Args:    args,
 		Info:    info,
-		Context: eCtx.Context,
-	})
+		Context: ctx,
+	}
+	if timeout := fieldTimeout(eCtx, fieldDef, fieldAST); timeout > 0 {
+		result, resolveFnError = resolveWithTimeout(resolveFn, resolveParams, timeout)
+	} else {
+		result, resolveFnError = recoverPanic(func() (interface{}, error) {
+			return resolveFn(resolveParams)
+		})
+	}
 
 	extErrs = resolveFieldFinishFn(result, resolveFnError)
 	if len(extErrs) != 0 {
-		eCtx.Errors = append(eCtx.Errors, extErrs...)
+		eCtx.addErrors(extErrs...)
 	}
 
 	if resolveFnError != nil {
@@ -666,6 +831,20 @@ func resolveField(eCtx *executionContext, parentType *Object, source interface{}
 	return completed, resultState
 }
 
+// contextErr returns the error of the context of the execution, if it is done.
+func contextErr(ctx context.Context) error {
+	if ctx == nil || ctx.Err() == nil {
+		return nil
+	}
+	return cancelledError(ctx.Err())
+}
+
+// cancelledError formats err, the error of a done context, with the
+// REQUEST_CANCELLED code.
+func cancelledError(err error) gqlerrors.FormattedError {
+	return gqlerrors.FormatError(err).WithCode(gqlerrors.CodeRequestCancelled)
+}
+
 func completeValueCatchingError(eCtx *executionContext, returnType Type, fieldASTs []*ast.Field, info ResolveInfo, path *ResponsePath, result interface{}) (completed interface{}) {
 	// catch panic
 	defer func() interface{} {
@@ -703,7 +882,7 @@ func completeValue(eCtx *executionContext, returnType Type, fieldASTs []*ast.Fie
 				FieldASTsToNodeASTs(fieldASTs),
 				path.AsArray(),
 			)
-			panic(gqlerrors.FormatError(err))
+			panic(internalError(err))
 		}
 		return completed
 	}
@@ -746,7 +925,7 @@ func completeValue(eCtx *executionContext, returnType Type, fieldASTs []*ast.Fie
 		`Cannot complete value of unexpected type "%v."`, returnType)
 
 	if err != nil {
-		panic(gqlerrors.FormatError(err))
+		panic(internalError(err))
 	}
 	return nil
 }
@@ -763,9 +942,12 @@ func completeThunkValueCatchingError(eCtx *executionContext, returnType Type, fi
 	propertyFn, ok := result.(func() (interface{}, error))
 	if !ok {
 		err := gqlerrors.NewFormattedError("Error resolving func. Expected `func() (interface{}, error)` signature")
-		panic(gqlerrors.FormatError(err))
+		panic(internalError(err))
+	}
+	if err := contextErr(eCtx.Context); err != nil {
+		panic(err)
 	}
-	fnResult, err := propertyFn()
+	fnResult, err := recoverPanic(propertyFn)
 	if err != nil {
 		panic(gqlerrors.FormatError(err))
 	}
@@ -804,14 +986,14 @@ func completeAbstractValue(eCtx *executionContext, returnType Abstract, fieldAST
 		`for field %v.%v with value "%v", received "%v".`, returnType, info.ParentType, info.FieldName, result, runtimeType,
 	)
 	if err != nil {
-		panic(err)
+		panic(internalError(err))
 	}
 
 	if !eCtx.Schema.IsPossibleType(returnType, runtimeType) {
-		panic(gqlerrors.NewFormattedError(
+		panic(internalError(gqlerrors.NewFormattedError(
 			fmt.Sprintf(`Runtime Object type "%v" is not a possible type `+
 				`for "%v".`, runtimeType, returnType),
-		))
+		)))
 	}
 
 	return completeObjectValue(eCtx, runtimeType, fieldASTs, info, path, result)
@@ -830,15 +1012,16 @@ func completeObjectValue(eCtx *executionContext, returnType *Object, fieldASTs [
 			Context: eCtx.Context,
 		}
 		if !returnType.IsTypeOf(p) {
-			panic(gqlerrors.NewFormattedError(
+			panic(internalError(gqlerrors.NewFormattedError(
 				fmt.Sprintf(`Expected value of type "%v" but got: %T.`, returnType, result),
-			))
+			)))
 		}
 	}
 
 	// Collect sub-fields to execute to complete this value.
 	subFieldASTs := map[string][]*ast.Field{}
 	visitedFragmentNames := map[string]bool{}
+	deferredFragments := newDeferredFragments(eCtx)
 	for _, fieldAST := range fieldASTs {
 		if fieldAST == nil {
 			continue
@@ -851,6 +1034,7 @@ func completeObjectValue(eCtx *executionContext, returnType *Object, fieldASTs [
 				SelectionSet:         selectionSet,
 				Fields:               subFieldASTs,
 				VisitedFragmentNames: visitedFragmentNames,
+				DeferredFragments:    deferredFragments,
 			}
 			subFieldASTs = collectFields(innerParams)
 		}
@@ -862,6 +1046,7 @@ func completeObjectValue(eCtx *executionContext, returnType *Object, fieldASTs [
 		Fields:           subFieldASTs,
 		Path:             path,
 	}
+	deferFragments(executeFieldsParams, deferredFragments)
 	return executeSubFields(executeFieldsParams)
 }
 
@@ -890,7 +1075,13 @@ func completeListValue(eCtx *executionContext, returnType *List, fieldASTs []*as
 			"for field %v.%v.", parentTypeName, info.FieldName)
 
 	if err != nil {
-		panic(gqlerrors.FormatError(err))
+		panic(internalError(err))
+	}
+
+	// Only the list returned by the field itself is streamed, not its inner lists
+	initialCount, label, stream := -1, "", false
+	if path == info.Path {
+		initialCount, label, stream = getStreamValues(eCtx, fieldASTs)
 	}
 
 	itemType := returnType.OfType
@@ -898,6 +1089,10 @@ func completeListValue(eCtx *executionContext, returnType *List, fieldASTs []*as
 	for i := 0; i < resultVal.Len(); i++ {
 		val := resultVal.Index(i).Interface()
 		fieldPath := path.WithKey(i)
+		if stream && i >= initialCount {
+			streamItem(eCtx, label, itemType, fieldASTs, info, fieldPath, val)
+			continue
+		}
 		completedItem := completeValueCatchingError(eCtx, itemType, fieldASTs, info, fieldPath, val)
 		completedResults = append(completedResults, completedItem)
 	}
@@ -0,0 +1,210 @@
+package graphql_test
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/testutil"
+)
+
+// inFlightCounter records the maximum number of resolvers running at once.
+type inFlightCounter struct {
+	mu       sync.Mutex
+	current  int
+	max      int
+	resolved []string
+}
+
+func (c *inFlightCounter) resolve(name string, d time.Duration) (interface{}, error) {
+	c.mu.Lock()
+	c.current++
+	if c.current > c.max {
+		c.max = c.current
+	}
+	c.mu.Unlock()
+
+	time.Sleep(d)
+
+	c.mu.Lock()
+	c.current--
+	c.resolved = append(c.resolved, name)
+	c.mu.Unlock()
+	return name, nil
+}
+
+func newConcurrencySchema(t *testing.T, counter *inFlightCounter) graphql.Schema {
+	fields := graphql.Fields{}
+	for _, name := range []string{"a", "b", "c", "d", "e", "f"} {
+		name := name
+		fields[name] = &graphql.Field{
+			Type: graphql.String,
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return counter.resolve(name, 20*time.Millisecond)
+			},
+		}
+	}
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name:   "Query",
+			Fields: fields,
+		}),
+		Mutation: graphql.NewObject(graphql.ObjectConfig{
+			Name:   "Mutation",
+			Fields: fields,
+		}),
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return schema
+}
+
+func TestExecuteResolvesSiblingFieldsConcurrently(t *testing.T) {
+	tests := []struct {
+		concurrency int
+		expectedMax int
+	}{
+		{concurrency: 0, expectedMax: 1},
+		{concurrency: 1, expectedMax: 1},
+		{concurrency: 3, expectedMax: 3},
+		{concurrency: 10, expectedMax: 6},
+	}
+	for _, test := range tests {
+		counter := &inFlightCounter{}
+		result := graphql.Do(graphql.Params{
+			Schema:        newConcurrencySchema(t, counter),
+			RequestString: `{ a b c d e f }`,
+			Concurrency:   test.concurrency,
+		})
+		expected := &graphql.Result{
+			Data: map[string]interface{}{
+				"a": "a", "b": "b", "c": "c", "d": "d", "e": "e", "f": "f",
+			},
+		}
+		if !reflect.DeepEqual(expected, result) {
+			t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
+		}
+		if counter.max != test.expectedMax {
+			t.Fatalf("Expected at most %v fields resolved at once with a concurrency of %v, got %v",
+				test.expectedMax, test.concurrency, counter.max)
+		}
+	}
+}
+
+func TestExecuteResolvesMutationFieldsSerially(t *testing.T) {
+	counter := &inFlightCounter{}
+	result := graphql.Do(graphql.Params{
+		Schema:        newConcurrencySchema(t, counter),
+		RequestString: `mutation { f e d c b a }`,
+		Concurrency:   6,
+	})
+	if result.HasErrors() {
+		t.Fatalf("Unexpected errors: %v", result.Errors)
+	}
+	expectedOrder := []string{"f", "e", "d", "c", "b", "a"}
+	if counter.max != 1 || !reflect.DeepEqual(expectedOrder, counter.resolved) {
+		t.Fatalf("Expected the mutation fields to be resolved in order, got %v with %v at once",
+			counter.resolved, counter.max)
+	}
+}
+
+func TestExecuteConcurrentlyPropagatesNullsLikeSerialExecution(t *testing.T) {
+	var itemType *graphql.Object
+	itemType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "Item",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"name": &graphql.Field{
+					Type: graphql.String,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return p.Source, nil
+					},
+				},
+				"nullableError": &graphql.Field{
+					Type: graphql.String,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return nil, errors.New("nullable failed")
+					},
+				},
+				"nonNullError": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.String),
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return nil, errors.New("non-null failed")
+					},
+				},
+				"nonNullPanic": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.String),
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						panic(errors.New("non-null panicked"))
+					},
+				},
+				"child": &graphql.Field{
+					Type: itemType,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return "child", nil
+					},
+				},
+				"nonNullChild": &graphql.Field{
+					Type: graphql.NewNonNull(itemType),
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return "nonNullChild", nil
+					},
+				},
+			}
+		}),
+	})
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Query",
+			Fields: graphql.Fields{
+				"items": &graphql.Field{
+					Type: graphql.NewList(itemType),
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return []interface{}{"first", "second"}, nil
+					},
+				},
+				"item": &graphql.Field{
+					Type: itemType,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return "item", nil
+					},
+				},
+			},
+		}),
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	queries := []string{
+		`{ items { name nullableError child { name nullableError } } item { name } }`,
+		`{ items { name child { name nonNullError } } item { name nonNullChild { name nonNullPanic } } }`,
+		`{ item { name nonNullError } }`,
+	}
+	for _, query := range queries {
+		serial := graphql.Do(graphql.Params{
+			Schema:        schema,
+			RequestString: query,
+		})
+		concurrent := graphql.Do(graphql.Params{
+			Schema:        schema,
+			RequestString: query,
+			Concurrency:   4,
+		})
+		// the errors are added in the order in which the fields are resolved
+		for _, result := range []*graphql.Result{serial, concurrent} {
+			sort.Slice(result.Errors, func(i, j int) bool {
+				return fmt.Sprint(result.Errors[i].Path) < fmt.Sprint(result.Errors[j].Path)
+			})
+		}
+		if !testutil.EqualResults(serial, concurrent) {
+			t.Fatalf("Unexpected result for %v, Diff: %v", query, testutil.Diff(serial, concurrent))
+		}
+	}
+}
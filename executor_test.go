@@ -572,7 +572,8 @@ func TestNullsOutErrorSubtrees(t *testing.T) {
 		"syncError": nil,
 	}
 	expectedErrors := []gqlerrors.FormattedError{{
-		Message: "Error getting syncError",
+		Message:    "Error getting syncError",
+		Extensions: map[string]interface{}{"code": gqlerrors.CodeInternalServerError},
 		Locations: []location.SourceLocation{
 			{
 				Line: 3, Column: 7,
@@ -771,8 +772,9 @@ func TestThrowsIfNoOperationIsProvided(t *testing.T) {
 
 	expectedErrors := []gqlerrors.FormattedError{
 		{
-			Message:   "Must provide an operation.",
-			Locations: []location.SourceLocation{},
+			Message:    "Must provide an operation.",
+			Extensions: map[string]interface{}{"code": gqlerrors.CodeBadUserInput},
+			Locations:  []location.SourceLocation{},
 		},
 	}
 
@@ -816,8 +818,9 @@ func TestThrowsIfNoOperationNameIsProvidedWithMultipleOperations(t *testing.T) {
 
 	expectedErrors := []gqlerrors.FormattedError{
 		{
-			Message:   "Must provide operation name if query contains multiple operations.",
-			Locations: []location.SourceLocation{},
+			Message:    "Must provide operation name if query contains multiple operations.",
+			Extensions: map[string]interface{}{"code": gqlerrors.CodeBadUserInput},
+			Locations:  []location.SourceLocation{},
 		},
 	}
 
@@ -862,8 +865,9 @@ func TestThrowsIfUnknownOperationNameIsProvided(t *testing.T) {
 
 	expectedErrors := []gqlerrors.FormattedError{
 		{
-			Message:   `Unknown operation named "UnknownExample".`,
-			Locations: []location.SourceLocation{},
+			Message:    `Unknown operation named "UnknownExample".`,
+			Extensions: map[string]interface{}{"code": gqlerrors.CodeBadUserInput},
+			Locations:  []location.SourceLocation{},
 		},
 	}
 
@@ -906,12 +910,14 @@ func TestThrowsIfOperationTypeIsUnsupported(t *testing.T) {
 
 	expectedErrors := [][]gqlerrors.FormattedError{
 		{{
-			Message:   `Schema is not configured for mutations`,
-			Locations: []location.SourceLocation{{Line: 1, Column: 1}},
+			Message:    `Schema is not configured for mutations`,
+			Extensions: map[string]interface{}{"code": gqlerrors.CodeBadUserInput},
+			Locations:  []location.SourceLocation{{Line: 1, Column: 1}},
 		}},
 		{{
-			Message:   `Schema is not configured for subscriptions`,
-			Locations: []location.SourceLocation{{Line: 1, Column: 20}},
+			Message:    `Schema is not configured for subscriptions`,
+			Extensions: map[string]interface{}{"code": gqlerrors.CodeBadUserInput},
+			Locations:  []location.SourceLocation{{Line: 1, Column: 20}},
 		}},
 	}
 
@@ -1403,7 +1409,8 @@ func TestFailsWhenAnIsTypeOfCheckIsNotMet(t *testing.T) {
 			},
 		},
 		Errors: []gqlerrors.FormattedError{{
-			Message: `Expected value of type "SpecialType" but got: graphql_test.testNotSpecialType.`,
+			Message:    `Expected value of type "SpecialType" but got: graphql_test.testNotSpecialType.`,
+			Extensions: map[string]interface{}{"code": gqlerrors.CodeInternalServerError},
 			Locations: []location.SourceLocation{
 				{
 					Line:   1,
@@ -1478,8 +1485,9 @@ func TestFailsToExecuteQueryContainingATypeDefinition(t *testing.T) {
 		Data: nil,
 		Errors: []gqlerrors.FormattedError{
 			{
-				Message:   "GraphQL cannot execute a request containing a ObjectDefinition",
-				Locations: []location.SourceLocation{},
+				Message:    "GraphQL cannot execute a request containing a ObjectDefinition",
+				Extensions: map[string]interface{}{"code": gqlerrors.CodeBadUserInput},
+				Locations:  []location.SourceLocation{},
 			},
 		},
 	}
@@ -1862,8 +1870,9 @@ func TestContextDeadline(t *testing.T) {
 	acceptableDelay := time.Millisecond * time.Duration(10)
 	expectedErrors := []gqlerrors.FormattedError{
 		{
-			Message:   context.DeadlineExceeded.Error(),
-			Locations: []location.SourceLocation{},
+			Message:    context.DeadlineExceeded.Error(),
+			Locations:  []location.SourceLocation{},
+			Extensions: map[string]interface{}{"code": gqlerrors.CodeRequestCancelled},
 		},
 	}
 
@@ -2235,7 +2244,8 @@ func TestQuery_ErrorPath(t *testing.T) {
 		{
 		  "message": "Name for character with ID 1002 could not be fetched.",
 		  "locations": [ { "line": 6, "column": 7 } ],
-		  "path": [ "hero", "heroFriends", 1, "name" ]
+		  "path": [ "hero", "heroFriends", 1, "name" ],
+		  "extensions": { "code": "INTERNAL_SERVER_ERROR" }
 		}
 	  ],
 	  "data": {
@@ -2269,7 +2279,8 @@ func TestQuery_ErrorPathForNonNullField(t *testing.T) {
 		{
 		  "message": "Name for character with ID 1002 could not be fetched.",
 		  "locations": [ { "line": 6, "column": 7 } ],
-		  "path": [ "hero", "heroFriends", 1, "name" ]
+		  "path": [ "hero", "heroFriends", 1, "name" ],
+		  "extensions": { "code": "INTERNAL_SERVER_ERROR" }
 		}
 	  ],
 	  "data": {
@@ -0,0 +1,546 @@
+package graphql
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/kinds"
+)
+
+// ExtendSchema returns a new schema that extends the given schema with the
+// type definitions, type extensions and directive definitions of the given
+// document.
+//
+// New fields may be added to objects and interfaces, new members to unions,
+// new values to enums and new fields to input objects. The types of the given
+// schema keep their resolvers and are copied rather than modified, so the
+// given schema is left untouched.
+func ExtendSchema(schema Schema, documentAST *ast.Document) (Schema, error) {
+	if documentAST == nil || documentAST.Kind != kinds.Document {
+		return Schema{}, errors.New("Must provide valid Document AST.")
+	}
+
+	stdTypeMap := map[string]Type{}
+	for _, ttype := range append(GetIntrospectionTypes(), getSpecifiedScalarTypes()...) {
+		stdTypeMap[ttype.Name()] = ttype
+	}
+
+	builder := newSchemaConfigBuilder(stdTypeMap)
+	extender := schemaExtender{
+		schema:   schema,
+		builder:  &builder,
+		typeDefs: map[string]NamedTypeDefinition{},
+	}
+
+	typeDefs := []NamedTypeDefinition{}
+	directiveDefs := []*ast.DirectiveDefinition{}
+	for _, def := range documentAST.Definitions {
+		switch node := def.(type) {
+		case *ast.SchemaDefinition:
+			return Schema{}, errors.New("Cannot define a new schema within a schema extension.")
+		case *ast.TypeExtensionDefinition, *ast.InterfaceExtensionDefinition, *ast.UnionExtensionDefinition,
+			*ast.EnumExtensionDefinition, *ast.InputObjectExtensionDefinition:
+			builder.addTypeExtension(node)
+		case *ast.ObjectDefinition, *ast.InterfaceDefinition, *ast.UnionDefinition, *ast.EnumDefinition,
+			*ast.ScalarDefinition, *ast.InputObjectDefinition:
+			typeDef := node.(NamedTypeDefinition)
+			name := typeDef.GetName().Value
+			if schema.Type(name) != nil {
+				return Schema{}, fmt.Errorf(`Type "%v" already exists in the schema. It cannot also be defined in this type definition.`, name)
+			}
+			extender.typeDefs[name] = typeDef
+			typeDefs = append(typeDefs, typeDef)
+		case *ast.DirectiveDefinition:
+			name := node.Name.Value
+			if schema.Directive(name) != nil {
+				return Schema{}, fmt.Errorf(`Directive "%v" already exists in the schema. It cannot be redefined.`, name)
+			}
+			directiveDefs = append(directiveDefs, node)
+		}
+	}
+
+	if len(typeDefs) == 0 && !builder.hasTypeExtensions() && len(directiveDefs) == 0 {
+		return schema, nil
+	}
+
+	if err := extender.checkExtensions(); err != nil {
+		return Schema{}, err
+	}
+
+	config, err := extender.buildSchemaConfig(typeDefs, directiveDefs)
+	if err != nil {
+		return Schema{}, err
+	}
+	return newSchemaFromThunks(*config)
+}
+
+// schemaExtender copies the types of an existing schema, applying the type
+// extensions collected by the builder. Types defined in the extension
+// document are built by the builder, which resolves type references against
+// the copied types.
+type schemaExtender struct {
+	schema   Schema
+	builder  *SchemaConfigBuilder
+	typeDefs map[string]NamedTypeDefinition
+}
+
+// checkExtensions verifies that every extension targets a type of the same
+// kind, either in the existing schema or in the extension document, and that
+// it does not redefine fields or values of an existing type.
+func (e *schemaExtender) checkExtensions() error {
+	for name, extensions := range e.builder.typeExtensionsMap {
+		fields := FieldDefinitionMap{}
+		if ttype, ok := e.schema.Type(name).(*Object); ok {
+			fields = ttype.Fields()
+		} else if err := e.checkExtendedType(name, kinds.ObjectDefinition); err != nil {
+			return err
+		}
+		for _, extension := range extensions {
+			for _, field := range extension.Definition.Fields {
+				if _, ok := fields[field.Name.Value]; ok {
+					return fieldAlreadyExistsError(name, field.Name.Value)
+				}
+			}
+		}
+	}
+	for name, extensions := range e.builder.interfaceExtensionsMap {
+		fields := FieldDefinitionMap{}
+		if ttype, ok := e.schema.Type(name).(*Interface); ok {
+			fields = ttype.Fields()
+		} else if err := e.checkExtendedType(name, kinds.InterfaceDefinition); err != nil {
+			return err
+		}
+		for _, extension := range extensions {
+			for _, field := range extension.Definition.Fields {
+				if _, ok := fields[field.Name.Value]; ok {
+					return fieldAlreadyExistsError(name, field.Name.Value)
+				}
+			}
+		}
+	}
+	for name := range e.builder.unionExtensionsMap {
+		if _, ok := e.schema.Type(name).(*Union); !ok {
+			if err := e.checkExtendedType(name, kinds.UnionDefinition); err != nil {
+				return err
+			}
+		}
+	}
+	for name, extensions := range e.builder.enumExtensionsMap {
+		values := map[string]bool{}
+		if ttype, ok := e.schema.Type(name).(*Enum); ok {
+			for _, value := range ttype.Values() {
+				values[value.Name] = true
+			}
+		} else if err := e.checkExtendedType(name, kinds.EnumDefinition); err != nil {
+			return err
+		}
+		for _, extension := range extensions {
+			for _, value := range extension.Definition.Values {
+				if values[value.Name.Value] {
+					return fmt.Errorf(`Enum value "%v.%v" already exists in the schema. It cannot also be defined in this type extension.`, name, value.Name.Value)
+				}
+			}
+		}
+	}
+	for name, extensions := range e.builder.inputObjectExtensionsMap {
+		fields := InputObjectFieldMap{}
+		if ttype, ok := e.schema.Type(name).(*InputObject); ok {
+			fields = ttype.Fields()
+		} else if err := e.checkExtendedType(name, kinds.InputObjectDefinition); err != nil {
+			return err
+		}
+		for _, extension := range extensions {
+			for _, field := range extension.Definition.Fields {
+				if _, ok := fields[field.Name.Value]; ok {
+					return fieldAlreadyExistsError(name, field.Name.Value)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// checkExtendedType verifies that a type which is not a type of the expected
+// kind in the existing schema is defined with that kind in the extension
+// document.
+func (e *schemaExtender) checkExtendedType(name string, kind string) error {
+	if ttype := e.schema.Type(name); ttype != nil {
+		return fmt.Errorf(`Cannot extend non-%v type "%v".`, kindDescription(kind), name)
+	}
+	typeDef, ok := e.typeDefs[name]
+	if !ok {
+		return fmt.Errorf(`Cannot extend type "%v" because it does not exist in the existing schema.`, name)
+	}
+	if typeDef.GetKind() != kind {
+		return fmt.Errorf(`Cannot extend non-%v type "%v".`, kindDescription(kind), name)
+	}
+	return nil
+}
+
+func kindDescription(kind string) string {
+	switch kind {
+	case kinds.ObjectDefinition:
+		return "object"
+	case kinds.InterfaceDefinition:
+		return "interface"
+	case kinds.UnionDefinition:
+		return "union"
+	case kinds.EnumDefinition:
+		return "enum"
+	case kinds.InputObjectDefinition:
+		return "input object"
+	}
+	return kind
+}
+
+func fieldAlreadyExistsError(typeName string, fieldName string) error {
+	return fmt.Errorf(`Field "%v.%v" already exists in the schema. It cannot also be defined in this type extension.`, typeName, fieldName)
+}
+
+func (e *schemaExtender) buildSchemaConfig(typeDefs []NamedTypeDefinition, directiveDefs []*ast.DirectiveDefinition) (*SchemaConfig, error) {
+	typeMap := e.builder.typeMap
+
+	// Unions take their possible types as a list of already built objects,
+	// so they are copied and built once all the other types are known.
+	unions := []*Union{}
+	for name, ttype := range e.schema.TypeMap() {
+		if _, ok := e.builder.stdTypeMap[name]; ok {
+			continue
+		}
+		if union, ok := ttype.(*Union); ok {
+			unions = append(unions, union)
+			continue
+		}
+		typeMap[name] = e.extendType(ttype)
+	}
+	unionDefs := []NamedTypeDefinition{}
+	for _, typeDef := range typeDefs {
+		if typeDef.GetKind() == kinds.UnionDefinition {
+			unionDefs = append(unionDefs, typeDef)
+			continue
+		}
+		if err := e.buildType(typeDef); err != nil {
+			return nil, err
+		}
+	}
+	for _, union := range unions {
+		extendedUnion, err := e.extendUnion(union)
+		if err != nil {
+			return nil, err
+		}
+		typeMap[union.Name()] = extendedUnion
+	}
+	for _, typeDef := range unionDefs {
+		if err := e.buildType(typeDef); err != nil {
+			return nil, err
+		}
+	}
+
+	typeNames := []string{}
+	for name := range typeMap {
+		typeNames = append(typeNames, name)
+	}
+	sort.Strings(typeNames)
+
+	schemaConfig := SchemaConfig{
+		Types:      []Type{},
+		Directives: []*Directive{},
+		Extensions: e.schema.extensions,
+
+		FieldMiddleware: e.schema.fieldMiddleware,
+	}
+	for _, name := range typeNames {
+		schemaConfig.Types = append(schemaConfig.Types, typeMap[name])
+	}
+	if queryType := e.schema.QueryType(); queryType != nil {
+		schemaConfig.Query = typeMap[queryType.Name()].(*Object)
+	}
+	if mutationType := e.schema.MutationType(); mutationType != nil {
+		schemaConfig.Mutation = typeMap[mutationType.Name()].(*Object)
+	}
+	if subscriptionType := e.schema.SubscriptionType(); subscriptionType != nil {
+		schemaConfig.Subscription = typeMap[subscriptionType.Name()].(*Object)
+	}
+
+	for _, directive := range e.schema.Directives() {
+		schemaConfig.Directives = append(schemaConfig.Directives, e.extendDirective(directive))
+	}
+	for _, directiveDef := range directiveDefs {
+		directive, err := e.builder.buildDirective(directiveDef)
+		if err != nil {
+			return nil, err
+		}
+		schemaConfig.Directives = append(schemaConfig.Directives, directive)
+	}
+
+	return &schemaConfig, nil
+}
+
+func (e *schemaExtender) buildType(typeDef NamedTypeDefinition) error {
+	builtType, err := e.builder.buildType(typeDef)
+	if err != nil {
+		return err
+	}
+	ttype, ok := builtType.(Type)
+	if !ok {
+		return fmt.Errorf("SchemaConfigBuilder.buildType did not return a Type: %v", builtType)
+	}
+	e.builder.typeMap[typeDef.GetName().Value] = ttype
+	return nil
+}
+
+// extendType returns a copy of the given named type, with its extensions
+// applied. Scalars, and enums without extensions, do not reference other
+// types and are returned as is.
+func (e *schemaExtender) extendType(ttype Type) Type {
+	switch ttype := ttype.(type) {
+	case *Object:
+		return e.extendObject(ttype)
+	case *Interface:
+		return e.extendInterface(ttype)
+	case *Enum:
+		return e.extendEnum(ttype)
+	case *InputObject:
+		return e.extendInputObject(ttype)
+	}
+	return ttype
+}
+
+func (e *schemaExtender) extendObject(ttype *Object) *Object {
+	name := ttype.Name()
+	return NewObject(ObjectConfig{
+		Name:        name,
+		Description: ttype.Description(),
+		IsTypeOf:    ttype.IsTypeOf,
+		Interfaces: InterfacesThunk(func() []*Interface {
+			namedInterfaces := []*ast.Named{}
+			for _, extension := range e.builder.typeExtensionsMap[name] {
+				namedInterfaces = append(namedInterfaces, extension.Definition.Interfaces...)
+			}
+			return e.extendInterfaces(ttype.Interfaces(), namedInterfaces)
+		}),
+		Fields: FieldsThunk(func() Fields {
+			fieldDefs := []*ast.FieldDefinition{}
+			for _, extension := range e.builder.typeExtensionsMap[name] {
+				fieldDefs = append(fieldDefs, extension.Definition.Fields...)
+			}
+			return e.extendFields(name, ttype.Fields(), fieldDefs)
+		}),
+	})
+}
+
+func (e *schemaExtender) extendInterface(ttype *Interface) *Interface {
+	name := ttype.Name()
+	return NewInterface(InterfaceConfig{
+		Name:        name,
+		Description: ttype.Description(),
+		ResolveType: e.extendResolveType(ttype.ResolveType),
+		Interfaces: InterfacesThunk(func() []*Interface {
+			namedInterfaces := []*ast.Named{}
+			for _, extension := range e.builder.interfaceExtensionsMap[name] {
+				namedInterfaces = append(namedInterfaces, extension.Definition.Interfaces...)
+			}
+			return e.extendInterfaces(ttype.Interfaces(), namedInterfaces)
+		}),
+		Fields: FieldsThunk(func() Fields {
+			fieldDefs := []*ast.FieldDefinition{}
+			for _, extension := range e.builder.interfaceExtensionsMap[name] {
+				fieldDefs = append(fieldDefs, extension.Definition.Fields...)
+			}
+			return e.extendFields(name, ttype.Fields(), fieldDefs)
+		}),
+	})
+}
+
+// extendInterfaces returns the extended copies of the given interfaces,
+// followed by the interfaces added by extensions.
+func (e *schemaExtender) extendInterfaces(interfaces []*Interface, namedInterfaces []*ast.Named) []*Interface {
+	extendedInterfaces := []*Interface{}
+	for _, iface := range interfaces {
+		if extendedInterface, ok := e.getType(iface).(*Interface); ok {
+			extendedInterfaces = append(extendedInterfaces, extendedInterface)
+		}
+	}
+	for _, namedInterface := range namedInterfaces {
+		iface, err := e.builder.getNamedType(namedInterface.Name.Value)
+		if err != nil {
+			// InterfaceThunks do not return errors, so panic here
+			panic(err)
+		}
+		if extendedInterface, ok := iface.(*Interface); ok {
+			extendedInterfaces = append(extendedInterfaces, extendedInterface)
+		}
+	}
+	return extendedInterfaces
+}
+
+func (e *schemaExtender) extendUnion(ttype *Union) (*Union, error) {
+	types := []*Object{}
+	for _, possibleType := range ttype.Types() {
+		if extendedType, ok := e.getType(possibleType).(*Object); ok {
+			types = append(types, extendedType)
+		}
+	}
+	for _, extension := range e.builder.unionExtensionsMap[ttype.Name()] {
+		for _, namedType := range extension.Definition.Types {
+			possibleType, err := e.builder.getNamedType(namedType.Name.Value)
+			if err != nil {
+				return nil, err
+			}
+			objectType, ok := possibleType.(*Object)
+			if !ok {
+				return nil, fmt.Errorf(`Union type "%v" is not an Object`, namedType.Name.Value)
+			}
+			types = append(types, objectType)
+		}
+	}
+	return NewUnion(UnionConfig{
+		Name:        ttype.Name(),
+		Description: ttype.Description(),
+		Types:       types,
+		ResolveType: e.extendResolveType(ttype.ResolveType),
+	}), nil
+}
+
+func (e *schemaExtender) extendEnum(ttype *Enum) *Enum {
+	extensions := e.builder.enumExtensionsMap[ttype.Name()]
+	if len(extensions) == 0 {
+		return ttype
+	}
+	values := EnumValueConfigMap{}
+	for _, value := range ttype.Values() {
+		values[value.Name] = &EnumValueConfig{
+			Value:             value.Value,
+			Description:       value.Description,
+			DeprecationReason: value.DeprecationReason,
+		}
+	}
+	for _, extension := range extensions {
+		for _, value := range extension.Definition.Values {
+			description := ""
+			if value.Description != nil {
+				description = value.Description.Value
+			}
+			values[value.Name.Value] = &EnumValueConfig{
+				Description:       description,
+				DeprecationReason: getDeprecationReason(DefinitionWithDirectives{Directives: value.Directives}),
+			}
+		}
+	}
+	return NewEnum(EnumConfig{
+		Name:        ttype.Name(),
+		Description: ttype.Description(),
+		Values:      values,
+	})
+}
+
+func (e *schemaExtender) extendInputObject(ttype *InputObject) *InputObject {
+	name := ttype.Name()
+	return NewInputObject(InputObjectConfig{
+		Name:        name,
+		Description: ttype.Description(),
+		Fields: InputObjectConfigFieldMapThunk(func() InputObjectConfigFieldMap {
+			fields := InputObjectConfigFieldMap{}
+			for fieldName, field := range ttype.Fields() {
+				fields[fieldName] = &InputObjectFieldConfig{
+					Type:         e.getType(field.Type).(Input),
+					DefaultValue: field.DefaultValue,
+					Description:  field.Description(),
+				}
+			}
+			for _, extension := range e.builder.inputObjectExtensionsMap[name] {
+				for fieldName, field := range e.builder.buildInputFieldMap(extension.Definition.Fields) {
+					fields[fieldName] = field
+				}
+			}
+			return fields
+		}),
+	})
+}
+
+// extendFields copies the given field definitions, keeping their resolvers,
+// and adds the fields defined by the given extension field definitions.
+func (e *schemaExtender) extendFields(typeName string, fieldMap FieldDefinitionMap, fieldDefs []*ast.FieldDefinition) Fields {
+	fields := Fields{}
+	for name, field := range fieldMap {
+		fields[name] = &Field{
+			Name:              field.Name,
+			Type:              e.getType(field.Type).(Output),
+			Args:              e.extendArgs(field.Args),
+			Resolve:           field.Resolve,
+			Subscribe:         field.Subscribe,
+			DeprecationReason: field.DeprecationReason,
+			Description:       field.Description,
+			Directives:        field.Directives,
+			Timeout:           field.Timeout,
+		}
+	}
+	extensionFields, err := e.builder.buildFieldMap(typeName, fieldDefs)
+	if err != nil {
+		// FieldThunks do not return errors, so panic here
+		panic(err)
+	}
+	for name, field := range extensionFields {
+		fields[name] = field
+	}
+	return fields
+}
+
+func (e *schemaExtender) extendArgs(args []*Argument) FieldConfigArgument {
+	argMap := FieldConfigArgument{}
+	for _, arg := range args {
+		argMap[arg.Name()] = &ArgumentConfig{
+			Type:         e.getType(arg.Type).(Input),
+			DefaultValue: arg.DefaultValue,
+			Description:  arg.Description(),
+		}
+	}
+	return argMap
+}
+
+func (e *schemaExtender) extendDirective(directive *Directive) *Directive {
+	if isSpecifiedDirective(directive) {
+		return directive
+	}
+	return NewDirective(DirectiveConfig{
+		Name:        directive.Name,
+		Description: directive.Description,
+		Locations:   directive.Locations,
+		Args:        e.extendArgs(directive.Args),
+	})
+}
+
+// extendResolveType wraps the given ResolveTypeFn so that objects of the
+// original schema are mapped to their copies in the extended schema.
+func (e *schemaExtender) extendResolveType(resolveType ResolveTypeFn) ResolveTypeFn {
+	if resolveType == nil {
+		return nil
+	}
+	return func(p ResolveTypeParams) *Object {
+		objectType := resolveType(p)
+		if objectType == nil {
+			return nil
+		}
+		if extendedType, ok := e.builder.typeMap[objectType.Name()].(*Object); ok {
+			return extendedType
+		}
+		return objectType
+	}
+}
+
+// getType returns the copy of the given, possibly wrapped, type in the
+// extended schema.
+func (e *schemaExtender) getType(ttype Type) Type {
+	switch ttype := ttype.(type) {
+	case *List:
+		return NewList(e.getType(ttype.OfType))
+	case *NonNull:
+		return NewNonNull(e.getType(ttype.OfType))
+	}
+	if extendedType, ok := e.builder.typeMap[ttype.Name()]; ok {
+		return extendedType
+	}
+	return ttype
+}
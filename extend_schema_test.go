@@ -0,0 +1,263 @@
+package graphql_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/testutil"
+)
+
+type extendSchemaDog struct {
+	Name string
+}
+
+func extendTestSchema(t *testing.T) graphql.Schema {
+	colorType := graphql.NewEnum(graphql.EnumConfig{
+		Name: "Color",
+		Values: graphql.EnumValueConfigMap{
+			"RED": &graphql.EnumValueConfig{Value: 0},
+		},
+	})
+	filterType := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "Filter",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"color": &graphql.InputObjectFieldConfig{Type: colorType},
+		},
+	})
+	var dogType *graphql.Object
+	petType := graphql.NewInterface(graphql.InterfaceConfig{
+		Name: "Pet",
+		Fields: graphql.Fields{
+			"name": &graphql.Field{Type: graphql.String},
+		},
+		ResolveType: func(p graphql.ResolveTypeParams) *graphql.Object {
+			return dogType
+		},
+	})
+	dogType = graphql.NewObject(graphql.ObjectConfig{
+		Name:       "Dog",
+		Interfaces: []*graphql.Interface{petType},
+		Fields: graphql.Fields{
+			"name": &graphql.Field{Type: graphql.String},
+		},
+	})
+	searchResultType := graphql.NewUnion(graphql.UnionConfig{
+		Name:  "SearchResult",
+		Types: []*graphql.Object{dogType},
+		ResolveType: func(p graphql.ResolveTypeParams) *graphql.Object {
+			return dogType
+		},
+	})
+	queryType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"pet": &graphql.Field{
+				Type: petType,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return extendSchemaDog{Name: "Odie"}, nil
+				},
+			},
+			"search": &graphql.Field{
+				Type: graphql.NewList(searchResultType),
+				Args: graphql.FieldConfigArgument{
+					"filter": &graphql.ArgumentConfig{Type: filterType},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return []interface{}{extendSchemaDog{Name: "Odie"}}, nil
+				},
+			},
+		},
+	})
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: queryType,
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return schema
+}
+
+func extendSchema(t *testing.T, schema graphql.Schema, sdl string) (graphql.Schema, error) {
+	doc, err := parser.Parse(parser.ParseParams{Source: sdl})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return graphql.ExtendSchema(schema, doc)
+}
+
+func TestExtendSchema_ReturnsSameSchemaWithoutExtensions(t *testing.T) {
+	schema := extendTestSchema(t)
+	extendedSchema, err := extendSchema(t, schema, `{ pet { name } }`)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expectPrinted(t, graphql.PrintSchema(extendedSchema), graphql.PrintSchema(schema))
+}
+
+func TestExtendSchema_ExtendsTypesWithoutAlteringOriginalSchema(t *testing.T) {
+	schema := extendTestSchema(t)
+	original := graphql.PrintSchema(schema)
+
+	extendedSchema, err := extendSchema(t, schema, `
+		extend type Query {
+			cats: [Cat]
+		}
+
+		extend type Dog implements Node {
+			id: ID!
+			barks: Boolean
+			nickname: String
+		}
+
+		extend interface Pet {
+			nickname: String
+		}
+
+		extend union SearchResult = Cat
+
+		extend enum Color {
+			GREEN @deprecated(reason: "Too green")
+		}
+
+		extend input Filter {
+			limit: Int = 10
+		}
+
+		interface Node {
+			id: ID!
+		}
+
+		type Cat implements Pet {
+			name: String
+			nickname: String
+		}
+
+		directive @cached(ttl: Int) on FIELD_DEFINITION
+	`)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expectPrinted(t, graphql.PrintSchema(schema), original)
+	expectPrinted(t, graphql.PrintSchema(extendedSchema), `directive @cached(ttl: Int) on FIELD_DEFINITION
+
+type Cat implements Pet {
+  name: String
+  nickname: String
+}
+
+enum Color {
+  GREEN @deprecated(reason: "Too green")
+  RED
+}
+
+type Dog implements Pet & Node {
+  barks: Boolean
+  id: ID!
+  name: String
+  nickname: String
+}
+
+input Filter {
+  color: Color
+  limit: Int = 10
+}
+
+interface Node {
+  id: ID!
+}
+
+interface Pet {
+  name: String
+  nickname: String
+}
+
+type Query {
+  cats: [Cat]
+  pet: Pet
+  search(filter: Filter): [SearchResult]
+}
+
+union SearchResult = Dog | Cat
+`)
+}
+
+func TestExtendSchema_KeepsResolversOfExtendedTypes(t *testing.T) {
+	extendedSchema, err := extendSchema(t, extendTestSchema(t), `
+		extend type Dog {
+			barks: Boolean
+		}
+	`)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	result := graphql.Do(graphql.Params{
+		Schema:        extendedSchema,
+		RequestString: `{ pet { name ... on Dog { barks } } search(filter: {color: RED}) { ... on Dog { name } } }`,
+	})
+	if len(result.Errors) > 0 {
+		t.Fatalf("Unexpected errors: %v", result.Errors)
+	}
+	expected := map[string]interface{}{
+		"pet": map[string]interface{}{
+			"name":  "Odie",
+			"barks": nil,
+		},
+		"search": []interface{}{
+			map[string]interface{}{
+				"name": "Odie",
+			},
+		},
+	}
+	if !reflect.DeepEqual(result.Data, expected) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result.Data))
+	}
+}
+
+func TestExtendSchema_RejectsInvalidExtensions(t *testing.T) {
+	tests := []struct {
+		sdl      string
+		expected string
+	}{
+		{
+			`extend type Unknown { field: String }`,
+			`Cannot extend type "Unknown" because it does not exist in the existing schema.`,
+		},
+		{
+			`extend interface Dog { field: String }`,
+			`Cannot extend non-interface type "Dog".`,
+		},
+		{
+			`extend type Dog { name: String }`,
+			`Field "Dog.name" already exists in the schema. It cannot also be defined in this type extension.`,
+		},
+		{
+			`extend enum Color { RED }`,
+			`Enum value "Color.RED" already exists in the schema. It cannot also be defined in this type extension.`,
+		},
+		{
+			`type Dog { field: String }`,
+			`Type "Dog" already exists in the schema. It cannot also be defined in this type definition.`,
+		},
+		{
+			`directive @include(if: Boolean!) on FIELD`,
+			`Directive "include" already exists in the schema. It cannot be redefined.`,
+		},
+		{
+			`extend type Dog { owner: Unknown }`,
+			`Unknown type: "Unknown"`,
+		},
+	}
+	for _, test := range tests {
+		_, err := extendSchema(t, extendTestSchema(t), test.sdl)
+		if err == nil {
+			t.Fatalf("Expected error for %v", test.sdl)
+		}
+		if err.Error() != test.expected {
+			t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(test.expected, err.Error()))
+		}
+	}
+}
@@ -29,7 +29,10 @@ type (
 	resolveFieldFinishFuncHandler func(interface{}, error) []gqlerrors.FormattedError
 )
 
-// Extension is an interface for extensions in graphql
+// Extension is an interface for extensions in graphql.
+// The hooks of the extensions are called in the order of their registration. The finish
+// func of every hook which started is called, and the results of the extensions are added
+// to the result, whether the request is executed or fails early, e.g. with a syntax error.
 type Extension interface {
 	// Init is used to help you initialize the extension
 	Init(context.Context, *Params) context.Context
@@ -56,196 +59,150 @@ type Extension interface {
 	GetResult(context.Context) interface{}
 }
 
+// startedExtension is an extension whose hook started, with the call of the
+// finish func it returned.
+type startedExtension struct {
+	name   string
+	finish func()
+}
+
+// callExtension calls f, the given hook of an extension, returning the
+// error of its panic if any.
+func callExtension(name, hook string, f func()) (err *gqlerrors.FormattedError) {
+	defer func() {
+		if r := recover(); r != nil {
+			extErr := internalError(fmt.Errorf("%s.%s: %v", name, hook, r))
+			err = &extErr
+		}
+	}()
+	f()
+	return nil
+}
+
+// startExtensions calls the given hook of each extension, in the order of
+// their registration, with start, which returns the call of the finish func
+// of the hook. It returns the extensions which started, in the same order,
+// and the errors of the extensions which panicked.
+func startExtensions(exts []Extension, hook string, start func(Extension) func()) ([]startedExtension, gqlerrors.FormattedErrors) {
+	started := make([]startedExtension, 0, len(exts))
+	errs := gqlerrors.FormattedErrors{}
+	for _, ext := range exts {
+		var finish func()
+		if err := callExtension(ext.Name(), hook, func() { finish = start(ext) }); err != nil {
+			errs = append(errs, *err)
+			continue
+		}
+		started = append(started, startedExtension{name: ext.Name(), finish: finish})
+	}
+	return started, errs
+}
+
+// finishExtensions calls the finish funcs of the started extensions, in the
+// order of their registration. It returns the errors of the finish funcs
+// which panicked.
+func finishExtensions(started []startedExtension, hook string) gqlerrors.FormattedErrors {
+	errs := gqlerrors.FormattedErrors{}
+	for _, ext := range started {
+		if err := callExtension(ext.name, hook, ext.finish); err != nil {
+			errs = append(errs, *err)
+		}
+	}
+	return errs
+}
+
 // handleExtensionsInits handles all the init functions for all the extensions in the schema
 func handleExtensionsInits(p *Params) gqlerrors.FormattedErrors {
 	errs := gqlerrors.FormattedErrors{}
 	for _, ext := range p.Schema.extensions {
-		func() {
-			// catch panic from an extension init fn
-			defer func() {
-				if r := recover(); r != nil {
-					errs = append(errs, gqlerrors.FormatError(fmt.Errorf("%s.Init: %v", ext.Name(), r.(error))))
-				}
-			}()
+		// catch panic from an extension init fn
+		if err := callExtension(ext.Name(), "Init", func() {
 			// update context
 			p.Context = ext.Init(p.Context, p)
-		}()
+		}); err != nil {
+			errs = append(errs, *err)
+		}
 	}
 	return errs
 }
 
 // handleExtensionsParseDidStart runs the ParseDidStart functions for each extension
 func handleExtensionsParseDidStart(p *Params) ([]gqlerrors.FormattedError, parseFinishFuncHandler) {
-	fs := map[string]ParseFinishFunc{}
-	errs := gqlerrors.FormattedErrors{}
-	for _, ext := range p.Schema.extensions {
-		var (
-			ctx      context.Context
-			finishFn ParseFinishFunc
-		)
-		// catch panic from an extension's parseDidStart functions
-		func() {
-			defer func() {
-				if r := recover(); r != nil {
-					errs = append(errs, gqlerrors.FormatError(fmt.Errorf("%s.ParseDidStart: %v", ext.Name(), r.(error))))
-				}
-			}()
-			ctx, finishFn = ext.ParseDidStart(p.Context)
-			// update context
-			p.Context = ctx
-			fs[ext.Name()] = finishFn
-		}()
-	}
+	var parseErr error
+	started, errs := startExtensions(p.Schema.extensions, "ParseDidStart", func(ext Extension) func() {
+		ctx, finishFn := ext.ParseDidStart(p.Context)
+		// update context
+		p.Context = ctx
+		return func() { finishFn(parseErr) }
+	})
 	return errs, func(err error) []gqlerrors.FormattedError {
-		errs := gqlerrors.FormattedErrors{}
-		for name, fn := range fs {
-			func() {
-				// catch panic from a finishFn
-				defer func() {
-					if r := recover(); r != nil {
-						errs = append(errs, gqlerrors.FormatError(fmt.Errorf("%s.ParseFinishFunc: %v", name, r.(error))))
-					}
-				}()
-				fn(err)
-			}()
-		}
-		return errs
+		parseErr = err
+		return finishExtensions(started, "ParseFinishFunc")
 	}
 }
 
 // handleExtensionsValidationDidStart notifies the extensions about the start of the validation process
 func handleExtensionsValidationDidStart(p *Params) ([]gqlerrors.FormattedError, validationFinishFuncHandler) {
-	fs := map[string]ValidationFinishFunc{}
-	errs := gqlerrors.FormattedErrors{}
-	for _, ext := range p.Schema.extensions {
-		var (
-			ctx      context.Context
-			finishFn ValidationFinishFunc
-		)
-		// catch panic from an extension's validationDidStart function
-		func() {
-			defer func() {
-				if r := recover(); r != nil {
-					errs = append(errs, gqlerrors.FormatError(fmt.Errorf("%s.ValidationDidStart: %v", ext.Name(), r.(error))))
-				}
-			}()
-			ctx, finishFn = ext.ValidationDidStart(p.Context)
-			// update context
-			p.Context = ctx
-			fs[ext.Name()] = finishFn
-		}()
-	}
+	var validationErrs []gqlerrors.FormattedError
+	started, errs := startExtensions(p.Schema.extensions, "ValidationDidStart", func(ext Extension) func() {
+		ctx, finishFn := ext.ValidationDidStart(p.Context)
+		// update context
+		p.Context = ctx
+		return func() { finishFn(validationErrs) }
+	})
 	return errs, func(errs []gqlerrors.FormattedError) []gqlerrors.FormattedError {
-		extErrs := gqlerrors.FormattedErrors{}
-		for name, finishFn := range fs {
-			func() {
-				// catch panic from a finishFn
-				defer func() {
-					if r := recover(); r != nil {
-						extErrs = append(extErrs, gqlerrors.FormatError(fmt.Errorf("%s.ValidationFinishFunc: %v", name, r.(error))))
-					}
-				}()
-				finishFn(errs)
-			}()
-		}
-		return extErrs
+		validationErrs = errs
+		return finishExtensions(started, "ValidationFinishFunc")
 	}
 }
 
 // handleExecutionDidStart handles the ExecutionDidStart functions
 func handleExtensionsExecutionDidStart(p *ExecuteParams) ([]gqlerrors.FormattedError, executionFinishFuncHandler) {
-	fs := map[string]ExecutionFinishFunc{}
-	errs := gqlerrors.FormattedErrors{}
-	for _, ext := range p.Schema.extensions {
-		var (
-			ctx      context.Context
-			finishFn ExecutionFinishFunc
-		)
-		// catch panic from an extension's executionDidStart function
-		func() {
-			defer func() {
-				if r := recover(); r != nil {
-					errs = append(errs, gqlerrors.FormatError(fmt.Errorf("%s.ExecutionDidStart: %v", ext.Name(), r.(error))))
-				}
-			}()
-			ctx, finishFn = ext.ExecutionDidStart(p.Context)
-			// update context
-			p.Context = ctx
-			fs[ext.Name()] = finishFn
-		}()
-	}
+	var executionResult *Result
+	started, errs := startExtensions(p.Schema.extensions, "ExecutionDidStart", func(ext Extension) func() {
+		ctx, finishFn := ext.ExecutionDidStart(p.Context)
+		// update context
+		p.Context = ctx
+		return func() { finishFn(executionResult) }
+	})
 	return errs, func(result *Result) []gqlerrors.FormattedError {
-		extErrs := gqlerrors.FormattedErrors{}
-		for name, finishFn := range fs {
-			func() {
-				// catch panic from a finishFn
-				defer func() {
-					if r := recover(); r != nil {
-						extErrs = append(extErrs, gqlerrors.FormatError(fmt.Errorf("%s.ExecutionFinishFunc: %v", name, r.(error))))
-					}
-				}()
-				finishFn(result)
-			}()
-		}
-		return extErrs
+		executionResult = result
+		return finishExtensions(started, "ExecutionFinishFunc")
 	}
 }
 
-// handleResolveFieldDidStart handles the notification of the extensions about the start of a resolve function
-func handleExtensionsResolveFieldDidStart(exts []Extension, p *executionContext, i *ResolveInfo) ([]gqlerrors.FormattedError, resolveFieldFinishFuncHandler) {
-	fs := map[string]ResolveFieldFinishFunc{}
-	errs := gqlerrors.FormattedErrors{}
-	for _, ext := range p.Schema.extensions {
-		var (
-			ctx      context.Context
-			finishFn ResolveFieldFinishFunc
-		)
-		// catch panic from an extension's resolveFieldDidStart function
-		func() {
-			defer func() {
-				if r := recover(); r != nil {
-					errs = append(errs, gqlerrors.FormatError(fmt.Errorf("%s.ResolveFieldDidStart: %v", ext.Name(), r.(error))))
-				}
-			}()
-			ctx, finishFn = ext.ResolveFieldDidStart(p.Context, i)
-			// update context
-			p.Context = ctx
-			fs[ext.Name()] = finishFn
-		}()
-	}
-	return errs, func(val interface{}, err error) []gqlerrors.FormattedError {
-		extErrs := gqlerrors.FormattedErrors{}
-		for name, finishFn := range fs {
-			func() {
-				// catch panic from a finishFn
-				defer func() {
-					if r := recover(); r != nil {
-						extErrs = append(extErrs, gqlerrors.FormatError(fmt.Errorf("%s.ResolveFieldFinishFunc: %v", name, r.(error))))
-					}
-				}()
-				finishFn(val, err)
-			}()
-		}
-		return extErrs
+// handleResolveFieldDidStart handles the notification of the extensions about the start of a resolve function.
+// It returns the context of the resolve function, which is local to the field as sibling fields may be resolved concurrently.
+func handleExtensionsResolveFieldDidStart(exts []Extension, p *executionContext, i *ResolveInfo) ([]gqlerrors.FormattedError, context.Context, resolveFieldFinishFuncHandler) {
+	fieldCtx := p.Context
+	var (
+		resolvedVal interface{}
+		resolveErr  error
+	)
+	started, errs := startExtensions(exts, "ResolveFieldDidStart", func(ext Extension) func() {
+		ctx, finishFn := ext.ResolveFieldDidStart(fieldCtx, i)
+		// update context
+		fieldCtx = ctx
+		return func() { finishFn(resolvedVal, resolveErr) }
+	})
+	return errs, fieldCtx, func(val interface{}, err error) []gqlerrors.FormattedError {
+		resolvedVal, resolveErr = val, err
+		return finishExtensions(started, "ResolveFieldFinishFunc")
 	}
 }
 
-func addExtensionResults(p *ExecuteParams, result *Result) {
-	if len(p.Schema.extensions) != 0 {
-		for _, ext := range p.Schema.extensions {
-			func() {
-				defer func() {
-					if r := recover(); r != nil {
-						result.Errors = append(result.Errors, gqlerrors.FormatError(fmt.Errorf("%s.GetResult: %v", ext.Name(), r.(error))))
-					}
-				}()
-				if ext.HasResult() {
-					if result.Extensions == nil {
-						result.Extensions = make(map[string]interface{})
-					}
-					result.Extensions[ext.Name()] = ext.GetResult(p.Context)
+// addExtensionResults adds the results of the extensions to the result, in
+// the order of their registration.
+func addExtensionResults(exts []Extension, ctx context.Context, result *Result) {
+	for _, ext := range exts {
+		if err := callExtension(ext.Name(), "GetResult", func() {
+			if ext.HasResult() {
+				if result.Extensions == nil {
+					result.Extensions = make(map[string]interface{})
 				}
-			}()
+				result.Extensions[ext.Name()] = ext.GetResult(ctx)
+			}
+		}); err != nil {
+			result.Errors = append(result.Errors, *err)
 		}
 	}
 }
@@ -59,7 +59,7 @@ func TestExtensionInitPanic(t *testing.T) {
 	expected := &graphql.Result{
 		Data: nil,
 		Errors: []gqlerrors.FormattedError{
-			gqlerrors.FormatError(fmt.Errorf("%s.Init: %v", ext.Name(), errors.New("test error"))),
+			gqlerrors.FormatError(fmt.Errorf("%s.Init: %v", ext.Name(), errors.New("test error"))).WithCode(gqlerrors.CodeInternalServerError),
 		},
 	}
 	if !reflect.DeepEqual(expected, result) {
@@ -90,7 +90,7 @@ func TestExtensionParseDidStartPanic(t *testing.T) {
 	expected := &graphql.Result{
 		Data: nil,
 		Errors: []gqlerrors.FormattedError{
-			gqlerrors.FormatError(fmt.Errorf("%s.ParseDidStart: %v", ext.Name(), errors.New("test error"))),
+			gqlerrors.FormatError(fmt.Errorf("%s.ParseDidStart: %v", ext.Name(), errors.New("test error"))).WithCode(gqlerrors.CodeInternalServerError),
 		},
 	}
 	if !reflect.DeepEqual(expected, result) {
@@ -118,7 +118,7 @@ func TestExtensionParseFinishFuncPanic(t *testing.T) {
 	expected := &graphql.Result{
 		Data: nil,
 		Errors: []gqlerrors.FormattedError{
-			gqlerrors.FormatError(fmt.Errorf("%s.ParseFinishFunc: %v", ext.Name(), errors.New("test error"))),
+			gqlerrors.FormatError(fmt.Errorf("%s.ParseFinishFunc: %v", ext.Name(), errors.New("test error"))).WithCode(gqlerrors.CodeInternalServerError),
 		},
 	}
 	if !reflect.DeepEqual(expected, result) {
@@ -149,7 +149,7 @@ func TestExtensionValidationDidStartPanic(t *testing.T) {
 	expected := &graphql.Result{
 		Data: nil,
 		Errors: []gqlerrors.FormattedError{
-			gqlerrors.FormatError(fmt.Errorf("%s.ValidationDidStart: %v", ext.Name(), errors.New("test error"))),
+			gqlerrors.FormatError(fmt.Errorf("%s.ValidationDidStart: %v", ext.Name(), errors.New("test error"))).WithCode(gqlerrors.CodeInternalServerError),
 		},
 	}
 	if !reflect.DeepEqual(expected, result) {
@@ -177,7 +177,7 @@ func TestExtensionValidationFinishFuncPanic(t *testing.T) {
 	expected := &graphql.Result{
 		Data: nil,
 		Errors: []gqlerrors.FormattedError{
-			gqlerrors.FormatError(fmt.Errorf("%s.ValidationFinishFunc: %v", ext.Name(), errors.New("test error"))),
+			gqlerrors.FormatError(fmt.Errorf("%s.ValidationFinishFunc: %v", ext.Name(), errors.New("test error"))).WithCode(gqlerrors.CodeInternalServerError),
 		},
 	}
 	if !reflect.DeepEqual(expected, result) {
@@ -208,7 +208,7 @@ func TestExtensionExecutionDidStartPanic(t *testing.T) {
 	expected := &graphql.Result{
 		Data: nil,
 		Errors: []gqlerrors.FormattedError{
-			gqlerrors.FormatError(fmt.Errorf("%s.ExecutionDidStart: %v", ext.Name(), errors.New("test error"))),
+			gqlerrors.FormatError(fmt.Errorf("%s.ExecutionDidStart: %v", ext.Name(), errors.New("test error"))).WithCode(gqlerrors.CodeInternalServerError),
 		},
 	}
 	if !reflect.DeepEqual(expected, result) {
@@ -238,7 +238,7 @@ func TestExtensionExecutionFinishFuncPanic(t *testing.T) {
 			"a": "foo",
 		},
 		Errors: []gqlerrors.FormattedError{
-			gqlerrors.FormatError(fmt.Errorf("%s.ExecutionFinishFunc: %v", ext.Name(), errors.New("test error"))),
+			gqlerrors.FormatError(fmt.Errorf("%s.ExecutionFinishFunc: %v", ext.Name(), errors.New("test error"))).WithCode(gqlerrors.CodeInternalServerError),
 		},
 	}
 
@@ -272,7 +272,7 @@ func TestExtensionResolveFieldDidStartPanic(t *testing.T) {
 			"a": "foo",
 		},
 		Errors: []gqlerrors.FormattedError{
-			gqlerrors.FormatError(fmt.Errorf("%s.ResolveFieldDidStart: %v", ext.Name(), errors.New("test error"))),
+			gqlerrors.FormatError(fmt.Errorf("%s.ResolveFieldDidStart: %v", ext.Name(), errors.New("test error"))).WithCode(gqlerrors.CodeInternalServerError),
 		},
 	}
 
@@ -303,7 +303,7 @@ func TestExtensionResolveFieldFinishFuncPanic(t *testing.T) {
 			"a": "foo",
 		},
 		Errors: []gqlerrors.FormattedError{
-			gqlerrors.FormatError(fmt.Errorf("%s.ResolveFieldFinishFunc: %v", ext.Name(), errors.New("test error"))),
+			gqlerrors.FormatError(fmt.Errorf("%s.ResolveFieldFinishFunc: %v", ext.Name(), errors.New("test error"))).WithCode(gqlerrors.CodeInternalServerError),
 		},
 	}
 
@@ -367,7 +367,7 @@ func TestExtensionGetResultPanic(t *testing.T) {
 			"a": "foo",
 		},
 		Errors: []gqlerrors.FormattedError{
-			gqlerrors.FormatError(fmt.Errorf("%s.GetResult: %v", ext.Name(), errors.New("test error"))),
+			gqlerrors.FormatError(fmt.Errorf("%s.GetResult: %v", ext.Name(), errors.New("test error"))).WithCode(gqlerrors.CodeInternalServerError),
 		},
 		Extensions: make(map[string]interface{}),
 	}
@@ -377,6 +377,126 @@ func TestExtensionGetResultPanic(t *testing.T) {
 	}
 }
 
+func TestExtensionSubscribeRunsHooks(t *testing.T) {
+	calls := map[string]int{}
+	ext := newtestExt("testExt")
+	ext.initFn = func(ctx context.Context, p *graphql.Params) context.Context {
+		calls["Init"]++
+		return ctx
+	}
+	ext.parseDidStartFn = func(ctx context.Context) (context.Context, graphql.ParseFinishFunc) {
+		calls["ParseDidStart"]++
+		return ctx, func(err error) {
+			calls["ParseFinishFunc"]++
+		}
+	}
+	ext.validationDidStartFn = func(ctx context.Context) (context.Context, graphql.ValidationFinishFunc) {
+		calls["ValidationDidStart"]++
+		return ctx, func([]gqlerrors.FormattedError) {
+			calls["ValidationFinishFunc"]++
+		}
+	}
+	ext.executionDidStartFn = func(ctx context.Context) (context.Context, graphql.ExecutionFinishFunc) {
+		calls["ExecutionDidStart"]++
+		return ctx, func(r *graphql.Result) {
+			calls["ExecutionFinishFunc"]++
+		}
+	}
+	ext.hasResultFn = func() bool {
+		return true
+	}
+	ext.getResultFn = func(context.Context) interface{} {
+		return "result"
+	}
+
+	schema := makeSubscriptionSchema(t, graphql.ObjectConfig{
+		Name: "Subscription",
+		Fields: graphql.Fields{
+			"sub": &graphql.Field{
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source, nil
+				},
+				Subscribe: makeSubscribeToStringFunction([]string{"a", "b"}),
+			},
+		},
+	})
+	schema.AddExtensions(ext)
+
+	results := []*graphql.Result{}
+	for result := range graphql.Subscribe(graphql.Params{
+		Schema:        schema,
+		RequestString: `subscription { sub }`,
+	}) {
+		results = append(results, result)
+	}
+
+	expected := []*graphql.Result{
+		{
+			Data:       map[string]interface{}{"sub": "a"},
+			Extensions: map[string]interface{}{"testExt": "result"},
+		},
+		{
+			Data:       map[string]interface{}{"sub": "b"},
+			Extensions: map[string]interface{}{"testExt": "result"},
+		},
+	}
+	if !reflect.DeepEqual(expected, results) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, results))
+	}
+
+	expectedCalls := map[string]int{
+		"Init":                 1,
+		"ParseDidStart":        1,
+		"ParseFinishFunc":      1,
+		"ValidationDidStart":   1,
+		"ValidationFinishFunc": 1,
+		"ExecutionDidStart":    2,
+		"ExecutionFinishFunc":  2,
+	}
+	if !reflect.DeepEqual(expectedCalls, calls) {
+		t.Fatalf("Unexpected calls, Diff: %v", testutil.Diff(expectedCalls, calls))
+	}
+}
+
+func TestExtensionSubscribeInitPanic(t *testing.T) {
+	ext := newtestExt("testExt")
+	ext.initFn = func(ctx context.Context, p *graphql.Params) context.Context {
+		if true {
+			panic(errors.New("test error"))
+		}
+		return ctx
+	}
+
+	schema := makeSubscriptionSchema(t, graphql.ObjectConfig{
+		Name: "Subscription",
+		Fields: graphql.Fields{
+			"sub": &graphql.Field{
+				Type:      graphql.String,
+				Subscribe: makeSubscribeToStringFunction([]string{"a"}),
+			},
+		},
+	})
+	schema.AddExtensions(ext)
+
+	results := []*graphql.Result{}
+	for result := range graphql.Subscribe(graphql.Params{
+		Schema:        schema,
+		RequestString: `subscription { sub }`,
+	}) {
+		results = append(results, result)
+	}
+
+	expected := []*graphql.Result{{
+		Errors: []gqlerrors.FormattedError{
+			gqlerrors.FormatError(fmt.Errorf("%s.Init: %v", ext.Name(), errors.New("test error"))).WithCode(gqlerrors.CodeInternalServerError),
+		},
+	}}
+	if !reflect.DeepEqual(expected, results) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, results))
+	}
+}
+
 func newtestExt(name string) *testExt {
 	ext := &testExt{
 		name: name,
@@ -469,3 +589,216 @@ func (t *testExt) ExecutionDidStart(ctx context.Context) (context.Context, graph
 func (t *testExt) ResolveFieldDidStart(ctx context.Context, i *graphql.ResolveInfo) (context.Context, graphql.ResolveFieldFinishFunc) {
 	return t.resolveFieldDidStartFn(ctx, i)
 }
+
+// newRecordingExt returns a testExt recording the calls of its hooks in
+// calls, with its name.
+func newRecordingExt(name string, calls *[]string) *testExt {
+	ext := newtestExt(name)
+	record := func(hook string) {
+		*calls = append(*calls, name+"."+hook)
+	}
+	ext.initFn = func(ctx context.Context, p *graphql.Params) context.Context {
+		record("Init")
+		return ctx
+	}
+	ext.parseDidStartFn = func(ctx context.Context) (context.Context, graphql.ParseFinishFunc) {
+		record("ParseDidStart")
+		return ctx, func(err error) {
+			record("ParseFinishFunc")
+		}
+	}
+	ext.validationDidStartFn = func(ctx context.Context) (context.Context, graphql.ValidationFinishFunc) {
+		record("ValidationDidStart")
+		return ctx, func([]gqlerrors.FormattedError) {
+			record("ValidationFinishFunc")
+		}
+	}
+	ext.executionDidStartFn = func(ctx context.Context) (context.Context, graphql.ExecutionFinishFunc) {
+		record("ExecutionDidStart")
+		return ctx, func(r *graphql.Result) {
+			record("ExecutionFinishFunc")
+		}
+	}
+	ext.resolveFieldDidStartFn = func(ctx context.Context, i *graphql.ResolveInfo) (context.Context, graphql.ResolveFieldFinishFunc) {
+		record("ResolveFieldDidStart")
+		return ctx, func(v interface{}, err error) {
+			record("ResolveFieldFinishFunc")
+		}
+	}
+	ext.hasResultFn = func() bool {
+		return true
+	}
+	ext.getResultFn = func(context.Context) interface{} {
+		record("GetResult")
+		return name
+	}
+	return ext
+}
+
+func TestExtensionsRunInTheOrderOfTheirRegistration(t *testing.T) {
+	var calls []string
+	schema := tinit(t)
+	for _, name := range []string{"c", "a", "b"} {
+		schema.AddExtensions(newRecordingExt(name, &calls))
+	}
+
+	result := graphql.Do(graphql.Params{
+		Schema:        schema,
+		RequestString: `{ a }`,
+	})
+
+	expected := &graphql.Result{
+		Data:       map[string]interface{}{"a": "foo"},
+		Extensions: map[string]interface{}{"a": "a", "b": "b", "c": "c"},
+	}
+	if !reflect.DeepEqual(expected, result) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
+	}
+	var expectedCalls []string
+	for _, hook := range []string{
+		"Init",
+		"ParseDidStart", "ParseFinishFunc",
+		"ValidationDidStart", "ValidationFinishFunc",
+		"ExecutionDidStart",
+		"ResolveFieldDidStart", "ResolveFieldFinishFunc",
+		"ExecutionFinishFunc",
+		"GetResult",
+	} {
+		expectedCalls = append(expectedCalls, "c."+hook, "a."+hook, "b."+hook)
+	}
+	if !reflect.DeepEqual(expectedCalls, calls) {
+		t.Fatalf("Unexpected calls, Diff: %v", testutil.Diff(expectedCalls, calls))
+	}
+}
+
+func TestExtensionsAreFinishedOnEveryPath(t *testing.T) {
+	tests := map[string]struct {
+		query string
+		// panicking sets a hook of the second extension panicking
+		panicking func(ext *testExt)
+		// params sets the options of the request, if any
+		params        func(t *testing.T, p *graphql.Params)
+		expectedCalls []string
+		expectedErr   string
+	}{
+		"init panic": {
+			query: `{ a }`,
+			panicking: func(ext *testExt) {
+				ext.initFn = func(ctx context.Context, p *graphql.Params) context.Context {
+					panic("test error")
+				}
+			},
+			expectedCalls: []string{"first.Init", "first.GetResult"},
+			expectedErr:   "second.Init: test error",
+		},
+		"persisted query not found": {
+			panicking: func(ext *testExt) {},
+			params: func(t *testing.T, p *graphql.Params) {
+				p.PersistedQuery = &graphql.PersistedQuery{Version: 1, Sha256Hash: "unknown"}
+				p.PersistedQueryStore = graphql.NewInMemoryPersistedQueryStore(10)
+			},
+			expectedCalls: []string{"first.Init", "first.GetResult"},
+			expectedErr:   "PersistedQueryNotFound",
+		},
+		"operation not safelisted": {
+			query:     `{ a }`,
+			panicking: func(ext *testExt) {},
+			params: func(t *testing.T, p *graphql.Params) {
+				manifest, err := graphql.NewOperationManifest(&p.Schema, map[string]string{"A": `query A { a }`})
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				p.OperationManifest = manifest
+			},
+			expectedCalls: []string{"first.Init", "first.GetResult"},
+			expectedErr:   "Operation is not in the safelist of the server.",
+		},
+		"parseDidStart panic": {
+			query: `{ a }`,
+			panicking: func(ext *testExt) {
+				ext.parseDidStartFn = func(ctx context.Context) (context.Context, graphql.ParseFinishFunc) {
+					panic("test error")
+				}
+			},
+			expectedCalls: []string{"first.Init", "first.ParseDidStart", "first.ParseFinishFunc", "first.GetResult"},
+			expectedErr:   "second.ParseDidStart: test error",
+		},
+		"syntax error": {
+			query:         `{ a`,
+			panicking:     func(ext *testExt) {},
+			expectedCalls: []string{"first.Init", "first.ParseDidStart", "first.ParseFinishFunc", "first.GetResult"},
+			expectedErr:   "Syntax Error GraphQL request (1:4) Expected Name, found EOF\n\n1: { a\n      ^\n",
+		},
+		"validationDidStart panic": {
+			query: `{ a }`,
+			panicking: func(ext *testExt) {
+				ext.validationDidStartFn = func(ctx context.Context) (context.Context, graphql.ValidationFinishFunc) {
+					panic("test error")
+				}
+			},
+			expectedCalls: []string{
+				"first.Init", "first.ParseDidStart", "first.ParseFinishFunc",
+				"first.ValidationDidStart", "first.ValidationFinishFunc", "first.GetResult",
+			},
+			expectedErr: "second.ValidationDidStart: test error",
+		},
+		"validation error": {
+			query:     `{ unknown }`,
+			panicking: func(ext *testExt) {},
+			expectedCalls: []string{
+				"first.Init", "first.ParseDidStart", "first.ParseFinishFunc",
+				"first.ValidationDidStart", "first.ValidationFinishFunc", "first.GetResult",
+			},
+			expectedErr: `Cannot query field "unknown" on type "Type".`,
+		},
+		"executionDidStart panic": {
+			query: `{ a }`,
+			panicking: func(ext *testExt) {
+				ext.executionDidStartFn = func(ctx context.Context) (context.Context, graphql.ExecutionFinishFunc) {
+					panic("test error")
+				}
+			},
+			expectedCalls: []string{
+				"first.Init", "first.ParseDidStart", "first.ParseFinishFunc",
+				"first.ValidationDidStart", "first.ValidationFinishFunc",
+				"first.ExecutionDidStart", "first.ExecutionFinishFunc", "first.GetResult",
+			},
+			expectedErr: "second.ExecutionDidStart: test error",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var calls []string
+			second := newtestExt("second")
+			test.panicking(second)
+			schema := tinit(t)
+			schema.AddExtensions(newRecordingExt("first", &calls), second)
+
+			params := graphql.Params{
+				Schema:        schema,
+				RequestString: test.query,
+			}
+			if test.params != nil {
+				test.params(t, &params)
+			}
+			result := graphql.Do(params)
+
+			if len(result.Errors) != 1 || result.Errors[0].Message != test.expectedErr {
+				t.Fatalf("Unexpected errors: %v", result.Errors)
+			}
+			expectedExtensions := map[string]interface{}{"first": "first"}
+			if !reflect.DeepEqual(expectedExtensions, result.Extensions) {
+				t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expectedExtensions, result.Extensions))
+			}
+			var hooks []string
+			for _, call := range calls {
+				if call != "first.ResolveFieldDidStart" && call != "first.ResolveFieldFinishFunc" {
+					hooks = append(hooks, call)
+				}
+			}
+			if !reflect.DeepEqual(test.expectedCalls, hooks) {
+				t.Fatalf("Unexpected calls, Diff: %v", testutil.Diff(test.expectedCalls, hooks))
+			}
+		})
+	}
+}
@@ -0,0 +1,267 @@
+package graphql_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/location"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/testutil"
+)
+
+type middlewareUserKey struct{}
+
+var errUnauthenticated = errors.New("unauthenticated")
+
+// authMiddleware denies access to the fields with an @auth directive to the
+// anonymous requests.
+func authMiddleware(next graphql.FieldResolveFn) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		if p.Info.FieldDefinition().Directive("auth") != nil && p.Context.Value(middlewareUserKey{}) == nil {
+			return nil, errUnauthenticated
+		}
+		return next(p)
+	}
+}
+
+func TestFieldMiddlewareWrapsResolvers(t *testing.T) {
+	var mu sync.Mutex
+	var calls []string
+	record := func(name string) graphql.FieldMiddleware {
+		return func(next graphql.FieldResolveFn) graphql.FieldResolveFn {
+			return func(p graphql.ResolveParams) (interface{}, error) {
+				mu.Lock()
+				calls = append(calls, name+" "+p.Info.ParentType.Name()+"."+p.Info.FieldName)
+				mu.Unlock()
+				return next(p)
+			}
+		}
+	}
+	upper := func(next graphql.FieldResolveFn) graphql.FieldResolveFn {
+		return func(p graphql.ResolveParams) (interface{}, error) {
+			v, err := next(p)
+			if s, ok := v.(string); ok && p.Info.FieldName == "name" {
+				return s + "!", err
+			}
+			return v, err
+		}
+	}
+
+	hero := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Hero",
+		Fields: graphql.Fields{
+			"name": &graphql.Field{Type: graphql.String},
+		},
+	})
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Query",
+			Fields: graphql.Fields{
+				"hero": &graphql.Field{
+					Type: hero,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return map[string]interface{}{"name": "Luke"}, nil
+					},
+				},
+			},
+		}),
+		FieldMiddleware: []graphql.FieldMiddleware{record("outer"), upper},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	schema.AddFieldMiddleware(record("inner"))
+
+	result := graphql.Do(graphql.Params{
+		Schema:        schema,
+		RequestString: `{ hero { name } }`,
+	})
+	expected := &graphql.Result{
+		Data: map[string]interface{}{
+			"hero": map[string]interface{}{"name": "Luke!"},
+		},
+	}
+	if !reflect.DeepEqual(expected, result) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
+	}
+	expectedCalls := []string{"outer Query.hero", "inner Query.hero", "outer Hero.name", "inner Hero.name"}
+	if !reflect.DeepEqual(expectedCalls, calls) {
+		t.Fatalf("Unexpected calls, Diff: %v", testutil.Diff(expectedCalls, calls))
+	}
+}
+
+func TestFieldMiddlewareSelectsFieldsByDirective(t *testing.T) {
+	schema, err := graphql.BuildSchemaWithResolvers(`
+		directive @auth on FIELD_DEFINITION
+
+		type Query {
+			public: String
+			secret: String @auth
+		}
+	`, graphql.ResolverMap{
+		Fields: map[string]graphql.FieldResolveFn{
+			"Query.public": func(p graphql.ResolveParams) (interface{}, error) {
+				return "public", nil
+			},
+			"Query.secret": func(p graphql.ResolveParams) (interface{}, error) {
+				return "secret", nil
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	schema.AddFieldMiddleware(authMiddleware)
+
+	result := graphql.Do(graphql.Params{
+		Schema:        *schema,
+		RequestString: `{ public secret }`,
+		Context:       context.Background(),
+	})
+	expected := &graphql.Result{
+		Data: map[string]interface{}{"public": "public", "secret": nil},
+		Errors: []gqlerrors.FormattedError{{
+			Message:    "unauthenticated",
+			Locations:  []location.SourceLocation{{Line: 1, Column: 10}},
+			Path:       []interface{}{"secret"},
+			Extensions: map[string]interface{}{"code": gqlerrors.CodeInternalServerError},
+		}},
+	}
+	if !testutil.EqualResults(expected, result) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
+	}
+
+	result = graphql.Do(graphql.Params{
+		Schema:        *schema,
+		RequestString: `{ public secret }`,
+		Context:       context.WithValue(context.Background(), middlewareUserKey{}, "luke"),
+	})
+	expected = &graphql.Result{
+		Data: map[string]interface{}{"public": "public", "secret": "secret"},
+	}
+	if !reflect.DeepEqual(expected, result) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
+	}
+}
+
+func TestFieldMiddlewareRunsWithinTheTimeoutAndRecoveryOfResolvers(t *testing.T) {
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Query",
+			Fields: graphql.Fields{
+				"slow": &graphql.Field{
+					Type:    graphql.String,
+					Timeout: 20 * time.Millisecond,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return "slow", nil
+					},
+				},
+				"panicking": &graphql.Field{
+					Type: graphql.String,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return "panicking", nil
+					},
+				},
+			},
+		}),
+		FieldMiddleware: []graphql.FieldMiddleware{
+			func(next graphql.FieldResolveFn) graphql.FieldResolveFn {
+				return func(p graphql.ResolveParams) (interface{}, error) {
+					switch p.Info.FieldName {
+					case "slow":
+						<-p.Context.Done()
+					case "panicking":
+						panic("middleware failed")
+					}
+					return next(p)
+				}
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	result := graphql.Do(graphql.Params{
+		Schema:        schema,
+		RequestString: `{ slow panicking }`,
+	})
+	// the sibling fields are resolved in any order
+	sort.Slice(result.Errors, func(i, j int) bool {
+		return result.Errors[i].Path[0].(string) > result.Errors[j].Path[0].(string)
+	})
+	expected := &graphql.Result{
+		Data: map[string]interface{}{"slow": nil, "panicking": nil},
+		Errors: []gqlerrors.FormattedError{
+			{
+				Message:    "Field was not resolved within 20ms.",
+				Locations:  []location.SourceLocation{{Line: 1, Column: 3}},
+				Path:       []interface{}{"slow"},
+				Extensions: map[string]interface{}{"code": "TIMEOUT"},
+			},
+			{
+				Message:    "middleware failed",
+				Locations:  []location.SourceLocation{{Line: 1, Column: 8}},
+				Path:       []interface{}{"panicking"},
+				Extensions: map[string]interface{}{"code": gqlerrors.CodeInternalServerError},
+			},
+		},
+	}
+	if !testutil.EqualResults(expected, result) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
+	}
+}
+
+func TestFieldMiddlewareIsKeptByExtendSchema(t *testing.T) {
+	schema, err := graphql.BuildSchemaWithResolvers(`
+		directive @auth on FIELD_DEFINITION
+
+		type Query {
+			secret: String @auth
+		}
+	`, graphql.ResolverMap{
+		Fields: map[string]graphql.FieldResolveFn{
+			"Query.secret": func(p graphql.ResolveParams) (interface{}, error) {
+				return "secret", nil
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	schema.AddFieldMiddleware(authMiddleware)
+
+	AST, err := parser.Parse(parser.ParseParams{Source: `extend type Query { public: String }`})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	extendedSchema, err := graphql.ExtendSchema(*schema, AST)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	result := graphql.Do(graphql.Params{
+		Schema:        extendedSchema,
+		RequestString: `{ secret }`,
+		Context:       context.Background(),
+	})
+	expected := &graphql.Result{
+		Data: map[string]interface{}{"secret": nil},
+		Errors: []gqlerrors.FormattedError{{
+			Message:    "unauthenticated",
+			Locations:  []location.SourceLocation{{Line: 1, Column: 3}},
+			Path:       []interface{}{"secret"},
+			Extensions: map[string]interface{}{"code": gqlerrors.CodeInternalServerError},
+		}},
+	}
+	if !testutil.EqualResults(expected, result) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
+	}
+}
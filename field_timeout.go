@@ -0,0 +1,120 @@
+package graphql
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// TimeoutDirective is used to bound the time taken to resolve a field. The
+// timeout of the field config, if any, still applies when it is shorter. It
+// is not one of the SpecifiedDirectives, so it must be added to the
+// directives of the schema to be used.
+var TimeoutDirective = NewDirective(DirectiveConfig{
+	Name: "timeout",
+	Description: "Directs the executor to resolve this field to null with an error " +
+		"when it is not resolved within `ms` milliseconds.",
+	Args: FieldConfigArgument{
+		"ms": &ArgumentConfig{
+			Type:        NewNonNull(Int),
+			Description: "Timeout of the field in milliseconds.",
+		},
+	},
+	Locations: []string{
+		DirectiveLocationField,
+	},
+})
+
+// FieldTimeoutError is the error of a field which was not resolved within
+// its timeout. It is reported with the TIMEOUT code in the extensions of the
+// error.
+type FieldTimeoutError struct {
+	Timeout time.Duration
+}
+
+func (e *FieldTimeoutError) Error() string {
+	return fmt.Sprintf("Field was not resolved within %v.", e.Timeout)
+}
+
+// Extensions implements gqlerrors.ExtendedError.
+func (e *FieldTimeoutError) Extensions() map[string]interface{} {
+	return map[string]interface{}{"code": "TIMEOUT"}
+}
+
+// UserFacing implements gqlerrors.UserFacingError, as the error is meant for
+// clients.
+func (e *FieldTimeoutError) UserFacing() bool {
+	return true
+}
+
+// fieldTimeout returns the timeout of a field, the shortest of the timeout of
+// its definition and of its @timeout directive, or 0 if it has none.
+func fieldTimeout(eCtx *executionContext, fieldDef *FieldDefinition, fieldAST *ast.Field) time.Duration {
+	timeout := fieldDef.Timeout
+	if len(fieldAST.Directives) == 0 || eCtx.Schema.Directive(TimeoutDirective.Name) == nil {
+		return timeout
+	}
+	argValues, ok := getExecutableDirectiveValues(eCtx, TimeoutDirective, fieldAST.Directives)
+	if !ok {
+		return timeout
+	}
+	if ms, ok := argValues["ms"].(int); ok && ms > 0 {
+		if directiveTimeout := time.Duration(ms) * time.Millisecond; timeout == 0 || directiveTimeout < timeout {
+			timeout = directiveTimeout
+		}
+	}
+	return timeout
+}
+
+// resolveWithTimeout calls resolveFn in its own goroutine, with a context
+// cancelled once the timeout elapses. A resolver which has not returned by
+// then is abandoned, the cancellation of its context signalling it to stop.
+// A thunk returned by the resolver is bound to the same timeout.
+func resolveWithTimeout(resolveFn FieldResolveFn, p ResolveParams, timeout time.Duration) (interface{}, error) {
+	parent := p.Context
+	if parent == nil {
+		parent = context.Background()
+	}
+	ctx, cancel := context.WithTimeout(parent, timeout)
+	p.Context = ctx
+
+	result, err := awaitWithTimeout(parent, ctx, timeout, func() (interface{}, error) {
+		return resolveFn(p)
+	})
+	if thunk, ok := result.(func() (interface{}, error)); ok && err == nil {
+		return func() (interface{}, error) {
+			defer cancel()
+			return awaitWithTimeout(parent, ctx, timeout, thunk)
+		}, nil
+	}
+	cancel()
+	return result, err
+}
+
+// awaitWithTimeout calls f in its own goroutine, returning its result unless
+// ctx, derived from parent with the timeout, is done first. A panic of f is
+// returned as a *PanicError.
+func awaitWithTimeout(parent, ctx context.Context, timeout time.Duration, f func() (interface{}, error)) (interface{}, error) {
+	type resolved struct {
+		result interface{}
+		err    error
+	}
+	done := make(chan resolved, 1)
+	go func() {
+		var r resolved
+		r.result, r.err = recoverPanic(f)
+		done <- r
+	}()
+
+	select {
+	case r := <-done:
+		return r.result, r.err
+	case <-ctx.Done():
+		if err := contextErr(parent); err != nil {
+			return nil, err
+		}
+		return nil, &FieldTimeoutError{Timeout: timeout}
+	}
+}
@@ -0,0 +1,219 @@
+package graphql_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/location"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/testutil"
+)
+
+// newTimeoutSchema returns a schema whose slow fields only return once their
+// context is done, which closes stopped.
+func newTimeoutSchema(t *testing.T, timeout time.Duration, stopped chan struct{}) graphql.Schema {
+	waitForCancellation := func(ctx context.Context) (interface{}, error) {
+		<-ctx.Done()
+		close(stopped)
+		return "too late", nil
+	}
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Query",
+			Fields: graphql.Fields{
+				"fast": &graphql.Field{
+					Type: graphql.String,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return "fast", nil
+					},
+				},
+				"slow": &graphql.Field{
+					Type:    graphql.String,
+					Timeout: timeout,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return waitForCancellation(p.Context)
+					},
+				},
+				"slowThunk": &graphql.Field{
+					Type:    graphql.String,
+					Timeout: timeout,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return func() (interface{}, error) {
+							return waitForCancellation(p.Context)
+						}, nil
+					},
+				},
+			},
+		}),
+		Directives: append(graphql.SpecifiedDirectives, graphql.TimeoutDirective),
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return schema
+}
+
+func expectTimeout(t *testing.T, result *graphql.Result, field string, column int, stopped chan struct{}) {
+	expected := &graphql.Result{
+		Data: map[string]interface{}{
+			"fast": "fast",
+			field:  nil,
+		},
+		Errors: []gqlerrors.FormattedError{
+			{
+				Message:    "Field was not resolved within 20ms.",
+				Locations:  []location.SourceLocation{{Line: 1, Column: column}},
+				Path:       []interface{}{field},
+				Extensions: map[string]interface{}{"code": "TIMEOUT"},
+			},
+		},
+	}
+	if !testutil.EqualResults(expected, result) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
+	}
+	if !reflect.DeepEqual(expected.Errors[0].Extensions, result.Errors[0].Extensions) {
+		t.Fatalf("Unexpected extensions: %v", result.Errors[0].Extensions)
+	}
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatalf("Expected the abandoned resolver to be signalled")
+	}
+}
+
+func TestFieldTimeout(t *testing.T) {
+	stopped := make(chan struct{})
+	result := graphql.Do(graphql.Params{
+		Schema:        newTimeoutSchema(t, 20*time.Millisecond, stopped),
+		RequestString: `{ fast slow }`,
+	})
+	expectTimeout(t, result, "slow", 8, stopped)
+}
+
+func TestFieldTimeoutBoundsThunks(t *testing.T) {
+	stopped := make(chan struct{})
+	result := graphql.Do(graphql.Params{
+		Schema:        newTimeoutSchema(t, 20*time.Millisecond, stopped),
+		RequestString: `{ fast slowThunk }`,
+	})
+	expectTimeout(t, result, "slowThunk", 8, stopped)
+}
+
+func TestFieldTimeoutIsKeptByExtendSchema(t *testing.T) {
+	AST, err := parser.Parse(parser.ParseParams{Source: `extend type Query { other: String }`})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	stopped := make(chan struct{})
+	extendedSchema, err := graphql.ExtendSchema(newTimeoutSchema(t, 20*time.Millisecond, stopped), AST)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	result := graphql.Do(graphql.Params{
+		Schema:        extendedSchema,
+		RequestString: `{ fast slow }`,
+	})
+	expectTimeout(t, result, "slow", 8, stopped)
+}
+
+func TestTimeoutDirective(t *testing.T) {
+	stopped := make(chan struct{})
+	result := graphql.Do(graphql.Params{
+		Schema:        newTimeoutSchema(t, time.Hour, stopped),
+		RequestString: `{ fast slow @timeout(ms: 20) }`,
+	})
+	expectTimeout(t, result, "slow", 8, stopped)
+}
+
+func TestExecuteStopsResolvingFieldsOnceCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	resolved := make(chan string, 2)
+	field := func(name string) *graphql.Field {
+		return &graphql.Field{
+			Type: graphql.String,
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				resolved <- name
+				if name == "first" {
+					cancel()
+				}
+				return name, nil
+			},
+		}
+	}
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Query",
+			Fields: graphql.Fields{
+				"first": field("first"),
+			},
+		}),
+		Mutation: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Mutation",
+			Fields: graphql.Fields{
+				"first":  field("first"),
+				"second": field("second"),
+			},
+		}),
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	result := graphql.Do(graphql.Params{
+		Schema:        schema,
+		RequestString: `mutation { first second }`,
+		Context:       ctx,
+	})
+	if !result.HasErrors() {
+		t.Fatalf("Expected the cancellation to be reported, got: %v", result)
+	}
+	for _, err := range result.Errors {
+		if err.Code() != gqlerrors.CodeRequestCancelled {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	if name := <-resolved; name != "first" {
+		t.Fatalf("Unexpected resolved field: %v", name)
+	}
+	select {
+	case name := <-resolved:
+		t.Fatalf("Unexpected resolved field after the cancellation: %v", name)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestFieldTimeoutReportsTheCancellationOfRequests(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stopped := make(chan struct{})
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		cancel()
+	}()
+	result := graphql.Do(graphql.Params{
+		Schema:        newTimeoutSchema(t, time.Hour, stopped),
+		RequestString: `{ slow }`,
+		Context:       ctx,
+	})
+	if !result.HasErrors() {
+		t.Fatalf("Expected the cancellation to be reported, got: %v", result)
+	}
+	// the cancellation is reported by the field or by the execution,
+	// whichever notices it first
+	for _, err := range result.Errors {
+		if err.Message != context.Canceled.Error() || err.Code() != gqlerrors.CodeRequestCancelled {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatalf("Expected the abandoned resolver to be signalled")
+	}
+}
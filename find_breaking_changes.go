@@ -0,0 +1,478 @@
+package graphql
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/graphql-go/graphql/language/printer"
+)
+
+// BreakingChangeType is the kind of a change between two schemas which may
+// break existing clients.
+type BreakingChangeType string
+
+const (
+	BreakingChangeTypeRemoved                 BreakingChangeType = "TYPE_REMOVED"
+	BreakingChangeTypeChangedKind             BreakingChangeType = "TYPE_CHANGED_KIND"
+	BreakingChangeTypeRemovedFromUnion        BreakingChangeType = "TYPE_REMOVED_FROM_UNION"
+	BreakingChangeValueRemovedFromEnum        BreakingChangeType = "VALUE_REMOVED_FROM_ENUM"
+	BreakingChangeRequiredInputFieldAdded     BreakingChangeType = "REQUIRED_INPUT_FIELD_ADDED"
+	BreakingChangeImplementedInterfaceRemoved BreakingChangeType = "IMPLEMENTED_INTERFACE_REMOVED"
+	BreakingChangeFieldRemoved                BreakingChangeType = "FIELD_REMOVED"
+	BreakingChangeFieldChangedKind            BreakingChangeType = "FIELD_CHANGED_KIND"
+	BreakingChangeRequiredArgAdded            BreakingChangeType = "REQUIRED_ARG_ADDED"
+	BreakingChangeArgRemoved                  BreakingChangeType = "ARG_REMOVED"
+	BreakingChangeArgChangedKind              BreakingChangeType = "ARG_CHANGED_KIND"
+	BreakingChangeDirectiveRemoved            BreakingChangeType = "DIRECTIVE_REMOVED"
+	BreakingChangeDirectiveArgRemoved         BreakingChangeType = "DIRECTIVE_ARG_REMOVED"
+	BreakingChangeRequiredDirectiveArgAdded   BreakingChangeType = "REQUIRED_DIRECTIVE_ARG_ADDED"
+	BreakingChangeDirectiveLocationRemoved    BreakingChangeType = "DIRECTIVE_LOCATION_REMOVED"
+)
+
+// DangerousChangeType is the kind of a change between two schemas which does
+// not break existing queries, but may change the behaviour of clients, e.g.
+// a new enum value that a client does not know how to handle.
+type DangerousChangeType string
+
+const (
+	DangerousChangeValueAddedToEnum          DangerousChangeType = "VALUE_ADDED_TO_ENUM"
+	DangerousChangeTypeAddedToUnion          DangerousChangeType = "TYPE_ADDED_TO_UNION"
+	DangerousChangeOptionalInputFieldAdded   DangerousChangeType = "OPTIONAL_INPUT_FIELD_ADDED"
+	DangerousChangeOptionalArgAdded          DangerousChangeType = "OPTIONAL_ARG_ADDED"
+	DangerousChangeImplementedInterfaceAdded DangerousChangeType = "IMPLEMENTED_INTERFACE_ADDED"
+	DangerousChangeArgChangedDefaultValue    DangerousChangeType = "ARG_CHANGED_DEFAULT_VALUE"
+)
+
+// BreakingChange describes a change between two schemas which may break
+// existing clients.
+type BreakingChange struct {
+	Type        BreakingChangeType `json:"type"`
+	Description string             `json:"description"`
+}
+
+// DangerousChange describes a change between two schemas which may change the
+// behaviour of existing clients.
+type DangerousChange struct {
+	Type        DangerousChangeType `json:"type"`
+	Description string              `json:"description"`
+}
+
+// FindBreakingChanges returns the changes from the old schema to the new
+// schema which may break existing clients, such as removed types and fields
+// or arguments which became required.
+func FindBreakingChanges(oldSchema Schema, newSchema Schema) []BreakingChange {
+	breakingChanges, _ := findSchemaChanges(oldSchema, newSchema)
+	return breakingChanges
+}
+
+// FindDangerousChanges returns the changes from the old schema to the new
+// schema which do not break existing queries but may change the behaviour of
+// existing clients, such as new enum values and union members.
+func FindDangerousChanges(oldSchema Schema, newSchema Schema) []DangerousChange {
+	_, dangerousChanges := findSchemaChanges(oldSchema, newSchema)
+	return dangerousChanges
+}
+
+type schemaChanges struct {
+	breaking  []BreakingChange
+	dangerous []DangerousChange
+}
+
+func (c *schemaChanges) addBreaking(changeType BreakingChangeType, format string, a ...interface{}) {
+	c.breaking = append(c.breaking, BreakingChange{
+		Type:        changeType,
+		Description: fmt.Sprintf(format, a...),
+	})
+}
+
+func (c *schemaChanges) addDangerous(changeType DangerousChangeType, format string, a ...interface{}) {
+	c.dangerous = append(c.dangerous, DangerousChange{
+		Type:        changeType,
+		Description: fmt.Sprintf(format, a...),
+	})
+}
+
+func findSchemaChanges(oldSchema Schema, newSchema Schema) ([]BreakingChange, []DangerousChange) {
+	changes := &schemaChanges{
+		breaking:  []BreakingChange{},
+		dangerous: []DangerousChange{},
+	}
+	findTypeChanges(changes, oldSchema.TypeMap(), newSchema.TypeMap())
+	findDirectiveChanges(changes, oldSchema.Directives(), newSchema.Directives())
+	return changes.breaking, changes.dangerous
+}
+
+func findTypeChanges(changes *schemaChanges, oldTypeMap TypeMap, newTypeMap TypeMap) {
+	typeNames := []string{}
+	for name, ttype := range oldTypeMap {
+		// Specified scalars are only part of the type map if they are used,
+		// their removal is reported through the fields that used them.
+		if isIntrospectionType(ttype) || isSpecifiedScalarType(ttype) {
+			continue
+		}
+		typeNames = append(typeNames, name)
+	}
+	sort.Strings(typeNames)
+
+	for _, name := range typeNames {
+		oldType := oldTypeMap[name]
+		newType, ok := newTypeMap[name]
+		if !ok {
+			changes.addBreaking(BreakingChangeTypeRemoved, "%v was removed.", name)
+			continue
+		}
+
+		switch oldType := oldType.(type) {
+		case *Object:
+			if newType, ok := newType.(*Object); ok {
+				findFieldChanges(changes, name, oldType.Fields(), newType.Fields())
+				findImplementedInterfaceChanges(changes, oldType, newType)
+				continue
+			}
+		case *Interface:
+			if newType, ok := newType.(*Interface); ok {
+				findFieldChanges(changes, name, oldType.Fields(), newType.Fields())
+				findImplementedInterfaceChanges(changes, oldType, newType)
+				continue
+			}
+		case *Union:
+			if newType, ok := newType.(*Union); ok {
+				findUnionTypeChanges(changes, oldType, newType)
+				continue
+			}
+		case *Enum:
+			if newType, ok := newType.(*Enum); ok {
+				findEnumValueChanges(changes, oldType, newType)
+				continue
+			}
+		case *InputObject:
+			if newType, ok := newType.(*InputObject); ok {
+				findInputFieldChanges(changes, oldType, newType)
+				continue
+			}
+		case *Scalar:
+			if _, ok := newType.(*Scalar); ok {
+				continue
+			}
+		}
+
+		changes.addBreaking(BreakingChangeTypeChangedKind, "%v changed from %v to %v.", name, typeKindDescription(oldType), typeKindDescription(newType))
+	}
+}
+
+func findFieldChanges(changes *schemaChanges, typeName string, oldFields FieldDefinitionMap, newFields FieldDefinitionMap) {
+	for _, fieldName := range sortedFieldNames(oldFields) {
+		oldField := oldFields[fieldName]
+		newField, ok := newFields[fieldName]
+		if !ok {
+			changes.addBreaking(BreakingChangeFieldRemoved, "%v.%v was removed.", typeName, fieldName)
+			continue
+		}
+
+		findArgChanges(changes, typeName+"."+fieldName, oldField.Args, newField.Args)
+
+		if !isChangeSafeForObjectOrInterfaceField(oldField.Type, newField.Type) {
+			changes.addBreaking(BreakingChangeFieldChangedKind, "%v.%v changed type from %v to %v.", typeName, fieldName, oldField.Type, newField.Type)
+		}
+	}
+}
+
+func findArgChanges(changes *schemaChanges, fieldPath string, oldArgs []*Argument, newArgs []*Argument) {
+	oldArgs = sortedArguments(oldArgs)
+	newArgs = sortedArguments(newArgs)
+
+	for _, oldArg := range oldArgs {
+		newArg := findArgument(newArgs, oldArg.Name())
+		if newArg == nil {
+			changes.addBreaking(BreakingChangeArgRemoved, "%v arg %v was removed.", fieldPath, oldArg.Name())
+			continue
+		}
+
+		if !isChangeSafeForInputObjectFieldOrFieldArg(oldArg.Type, newArg.Type) {
+			changes.addBreaking(BreakingChangeArgChangedKind, "%v arg %v has changed type from %v to %v.", fieldPath, oldArg.Name(), oldArg.Type, newArg.Type)
+		} else if oldArg.DefaultValue != nil {
+			oldValue := printDefaultValue(oldArg.DefaultValue, oldArg.Type)
+			newValue := printDefaultValue(newArg.DefaultValue, newArg.Type)
+			if newArg.DefaultValue == nil {
+				changes.addDangerous(DangerousChangeArgChangedDefaultValue, "%v arg %v defaultValue was removed.", fieldPath, oldArg.Name())
+			} else if oldValue != newValue {
+				changes.addDangerous(DangerousChangeArgChangedDefaultValue, "%v arg %v has changed defaultValue from %v to %v.", fieldPath, oldArg.Name(), oldValue, newValue)
+			}
+		}
+	}
+
+	for _, newArg := range newArgs {
+		if findArgument(oldArgs, newArg.Name()) != nil {
+			continue
+		}
+		if isRequiredInput(newArg.Type, newArg.DefaultValue) {
+			changes.addBreaking(BreakingChangeRequiredArgAdded, "A required arg %v on %v was added.", newArg.Name(), fieldPath)
+		} else {
+			changes.addDangerous(DangerousChangeOptionalArgAdded, "An optional arg %v on %v was added.", newArg.Name(), fieldPath)
+		}
+	}
+}
+
+func findImplementedInterfaceChanges(changes *schemaChanges, oldType implementer, newType implementer) {
+	for _, oldInterface := range oldType.Interfaces() {
+		if !implementsInterface(newType, oldInterface.Name()) {
+			changes.addBreaking(BreakingChangeImplementedInterfaceRemoved, "%v no longer implements interface %v.", oldType.Name(), oldInterface.Name())
+		}
+	}
+	for _, newInterface := range newType.Interfaces() {
+		if !implementsInterface(oldType, newInterface.Name()) {
+			changes.addDangerous(DangerousChangeImplementedInterfaceAdded, "%v added to interfaces implemented by %v.", newInterface.Name(), newType.Name())
+		}
+	}
+}
+
+func findUnionTypeChanges(changes *schemaChanges, oldType *Union, newType *Union) {
+	for _, oldPossibleType := range oldType.Types() {
+		if !hasUnionMember(newType, oldPossibleType.Name()) {
+			changes.addBreaking(BreakingChangeTypeRemovedFromUnion, "%v was removed from union type %v.", oldPossibleType.Name(), oldType.Name())
+		}
+	}
+	for _, newPossibleType := range newType.Types() {
+		if !hasUnionMember(oldType, newPossibleType.Name()) {
+			changes.addDangerous(DangerousChangeTypeAddedToUnion, "%v was added to union type %v.", newPossibleType.Name(), newType.Name())
+		}
+	}
+}
+
+func findEnumValueChanges(changes *schemaChanges, oldType *Enum, newType *Enum) {
+	for _, oldValue := range sortedEnumValues(oldType) {
+		if !hasEnumValue(newType, oldValue.Name) {
+			changes.addBreaking(BreakingChangeValueRemovedFromEnum, "%v was removed from enum type %v.", oldValue.Name, oldType.Name())
+		}
+	}
+	for _, newValue := range sortedEnumValues(newType) {
+		if !hasEnumValue(oldType, newValue.Name) {
+			changes.addDangerous(DangerousChangeValueAddedToEnum, "%v was added to enum type %v.", newValue.Name, newType.Name())
+		}
+	}
+}
+
+func findInputFieldChanges(changes *schemaChanges, oldType *InputObject, newType *InputObject) {
+	oldFields := oldType.Fields()
+	newFields := newType.Fields()
+
+	for _, fieldName := range sortedInputFieldNames(oldFields) {
+		oldField := oldFields[fieldName]
+		newField, ok := newFields[fieldName]
+		if !ok {
+			changes.addBreaking(BreakingChangeFieldRemoved, "%v.%v was removed.", oldType.Name(), fieldName)
+			continue
+		}
+		if !isChangeSafeForInputObjectFieldOrFieldArg(oldField.Type, newField.Type) {
+			changes.addBreaking(BreakingChangeFieldChangedKind, "%v.%v changed type from %v to %v.", oldType.Name(), fieldName, oldField.Type, newField.Type)
+		}
+	}
+
+	for _, fieldName := range sortedInputFieldNames(newFields) {
+		if _, ok := oldFields[fieldName]; ok {
+			continue
+		}
+		newField := newFields[fieldName]
+		if isRequiredInput(newField.Type, newField.DefaultValue) {
+			changes.addBreaking(BreakingChangeRequiredInputFieldAdded, "A required field %v on input type %v was added.", fieldName, newType.Name())
+		} else {
+			changes.addDangerous(DangerousChangeOptionalInputFieldAdded, "An optional field %v on input type %v was added.", fieldName, newType.Name())
+		}
+	}
+}
+
+func findDirectiveChanges(changes *schemaChanges, oldDirectives []*Directive, newDirectives []*Directive) {
+	for _, oldDirective := range oldDirectives {
+		newDirective := findDirective(newDirectives, oldDirective.Name)
+		if newDirective == nil {
+			changes.addBreaking(BreakingChangeDirectiveRemoved, "%v was removed.", oldDirective.Name)
+			continue
+		}
+
+		for _, oldArg := range sortedArguments(oldDirective.Args) {
+			if findArgument(newDirective.Args, oldArg.Name()) == nil {
+				changes.addBreaking(BreakingChangeDirectiveArgRemoved, "%v was removed from %v.", oldArg.Name(), oldDirective.Name)
+			}
+		}
+		for _, newArg := range sortedArguments(newDirective.Args) {
+			if findArgument(oldDirective.Args, newArg.Name()) == nil && isRequiredInput(newArg.Type, newArg.DefaultValue) {
+				changes.addBreaking(BreakingChangeRequiredDirectiveArgAdded, "A required arg %v on directive %v was added.", newArg.Name(), newDirective.Name)
+			}
+		}
+		for _, location := range oldDirective.Locations {
+			if !hasDirectiveLocation(newDirective, location) {
+				changes.addBreaking(BreakingChangeDirectiveLocationRemoved, "%v was removed from %v.", location, oldDirective.Name)
+			}
+		}
+	}
+}
+
+// isChangeSafeForObjectOrInterfaceField reports whether the type of an output
+// field may change from the old type to the new type without breaking
+// clients, i.e. if the new type is the same or a non-null version of it.
+func isChangeSafeForObjectOrInterfaceField(oldType Type, newType Type) bool {
+	switch oldType := oldType.(type) {
+	case *List:
+		if newType, ok := newType.(*List); ok {
+			return isChangeSafeForObjectOrInterfaceField(oldType.OfType, newType.OfType)
+		}
+		if newType, ok := newType.(*NonNull); ok {
+			return isChangeSafeForObjectOrInterfaceField(oldType, newType.OfType)
+		}
+		return false
+	case *NonNull:
+		if newType, ok := newType.(*NonNull); ok {
+			return isChangeSafeForObjectOrInterfaceField(oldType.OfType, newType.OfType)
+		}
+		return false
+	}
+	switch newType := newType.(type) {
+	case *List:
+		return false
+	case *NonNull:
+		return isChangeSafeForObjectOrInterfaceField(oldType, newType.OfType)
+	}
+	return oldType.Name() == newType.Name()
+}
+
+// isChangeSafeForInputObjectFieldOrFieldArg reports whether the type of an
+// input field or argument may change from the old type to the new type
+// without breaking clients, i.e. if the new type is the same or a nullable
+// version of it.
+func isChangeSafeForInputObjectFieldOrFieldArg(oldType Type, newType Type) bool {
+	switch oldType := oldType.(type) {
+	case *List:
+		if newType, ok := newType.(*List); ok {
+			return isChangeSafeForInputObjectFieldOrFieldArg(oldType.OfType, newType.OfType)
+		}
+		return false
+	case *NonNull:
+		if newType, ok := newType.(*NonNull); ok {
+			return isChangeSafeForInputObjectFieldOrFieldArg(oldType.OfType, newType.OfType)
+		}
+		return isChangeSafeForInputObjectFieldOrFieldArg(oldType.OfType, newType)
+	}
+	switch newType.(type) {
+	case *List, *NonNull:
+		return false
+	}
+	return oldType.Name() == newType.Name()
+}
+
+func isRequiredInput(ttype Input, defaultValue interface{}) bool {
+	_, isNonNull := ttype.(*NonNull)
+	return isNonNull && defaultValue == nil
+}
+
+func printDefaultValue(value interface{}, ttype Input) string {
+	if value == nil {
+		return ""
+	}
+	return fmt.Sprintf("%v", printer.Print(astFromValue(value, ttype)))
+}
+
+func typeKindDescription(ttype Type) string {
+	switch ttype.(type) {
+	case *Scalar:
+		return "a Scalar type"
+	case *Object:
+		return "an Object type"
+	case *Interface:
+		return "an Interface type"
+	case *Union:
+		return "a Union type"
+	case *Enum:
+		return "an Enum type"
+	case *InputObject:
+		return "an Input type"
+	}
+	return fmt.Sprintf("%T", ttype)
+}
+
+func findArgument(args []*Argument, name string) *Argument {
+	for _, arg := range args {
+		if arg.Name() == name {
+			return arg
+		}
+	}
+	return nil
+}
+
+func findDirective(directives []*Directive, name string) *Directive {
+	for _, directive := range directives {
+		if directive.Name == name {
+			return directive
+		}
+	}
+	return nil
+}
+
+func hasDirectiveLocation(directive *Directive, location string) bool {
+	for _, l := range directive.Locations {
+		if l == location {
+			return true
+		}
+	}
+	return false
+}
+
+func implementsInterface(ttype implementer, name string) bool {
+	for _, iface := range ttype.Interfaces() {
+		if iface.Name() == name {
+			return true
+		}
+	}
+	return false
+}
+
+func hasUnionMember(ttype *Union, name string) bool {
+	for _, possibleType := range ttype.Types() {
+		if possibleType.Name() == name {
+			return true
+		}
+	}
+	return false
+}
+
+func hasEnumValue(ttype *Enum, name string) bool {
+	for _, value := range ttype.Values() {
+		if value.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedEnumValues(ttype *Enum) []*EnumValueDefinition {
+	values := append([]*EnumValueDefinition{}, ttype.Values()...)
+	sort.Slice(values, func(i, j int) bool {
+		return values[i].Name < values[j].Name
+	})
+	return values
+}
+
+func sortedArguments(args []*Argument) []*Argument {
+	sorted := append([]*Argument{}, args...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Name() < sorted[j].Name()
+	})
+	return sorted
+}
+
+func sortedFieldNames(fields FieldDefinitionMap) []string {
+	names := []string{}
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func sortedInputFieldNames(fields InputObjectFieldMap) []string {
+	names := []string{}
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
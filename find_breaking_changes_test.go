@@ -0,0 +1,318 @@
+package graphql_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/testutil"
+)
+
+func buildSchemaForChanges(t *testing.T, sdl string) graphql.Schema {
+	schema, err := graphql.BuildSchema(sdl)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return *schema
+}
+
+func TestFindBreakingChanges_DetectsRemovedAndChangedTypes(t *testing.T) {
+	oldSchema := buildSchemaForChanges(t, `
+		type Query {
+			first: String
+		}
+		type Removed {
+			field: String
+		}
+		type ChangedKind {
+			field: String
+		}
+	`)
+	newSchema := buildSchemaForChanges(t, `
+		type Query {
+			first: String
+		}
+		interface ChangedKind {
+			field: String
+		}
+	`)
+	expected := []graphql.BreakingChange{
+		{
+			Type:        graphql.BreakingChangeTypeChangedKind,
+			Description: "ChangedKind changed from an Object type to an Interface type.",
+		},
+		{
+			Type:        graphql.BreakingChangeTypeRemoved,
+			Description: "Removed was removed.",
+		},
+	}
+	changes := graphql.FindBreakingChanges(oldSchema, newSchema)
+	if !reflect.DeepEqual(changes, expected) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, changes))
+	}
+}
+
+func TestFindBreakingChanges_DetectsFieldChanges(t *testing.T) {
+	oldSchema := buildSchemaForChanges(t, `
+		interface Node {
+			id: ID
+		}
+		type Query {
+			removed: String
+			narrowed: String!
+			widened: String
+			list: [String]
+			changedType: String
+			node: Node
+		}
+		type Thing implements Node {
+			id: ID
+		}
+		input Filter {
+			removed: String
+			widened: String!
+			narrowed: String
+		}
+	`)
+	newSchema := buildSchemaForChanges(t, `
+		interface Node {
+			id: ID
+		}
+		type Query {
+			narrowed: String
+			widened: String!
+			list: [String!]!
+			changedType: Int
+			node: Node
+			filter(filter: Filter): String
+		}
+		type Thing {
+			id: ID
+		}
+		input Filter {
+			widened: String
+			narrowed: String!
+			required: Int!
+		}
+	`)
+	expected := []graphql.BreakingChange{
+		{
+			Type:        graphql.BreakingChangeFieldChangedKind,
+			Description: "Filter.narrowed changed type from String to String!.",
+		},
+		{
+			Type:        graphql.BreakingChangeFieldRemoved,
+			Description: "Filter.removed was removed.",
+		},
+		{
+			Type:        graphql.BreakingChangeRequiredInputFieldAdded,
+			Description: "A required field required on input type Filter was added.",
+		},
+		{
+			Type:        graphql.BreakingChangeFieldChangedKind,
+			Description: "Query.changedType changed type from String to Int.",
+		},
+		{
+			Type:        graphql.BreakingChangeFieldChangedKind,
+			Description: "Query.narrowed changed type from String! to String.",
+		},
+		{
+			Type:        graphql.BreakingChangeFieldRemoved,
+			Description: "Query.removed was removed.",
+		},
+		{
+			Type:        graphql.BreakingChangeImplementedInterfaceRemoved,
+			Description: "Thing no longer implements interface Node.",
+		},
+	}
+	changes := graphql.FindBreakingChanges(oldSchema, newSchema)
+	if !reflect.DeepEqual(changes, expected) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, changes))
+	}
+}
+
+func TestFindBreakingChanges_DetectsArgumentChanges(t *testing.T) {
+	oldSchema := buildSchemaForChanges(t, `
+		type Query {
+			field(removed: String, changed: Int, optional: String!): String
+		}
+	`)
+	newSchema := buildSchemaForChanges(t, `
+		type Query {
+			field(changed: String, optional: String, required: Int!, withDefault: Int! = 1): String
+		}
+	`)
+	expectedBreaking := []graphql.BreakingChange{
+		{
+			Type:        graphql.BreakingChangeArgChangedKind,
+			Description: "Query.field arg changed has changed type from Int to String.",
+		},
+		{
+			Type:        graphql.BreakingChangeArgRemoved,
+			Description: "Query.field arg removed was removed.",
+		},
+		{
+			Type:        graphql.BreakingChangeRequiredArgAdded,
+			Description: "A required arg required on Query.field was added.",
+		},
+	}
+	if changes := graphql.FindBreakingChanges(oldSchema, newSchema); !reflect.DeepEqual(changes, expectedBreaking) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expectedBreaking, changes))
+	}
+	expectedDangerous := []graphql.DangerousChange{
+		{
+			Type:        graphql.DangerousChangeOptionalArgAdded,
+			Description: "An optional arg withDefault on Query.field was added.",
+		},
+	}
+	if changes := graphql.FindDangerousChanges(oldSchema, newSchema); !reflect.DeepEqual(changes, expectedDangerous) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expectedDangerous, changes))
+	}
+}
+
+func TestFindBreakingChanges_DetectsEnumUnionAndDirectiveChanges(t *testing.T) {
+	oldSchema := buildSchemaForChanges(t, `
+		directive @removed on FIELD
+		directive @changed(arg: Int) on FIELD | QUERY
+		enum Color {
+			RED
+			GREEN
+		}
+		type Cat {
+			meows: Boolean
+		}
+		type Dog {
+			barks: Boolean
+		}
+		union Pet = Cat | Dog
+		type Query {
+			color: Color
+			pet: Pet
+		}
+	`)
+	newSchema := buildSchemaForChanges(t, `
+		directive @changed(required: Int!) on FIELD
+		enum Color {
+			RED
+			BLUE
+		}
+		type Cat {
+			meows: Boolean
+		}
+		type Dog {
+			barks: Boolean
+		}
+		type Bird {
+			sings: Boolean
+		}
+		union Pet = Cat | Bird
+		type Query {
+			color: Color
+			pet: Pet
+			dog: Dog
+		}
+	`)
+	expectedBreaking := []graphql.BreakingChange{
+		{
+			Type:        graphql.BreakingChangeValueRemovedFromEnum,
+			Description: "GREEN was removed from enum type Color.",
+		},
+		{
+			Type:        graphql.BreakingChangeTypeRemovedFromUnion,
+			Description: "Dog was removed from union type Pet.",
+		},
+		{
+			Type:        graphql.BreakingChangeDirectiveRemoved,
+			Description: "removed was removed.",
+		},
+		{
+			Type:        graphql.BreakingChangeDirectiveArgRemoved,
+			Description: "arg was removed from changed.",
+		},
+		{
+			Type:        graphql.BreakingChangeRequiredDirectiveArgAdded,
+			Description: "A required arg required on directive changed was added.",
+		},
+		{
+			Type:        graphql.BreakingChangeDirectiveLocationRemoved,
+			Description: "QUERY was removed from changed.",
+		},
+	}
+	changes := graphql.FindBreakingChanges(oldSchema, newSchema)
+	if !reflect.DeepEqual(changes, expectedBreaking) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expectedBreaking, changes))
+	}
+
+	expectedDangerous := []graphql.DangerousChange{
+		{
+			Type:        graphql.DangerousChangeValueAddedToEnum,
+			Description: "BLUE was added to enum type Color.",
+		},
+		{
+			Type:        graphql.DangerousChangeTypeAddedToUnion,
+			Description: "Bird was added to union type Pet.",
+		},
+	}
+	if changes := graphql.FindDangerousChanges(oldSchema, newSchema); !reflect.DeepEqual(changes, expectedDangerous) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expectedDangerous, changes))
+	}
+}
+
+func TestFindDangerousChanges_DetectsDefaultValueAndInterfaceChanges(t *testing.T) {
+	oldSchema := buildSchemaForChanges(t, `
+		interface Node {
+			id: ID
+		}
+		input Filter {
+			first: Int
+		}
+		type Thing {
+			id: ID
+		}
+		type Query {
+			things(first: Int = 10, order: String = "asc", filter: Filter): [Thing]
+			node: Node
+		}
+	`)
+	newSchema := buildSchemaForChanges(t, `
+		interface Node {
+			id: ID
+		}
+		input Filter {
+			first: Int
+			after: String
+		}
+		type Thing implements Node {
+			id: ID
+		}
+		type Query {
+			things(first: Int = 20, order: String, filter: Filter): [Thing]
+			node: Node
+		}
+	`)
+	expected := []graphql.DangerousChange{
+		{
+			Type:        graphql.DangerousChangeOptionalInputFieldAdded,
+			Description: "An optional field after on input type Filter was added.",
+		},
+		{
+			Type:        graphql.DangerousChangeArgChangedDefaultValue,
+			Description: "Query.things arg first has changed defaultValue from 10 to 20.",
+		},
+		{
+			Type:        graphql.DangerousChangeArgChangedDefaultValue,
+			Description: "Query.things arg order defaultValue was removed.",
+		},
+		{
+			Type:        graphql.DangerousChangeImplementedInterfaceAdded,
+			Description: "Node added to interfaces implemented by Thing.",
+		},
+	}
+	changes := graphql.FindDangerousChanges(oldSchema, newSchema)
+	if !reflect.DeepEqual(changes, expected) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, changes))
+	}
+	if breaking := graphql.FindBreakingChanges(oldSchema, newSchema); len(breaking) != 0 {
+		t.Fatalf("Unexpected breaking changes: %v", breaking)
+	}
+}
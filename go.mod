@@ -1,5 +1,5 @@
 module github.com/graphql-go/graphql
 
-go 1.13
+go 1.18
 
 require github.com/fatih/structs v1.1.0 // indirect
@@ -0,0 +1,21 @@
+package gqlerrors
+
+// The codes of the errors produced by the library, reported as the "code" of
+// the extensions of the errors so that clients can tell them apart.
+const (
+	// CodeParseFailed is the code of the syntax errors of a document.
+	CodeParseFailed = "GRAPHQL_PARSE_FAILED"
+	// CodeValidationFailed is the code of the errors of the validation of a
+	// document, whose extensions also hold the name of the failed rule.
+	CodeValidationFailed = "GRAPHQL_VALIDATION_FAILED"
+	// CodeBadUserInput is the code of the errors of a request which cannot be
+	// executed, such as invalid variable values or an unknown operation.
+	CodeBadUserInput = "BAD_USER_INPUT"
+	// CodeInternalServerError is the code of the errors of the server, such
+	// as a null returned for a non-null field, a panic of an extension or an
+	// error returned by a resolver without a code of its own.
+	CodeInternalServerError = "INTERNAL_SERVER_ERROR"
+	// CodeRequestCancelled is the code of the errors of a request whose
+	// context was cancelled or exceeded its deadline before it was resolved.
+	CodeRequestCancelled = "REQUEST_CANCELLED"
+)
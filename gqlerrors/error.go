@@ -18,6 +18,9 @@ type Error struct {
 	Locations     []location.SourceLocation
 	OriginalError error
 	Path          []interface{}
+	// Extensions are reported as the extensions of the formatted error,
+	// unless the original error has its own.
+	Extensions map[string]interface{}
 }
 
 // implements Golang's built-in `error` interface
@@ -25,6 +28,11 @@ func (g Error) Error() string {
 	return fmt.Sprintf("%v", g.Message)
 }
 
+// Unwrap returns the original error.
+func (g Error) Unwrap() error {
+	return g.OriginalError
+}
+
 func NewError(message string, nodes []ast.Node, stack string, source *source.Source, positions []int, origError error) *Error {
 	return newError(message, nodes, stack, source, positions, nil, origError)
 }
@@ -27,6 +27,11 @@ func (g FormattedError) Error() string {
 	return g.Message
 }
 
+// Unwrap returns the original error.
+func (g FormattedError) Unwrap() error {
+	return g.originalError
+}
+
 func NewFormattedError(message string) FormattedError {
 	err := errors.New(message)
 	return FormatError(err)
@@ -43,21 +48,57 @@ func FormatError(err error) FormattedError {
 			Path:          err.Path,
 			originalError: err,
 		}
-		if err := err.OriginalError; err != nil {
-			if extended, ok := err.(ExtendedError); ok {
-				ret.Extensions = extended.Extensions()
-			}
+		switch err := err.OriginalError.(type) {
+		case ExtendedError:
+			ret.Extensions = err.Extensions()
+		case FormattedError:
+			// e.g. the error of a thunk, which was formatted before being located
+			ret.Extensions = err.Extensions
+		}
+		if ret.Extensions == nil {
+			ret.Extensions = err.Extensions
 		}
 		return ret
 	case Error:
 		return FormatError(&err)
 	default:
-		return FormattedError{
+		ret := FormattedError{
 			Message:       err.Error(),
 			Locations:     []location.SourceLocation{},
 			originalError: err,
 		}
+		if extended, ok := err.(ExtendedError); ok {
+			ret.Extensions = extended.Extensions()
+		}
+		return ret
+	}
+}
+
+// Code returns the code of the extensions of the error, or "" if it has none.
+func (g FormattedError) Code() string {
+	code, _ := g.Extensions["code"].(string)
+	return code
+}
+
+// WithCode returns a copy of the error with code as the code of its
+// extensions, unless it already has one.
+func (g FormattedError) WithCode(code string) FormattedError {
+	if g.Code() != "" {
+		return g
+	}
+	return g.WithExtension("code", code)
+}
+
+// WithExtension returns a copy of the error with value as the key of its
+// extensions.
+func (g FormattedError) WithExtension(key string, value interface{}) FormattedError {
+	extensions := make(map[string]interface{}, len(g.Extensions)+1)
+	for k, v := range g.Extensions {
+		extensions[k] = v
 	}
+	extensions[key] = value
+	g.Extensions = extensions
+	return g
 }
 
 func FormatErrors(errs ...error) []FormattedError {
@@ -12,7 +12,7 @@ import (
 
 func NewSyntaxError(s *source.Source, position int, description string) *Error {
 	l := location.GetLocation(s, position)
-	return NewError(
+	err := NewError(
 		fmt.Sprintf("Syntax Error %s (%d:%d) %s\n\n%s", s.Name, l.Line, l.Column, description, highlightSourceAtLocation(s, l)),
 		[]ast.Node{},
 		"",
@@ -20,6 +20,8 @@ func NewSyntaxError(s *source.Source, position int, description string) *Error {
 		[]int{position},
 		nil,
 	)
+	err.Extensions = map[string]interface{}{"code": CodeParseFailed}
+	return err
 }
 
 // printCharCode here is slightly different from lexer.printCharCode()
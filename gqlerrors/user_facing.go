@@ -0,0 +1,56 @@
+package gqlerrors
+
+import "errors"
+
+// UserFacingError is implemented by the errors whose messages are meant for
+// clients, which are reported as they are when the other errors are masked
+// as internal errors.
+type UserFacingError interface {
+	error
+	UserFacing() bool
+}
+
+// IsUserFacing reports whether err, or one of the errors it wraps, is a
+// UserFacingError meant for clients.
+func IsUserFacing(err error) bool {
+	var userFacing UserFacingError
+	return errors.As(err, &userFacing) && userFacing.UserFacing()
+}
+
+// NewUserFacingError returns a UserFacingError with the given message.
+func NewUserFacingError(message string) error {
+	return &userFacingError{err: errors.New(message)}
+}
+
+// UserFacing marks err as meant for clients, keeping its extensions.
+func UserFacing(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &userFacingError{err: err}
+}
+
+type userFacingError struct {
+	err error
+}
+
+func (e *userFacingError) Error() string {
+	return e.err.Error()
+}
+
+func (e *userFacingError) Unwrap() error {
+	return e.err
+}
+
+func (e *userFacingError) UserFacing() bool {
+	return true
+}
+
+// Extensions implements ExtendedError, returning the extensions of the
+// wrapped error if it has any.
+func (e *userFacingError) Extensions() map[string]interface{} {
+	if err, ok := e.err.(ExtendedError); ok {
+		return err.Extensions()
+	}
+	return nil
+}
@@ -4,8 +4,7 @@ import (
 	"context"
 
 	"github.com/graphql-go/graphql/gqlerrors"
-	"github.com/graphql-go/graphql/language/parser"
-	"github.com/graphql-go/graphql/language/source"
+	"github.com/graphql-go/graphql/language/ast"
 )
 
 type Params struct {
@@ -31,86 +30,175 @@ type Params struct {
 	// Context may be provided to pass application-specific per-request
 	// information to resolve functions.
 	Context context.Context
+
+	// ValidationRules are run in addition to the SpecifiedRules when
+	// validating the document, e.g. MaxDepthRule or ComplexityRule.
+	ValidationRules []ValidationRuleFn
+
+	// ReplaceSpecifiedRules makes the document validated with the
+	// ValidationRules only, instead of the SpecifiedRules and the
+	// ValidationRules.
+	ReplaceSpecifiedRules bool
+
+	// SkipValidation executes the document without validating it. It should
+	// only be set for documents known to be valid, such as trusted persisted
+	// queries, as the execution of invalid documents is undefined.
+	SkipValidation bool
+
+	// DocumentCache caches the parsed and validated documents. It defaults to
+	// the DefaultDocumentCache, which is nil unless it is set.
+	DocumentCache DocumentCache
+
+	// PersistedQuery is the persistedQuery extension of a request using
+	// automatic persisted queries, in which case RequestString may be empty
+	// and is loaded from the PersistedQueryStore.
+	PersistedQuery *PersistedQuery
+
+	// PersistedQueryStore stores the query texts of the automatic persisted
+	// queries, e.g. NewInMemoryPersistedQueryStore.
+	PersistedQueryStore PersistedQueryStore
+
+	// OperationManifest restricts the executed documents to the documents of
+	// the manifest, rejecting the others with ErrOperationNotSafelisted.
+	OperationManifest *OperationManifest
+
+	// Concurrency is the maximum number of sibling fields of a query resolved
+	// in parallel, see ExecuteParams.
+	Concurrency int
+
+	// ErrorFormatter, if any, rewrites each error of the results before they
+	// are returned.
+	ErrorFormatter ErrorFormatter
+}
+
+// ErrorFormatter rewrites an error before it reaches the client, e.g. to mask
+// its message or to add extensions. The error it was formatted from, if any,
+// is given by err.OriginalError().
+type ErrorFormatter func(err gqlerrors.FormattedError) gqlerrors.FormattedError
+
+// formatErrors rewrites the errors of the result with the formatter, if any.
+func formatErrors(formatter ErrorFormatter, result *Result) {
+	if formatter == nil || result == nil {
+		return
+	}
+	for i, err := range result.Errors {
+		result.Errors[i] = formatter(err)
+	}
 }
 
-func Do(p Params) *Result {
-	source := source.NewSource(&source.Source{
-		Body: []byte(p.RequestString),
-		Name: "GraphQL request",
+func Do(p Params) (result *Result) {
+	defer func() {
+		formatErrors(p.ErrorFormatter, result)
+	}()
+
+	AST, result := prepareDocument(&p)
+	if result != nil {
+		return result
+	}
+
+	return Execute(ExecuteParams{
+		Schema:        p.Schema,
+		Root:          p.RootObject,
+		AST:           AST,
+		OperationName: p.OperationName,
+		Args:          p.VariableValues,
+		Context:       p.Context,
+		Concurrency:   p.Concurrency,
 	})
+}
+
+// prepareDocument loads, checks against the safelist, parses and validates
+// the document of the request, running the Init, ParseDidStart and
+// ValidationDidStart hooks of the extensions. If the document cannot be
+// executed, it returns the Result of the request, to which the results of
+// the extensions are added. The finish funcs of the extensions which started
+// are called on every path.
+func prepareDocument(p *Params) (AST *ast.Document, result *Result) {
+	defer func() {
+		if result != nil {
+			addExtensionResults(p.Schema.extensions, p.Context, result)
+		}
+	}()
 
 	// run init on the extensions
-	extErrs := handleExtensionsInits(&p)
+	extErrs := handleExtensionsInits(p)
 	if len(extErrs) != 0 {
-		return &Result{
+		return nil, &Result{
 			Errors: extErrs,
 		}
 	}
 
-	extErrs, parseFinishFn := handleExtensionsParseDidStart(&p)
+	// load the query text of automatic persisted queries
+	if errs := loadPersistedQuery(p); len(errs) != 0 {
+		return nil, &Result{
+			Errors: errs,
+		}
+	}
+
+	// reject the documents which are not safelisted
+	if errs := checkSafelist(p); len(errs) != 0 {
+		return nil, &Result{
+			Errors: errs,
+		}
+	}
+
+	document := lookupDocument(*p)
+
+	extErrs, parseFinishFn := handleExtensionsParseDidStart(p)
 	if len(extErrs) != 0 {
-		return &Result{
+		// the document is not parsed, the extensions which started are
+		// notified with the error of the first extension
+		extErrs = append(extErrs, parseFinishFn(extErrs[0])...)
+		return nil, &Result{
 			Errors: extErrs,
 		}
 	}
 
-	// parse the source
-	AST, err := parser.Parse(parser.ParseParams{Source: source})
-	if err != nil {
-		// run parseFinishFuncs for extensions
-		extErrs = parseFinishFn(err)
+	// parse the source, unless it is cached
+	AST, err := document.parse(*p)
 
+	// run parseFinish functions for extensions
+	extErrs = parseFinishFn(err)
+	if err != nil {
 		// merge the errors from extensions and the original error from parser
 		extErrs = append(extErrs, gqlerrors.FormatErrors(err)...)
-		return &Result{
+		return nil, &Result{
 			Errors: extErrs,
 		}
 	}
-
-	// run parseFinish functions for extensions
-	extErrs = parseFinishFn(err)
 	if len(extErrs) != 0 {
-		return &Result{
+		return nil, &Result{
 			Errors: extErrs,
 		}
 	}
 
 	// notify extensions about the start of the validation
-	extErrs, validationFinishFn := handleExtensionsValidationDidStart(&p)
+	extErrs, validationFinishFn := handleExtensionsValidationDidStart(p)
 	if len(extErrs) != 0 {
-		return &Result{
+		// the document is not validated, the extensions which started are
+		// notified with the errors of the extensions
+		extErrs = append(extErrs, validationFinishFn(extErrs)...)
+		return nil, &Result{
 			Errors: extErrs,
 		}
 	}
 
 	// validate document
-	validationResult := ValidateDocument(&p.Schema, AST, nil)
+	validationResult := document.validate(*p, AST)
 
+	// run the validationFinishFuncs for extensions
+	extErrs = validationFinishFn(validationResult.Errors)
 	if !validationResult.IsValid {
-		// run validation finish functions for extensions
-		extErrs = validationFinishFn(validationResult.Errors)
-
 		// merge the errors from extensions and the original error from parser
 		extErrs = append(extErrs, validationResult.Errors...)
-		return &Result{
+		return nil, &Result{
 			Errors: extErrs,
 		}
 	}
-
-	// run the validationFinishFuncs for extensions
-	extErrs = validationFinishFn(validationResult.Errors)
 	if len(extErrs) != 0 {
-		return &Result{
+		return nil, &Result{
 			Errors: extErrs,
 		}
 	}
-
-	return Execute(ExecuteParams{
-		Schema:        p.Schema,
-		Root:          p.RootObject,
-		AST:           AST,
-		OperationName: p.OperationName,
-		Args:          p.VariableValues,
-		Context:       p.Context,
-	})
+	return AST, nil
 }
@@ -6,6 +6,8 @@ import (
 	"testing"
 
 	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/location"
 	"github.com/graphql-go/graphql/testutil"
 )
 
@@ -268,3 +270,98 @@ func TestEmptyStringIsNotNull(t *testing.T) {
 		t.Errorf("wrong result, query: %v, graphql result diff: %v", query, testutil.Diff(expected, result))
 	}
 }
+
+func TestDoRunsAdditionalValidationRules(t *testing.T) {
+	query := `
+		query HeroFriendsQuery {
+			hero {
+				friends {
+					friends {
+						name
+					}
+				}
+			}
+		}
+	`
+	result := graphql.Do(graphql.Params{
+		Schema:          testutil.StarWarsSchema,
+		RequestString:   query,
+		ValidationRules: []graphql.ValidationRuleFn{graphql.MaxDepthRule(3)},
+	})
+	expected := []gqlerrors.FormattedError{{
+		Message:    `Operation "HeroFriendsQuery" has a depth of 4, which exceeds the maximum depth of 3.`,
+		Extensions: map[string]interface{}{"code": gqlerrors.CodeValidationFailed, "rule": "MaxDepthRule"},
+		Locations:  []location.SourceLocation{{Line: 2, Column: 3}},
+	}}
+	if result.Data != nil || !testutil.EqualFormattedErrors(expected, result.Errors) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result.Errors))
+	}
+
+	result = graphql.Do(graphql.Params{
+		Schema:          testutil.StarWarsSchema,
+		RequestString:   query,
+		ValidationRules: []graphql.ValidationRuleFn{graphql.MaxDepthRule(4)},
+	})
+	if len(result.Errors) > 0 {
+		t.Fatalf("wrong result, unexpected errors: %v", result.Errors)
+	}
+}
+
+func TestDoReplacesSpecifiedRules(t *testing.T) {
+	query := `
+		query HeroQuery {
+			hero {
+				name
+			}
+			unknown
+		}
+	`
+	result := graphql.Do(graphql.Params{
+		Schema:                testutil.StarWarsSchema,
+		RequestString:         query,
+		ValidationRules:       []graphql.ValidationRuleFn{graphql.MaxDepthRule(1)},
+		ReplaceSpecifiedRules: true,
+	})
+	expected := []gqlerrors.FormattedError{{
+		Message:    `Operation "HeroQuery" has a depth of 2, which exceeds the maximum depth of 1.`,
+		Extensions: map[string]interface{}{"code": gqlerrors.CodeValidationFailed, "rule": "MaxDepthRule"},
+		Locations:  []location.SourceLocation{{Line: 2, Column: 3}},
+	}}
+	if result.Data != nil || !testutil.EqualFormattedErrors(expected, result.Errors) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result.Errors))
+	}
+}
+
+func TestDoSkipsValidation(t *testing.T) {
+	query := `
+		query HeroQuery {
+			hero {
+				name
+			}
+			unknown
+		}
+	`
+	result := graphql.Do(graphql.Params{
+		Schema:        testutil.StarWarsSchema,
+		RequestString: query,
+	})
+	if result.Data != nil || len(result.Errors) != 1 {
+		t.Fatalf("Unexpected result: %v", result)
+	}
+
+	result = graphql.Do(graphql.Params{
+		Schema:         testutil.StarWarsSchema,
+		RequestString:  query,
+		SkipValidation: true,
+	})
+	expected := &graphql.Result{
+		Data: map[string]interface{}{
+			"hero": map[string]interface{}{
+				"name": "R2-D2",
+			},
+		},
+	}
+	if !reflect.DeepEqual(expected, result) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
+	}
+}
@@ -0,0 +1,443 @@
+// Package graphqlws serves GraphQL operations over websockets using the
+// graphql-transport-ws protocol, see
+// https://github.com/enisdenjo/graphql-ws/blob/master/PROTOCOL.md.
+//
+// Subscriptions are executed with graphql.Subscribe and each of their results
+// is sent in a next message. Queries and mutations are executed with
+// graphql.Do and their result is sent in a single next message.
+package graphqlws
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+)
+
+// Subprotocol is the websocket subprotocol implemented by the server.
+const Subprotocol = "graphql-transport-ws"
+
+// The types of the messages of the protocol.
+const (
+	MessageTypeConnectionInit = "connection_init"
+	MessageTypeConnectionAck  = "connection_ack"
+	MessageTypePing           = "ping"
+	MessageTypePong           = "pong"
+	MessageTypeSubscribe      = "subscribe"
+	MessageTypeNext           = "next"
+	MessageTypeError          = "error"
+	MessageTypeComplete       = "complete"
+)
+
+// The codes with which the server closes the connection when the client
+// breaks the protocol.
+const (
+	CloseCodeBadRequest                    = 4400
+	CloseCodeUnauthorized                  = 4401
+	CloseCodeForbidden                     = 4403
+	CloseCodeConnectionInitTimeout         = 4408
+	CloseCodeSubscriberAlreadyExists       = 4409
+	CloseCodeTooManyInitialisationRequests = 4429
+)
+
+// DefaultConnectionInitWaitTimeout is the time given to clients to send their
+// connection_init message when Config.ConnectionInitWaitTimeout is zero.
+const DefaultConnectionInitWaitTimeout = 3 * time.Second
+
+// Message is a message of the protocol.
+type Message struct {
+	ID      string          `json:"id,omitempty"`
+	Type    string          `json:"type"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+// SubscribePayload is the payload of a subscribe message.
+type SubscribePayload struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName,omitempty"`
+	Variables     map[string]interface{} `json:"variables,omitempty"`
+	Extensions    map[string]interface{} `json:"extensions,omitempty"`
+}
+
+// Conn is a websocket connection exchanging text messages. It allows the
+// server to run on top of any websocket implementation with ServeConn.
+//
+// ReadMessage is only called from one goroutine at a time, and so are
+// WriteMessage and Close. Close may be called while ReadMessage is blocked,
+// and must then make it return an error.
+type Conn interface {
+	ReadMessage() ([]byte, error)
+	WriteMessage(data []byte) error
+	Close(code int, reason string) error
+}
+
+// Config configures a Server.
+type Config struct {
+	// Schema is the schema against which the operations are executed.
+	Schema *graphql.Schema
+
+	// OnConnect is called with the payload of the connection_init message.
+	// The returned context is the parent of the contexts of the operations of
+	// the connection. Returning an error closes the connection with
+	// CloseCodeForbidden.
+	OnConnect func(ctx context.Context, payload map[string]interface{}) (context.Context, error)
+
+	// ConnectionInitWaitTimeout is the time given to clients to send their
+	// connection_init message, after which the connection is closed with
+	// CloseCodeConnectionInitTimeout. It defaults to
+	// DefaultConnectionInitWaitTimeout.
+	ConnectionInitWaitTimeout time.Duration
+
+	// KeepAlive is the interval at which ping messages are sent to the client
+	// to keep the connection alive. Zero disables them.
+	KeepAlive time.Duration
+
+	// CheckOrigin reports whether the upgrade request is allowed. By default,
+	// requests with an Origin header must come from the same host.
+	CheckOrigin func(r *http.Request) bool
+}
+
+// Server serves the graphql-transport-ws protocol.
+type Server struct {
+	config Config
+}
+
+// New returns a Server with the given configuration, or an error if it has
+// no Schema.
+func New(config Config) (*Server, error) {
+	if config.Schema == nil {
+		return nil, errors.New("graphqlws: missing schema")
+	}
+	if config.ConnectionInitWaitTimeout == 0 {
+		config.ConnectionInitWaitTimeout = DefaultConnectionInitWaitTimeout
+	}
+	return &Server{config: config}, nil
+}
+
+// ServeHTTP upgrades the request to a websocket connection and serves it
+// until it is closed or the context of the request is done.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	conn, err := upgrade(w, r, s.config.CheckOrigin)
+	if err != nil {
+		return
+	}
+	s.ServeConn(r.Context(), conn)
+}
+
+// ServeConn serves the given connection until it is closed or ctx is done,
+// then closes it. The operations of the connection are canceled on return.
+func (s *Server) ServeConn(ctx context.Context, conn Conn) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	c := &connection{
+		server:     s,
+		conn:       conn,
+		ctx:        ctx,
+		opCtx:      ctx,
+		operations: map[string]*operation{},
+	}
+	defer c.close(closeNormal, "")
+
+	initTimer := time.AfterFunc(s.config.ConnectionInitWaitTimeout, func() {
+		c.mu.Lock()
+		acknowledged := c.acknowledged
+		c.mu.Unlock()
+		if !acknowledged {
+			c.close(CloseCodeConnectionInitTimeout, "Connection initialisation timeout")
+		}
+	})
+	defer initTimer.Stop()
+
+	go func() {
+		<-ctx.Done()
+		c.close(closeNormal, "")
+	}()
+
+	if s.config.KeepAlive > 0 {
+		go c.keepAlive(s.config.KeepAlive)
+	}
+
+	for {
+		data, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		if !c.handleMessage(data) {
+			return
+		}
+	}
+}
+
+// connection is the state of a connection being served.
+type connection struct {
+	server *Server
+	conn   Conn
+	ctx    context.Context
+	// opCtx is the parent of the contexts of the operations, as returned by
+	// OnConnect
+	opCtx context.Context
+
+	// writeMu serializes the calls to WriteMessage and Close
+	writeMu sync.Mutex
+	closed  bool
+
+	// mu guards the fields below
+	mu           sync.Mutex
+	initialised  bool
+	acknowledged bool
+	operations   map[string]*operation
+}
+
+// operation is an operation being executed.
+type operation struct {
+	cancel context.CancelFunc
+}
+
+// handleMessage handles a message received from the client, and reports
+// whether the connection is still open.
+func (c *connection) handleMessage(data []byte) bool {
+	var msg Message
+	if err := json.Unmarshal(data, &msg); err != nil {
+		c.close(CloseCodeBadRequest, "Invalid message received")
+		return false
+	}
+
+	switch msg.Type {
+	case MessageTypeConnectionInit:
+		return c.handleConnectionInit(msg)
+	case MessageTypePing:
+		c.write(&Message{Type: MessageTypePong})
+	case MessageTypePong:
+	case MessageTypeSubscribe:
+		return c.handleSubscribe(msg)
+	case MessageTypeComplete:
+		c.mu.Lock()
+		if op, ok := c.operations[msg.ID]; ok {
+			delete(c.operations, msg.ID)
+			op.cancel()
+		}
+		c.mu.Unlock()
+	default:
+		c.close(CloseCodeBadRequest, fmt.Sprintf("Unexpected message type %q", msg.Type))
+		return false
+	}
+	return true
+}
+
+func (c *connection) handleConnectionInit(msg Message) bool {
+	c.mu.Lock()
+	initialised := c.initialised
+	c.initialised = true
+	c.mu.Unlock()
+	if initialised {
+		c.close(CloseCodeTooManyInitialisationRequests, "Too many initialisation requests")
+		return false
+	}
+
+	var payload map[string]interface{}
+	if len(msg.Payload) != 0 {
+		if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+			c.close(CloseCodeBadRequest, "Invalid connection_init payload")
+			return false
+		}
+	}
+	if c.server.config.OnConnect != nil {
+		ctx, err := c.server.config.OnConnect(c.ctx, payload)
+		if err != nil {
+			c.close(CloseCodeForbidden, "Forbidden")
+			return false
+		}
+		if ctx != nil {
+			c.opCtx = ctx
+		}
+	}
+
+	c.mu.Lock()
+	c.acknowledged = true
+	c.mu.Unlock()
+	c.write(&Message{Type: MessageTypeConnectionAck})
+	return true
+}
+
+func (c *connection) handleSubscribe(msg Message) bool {
+	c.mu.Lock()
+	acknowledged := c.acknowledged
+	c.mu.Unlock()
+	if !acknowledged {
+		c.close(CloseCodeUnauthorized, "Unauthorized")
+		return false
+	}
+
+	var payload SubscribePayload
+	if msg.ID == "" || json.Unmarshal(msg.Payload, &payload) != nil {
+		c.close(CloseCodeBadRequest, "Invalid subscribe message")
+		return false
+	}
+
+	ctx, cancel := context.WithCancel(c.opCtx)
+	op := &operation{cancel: cancel}
+	c.mu.Lock()
+	_, exists := c.operations[msg.ID]
+	if !exists {
+		c.operations[msg.ID] = op
+	}
+	c.mu.Unlock()
+	if exists {
+		cancel()
+		c.close(CloseCodeSubscriberAlreadyExists, fmt.Sprintf("Subscriber for %s already exists", msg.ID))
+		return false
+	}
+
+	go c.execute(ctx, msg.ID, op, payload)
+	return true
+}
+
+// execute executes an operation, sending its results to the client until it
+// completes or is canceled.
+func (c *connection) execute(ctx context.Context, id string, op *operation, payload SubscribePayload) {
+	defer func() {
+		c.mu.Lock()
+		if c.operations[id] == op {
+			delete(c.operations, id)
+		}
+		c.mu.Unlock()
+		op.cancel()
+	}()
+
+	params := graphql.Params{
+		Schema:         *c.server.config.Schema,
+		RequestString:  payload.Query,
+		VariableValues: payload.Variables,
+		OperationName:  payload.OperationName,
+		Context:        ctx,
+	}
+
+	var results chan *graphql.Result
+	if isSubscription(payload.Query, payload.OperationName) {
+		results = graphql.Subscribe(params)
+	} else {
+		results = make(chan *graphql.Result, 1)
+		results <- graphql.Do(params)
+		close(results)
+	}
+
+	failed := false
+	first := true
+	// results is drained even once the operation is canceled, so that the
+	// goroutine sending them can return
+	for result := range results {
+		if failed || ctx.Err() != nil {
+			continue
+		}
+		// errors preventing the execution of the operation are sent in an
+		// error message, which ends the operation
+		if first && isRequestError(result) {
+			c.writePayload(id, MessageTypeError, result.Errors)
+			failed = true
+			continue
+		}
+		first = false
+		c.writePayload(id, MessageTypeNext, result)
+	}
+	if !failed && ctx.Err() == nil {
+		c.write(&Message{ID: id, Type: MessageTypeComplete})
+	}
+}
+
+// isRequestError reports whether the result only holds the errors of an
+// operation which could not be executed, such as syntax or validation errors,
+// rather than the errors of its execution.
+func isRequestError(result *graphql.Result) bool {
+	if result.Data != nil || len(result.Errors) == 0 {
+		return false
+	}
+	for _, err := range result.Errors {
+		switch err.Code() {
+		case gqlerrors.CodeParseFailed, gqlerrors.CodeValidationFailed, gqlerrors.CodeBadUserInput:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func (c *connection) keepAlive(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.ctx.Done():
+			return
+		case <-ticker.C:
+			if c.write(&Message{Type: MessageTypePing}) != nil {
+				return
+			}
+		}
+	}
+}
+
+func (c *connection) writePayload(id string, messageType string, payload interface{}) error {
+	b, err := json.Marshal(payload)
+	if err != nil {
+		b, _ = json.Marshal([]gqlerrors.FormattedError{gqlerrors.FormatError(err)})
+		messageType = MessageTypeError
+	}
+	return c.write(&Message{ID: id, Type: messageType, Payload: b})
+}
+
+func (c *connection) write(msg *Message) error {
+	b, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	if c.closed {
+		return errClosed
+	}
+	return c.conn.WriteMessage(b)
+}
+
+func (c *connection) close(code int, reason string) {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	if c.closed {
+		return
+	}
+	c.closed = true
+	c.conn.Close(code, reason)
+}
+
+// isSubscription reports whether the operation to execute is a subscription.
+// Documents which cannot be parsed are left to graphql.Do to report.
+func isSubscription(query string, operationName string) bool {
+	doc, err := parser.Parse(parser.ParseParams{
+		Source: source.NewSource(&source.Source{
+			Body: []byte(query),
+			Name: "GraphQL request",
+		}),
+	})
+	if err != nil {
+		return false
+	}
+	for _, definition := range doc.Definitions {
+		operation, ok := definition.(*ast.OperationDefinition)
+		if !ok {
+			continue
+		}
+		if operationName == "" || (operation.Name != nil && operation.Name.Value == operationName) {
+			return operation.Operation == ast.OperationTypeSubscription
+		}
+	}
+	return false
+}
@@ -0,0 +1,363 @@
+package graphqlws
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/testutil"
+)
+
+type contextKey string
+
+var testSchema, _ = graphql.NewSchema(graphql.SchemaConfig{
+	Query: graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"hello": &graphql.Field{
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return "world", nil
+				},
+			},
+			"failing": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.String),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return nil, errors.New("failed")
+				},
+			},
+		},
+	}),
+	Subscription: graphql.NewObject(graphql.ObjectConfig{
+		Name: "Subscription",
+		Fields: graphql.Fields{
+			"count": &graphql.Field{
+				Type: graphql.Int,
+				Args: graphql.FieldConfigArgument{
+					"to": &graphql.ArgumentConfig{Type: graphql.Int},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source, nil
+				},
+				Subscribe: func(p graphql.ResolveParams) (interface{}, error) {
+					c := make(chan interface{})
+					go func() {
+						defer close(c)
+						for i := 1; i <= p.Args["to"].(int); i++ {
+							select {
+							case <-p.Context.Done():
+								return
+							case c <- i:
+							}
+						}
+					}()
+					return c, nil
+				},
+			},
+			"user": &graphql.Field{
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Context.Value(contextKey("user")), nil
+				},
+				Subscribe: func(p graphql.ResolveParams) (interface{}, error) {
+					c := make(chan interface{}, 1)
+					c <- struct{}{}
+					close(c)
+					return c, nil
+				},
+			},
+			"forever": &graphql.Field{
+				Type: graphql.Int,
+				Subscribe: func(p graphql.ResolveParams) (interface{}, error) {
+					c := make(chan interface{})
+					go func() {
+						<-p.Context.Done()
+						if canceled, ok := p.Context.Value(contextKey("canceled")).(chan struct{}); ok {
+							close(canceled)
+						}
+						close(c)
+					}()
+					return c, nil
+				},
+			},
+		},
+	}),
+})
+
+func newTestServer(t *testing.T, config Config) *httptest.Server {
+	config.Schema = &testSchema
+	s, err := New(config)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	server := httptest.NewServer(s)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func dial(t *testing.T, server *httptest.Server) *websocketConn {
+	conn, err := net.Dial("tcp", server.Listener.Addr().String())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	req.Header.Set("Sec-WebSocket-Key", "dGhlIHNhbXBsZSBub25jZQ==")
+	req.Header.Set("Sec-WebSocket-Protocol", Subprotocol)
+	if err := req.Write(conn); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	br := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(br, req)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		t.Fatalf("Unexpected status code: %v", resp.StatusCode)
+	}
+	if accept := resp.Header.Get("Sec-WebSocket-Accept"); accept != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" {
+		t.Fatalf("Unexpected Sec-WebSocket-Accept header: %v", accept)
+	}
+	return &websocketConn{conn: conn, br: br, client: true}
+}
+
+func send(t *testing.T, client *websocketConn, message string) {
+	if err := client.WriteMessage([]byte(message)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+}
+
+// sendFrame sends a single frame with the given first byte, holding the FIN
+// bit and the opcode, whose payload is masked with a zero key.
+func sendFrame(t *testing.T, client *websocketConn, first byte, payload []byte) {
+	frame := []byte{first, 0x80 | 126, 0, 0}
+	binary.BigEndian.PutUint16(frame[2:], uint16(len(payload)))
+	frame = append(frame, 0, 0, 0, 0)
+	if _, err := client.conn.Write(append(frame, payload...)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+}
+
+func expectMessage(t *testing.T, client *websocketConn, expected string) {
+	data, err := client.ReadMessage()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var expectedMessage, message interface{}
+	json.Unmarshal([]byte(expected), &expectedMessage)
+	json.Unmarshal(data, &message)
+	if !reflect.DeepEqual(expectedMessage, message) {
+		t.Fatalf("Unexpected message, Diff: %v", testutil.Diff(expectedMessage, message))
+	}
+}
+
+func expectClose(t *testing.T, client *websocketConn, expectedCode int) {
+	for {
+		_, opcode, payload, err := client.readFrame()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if opcode != opClose {
+			continue
+		}
+		if code := int(binary.BigEndian.Uint16(payload)); code != expectedCode {
+			t.Fatalf("Unexpected close code %v: %s", code, payload[2:])
+		}
+		return
+	}
+}
+
+func connect(t *testing.T, server *httptest.Server) *websocketConn {
+	client := dial(t, server)
+	send(t, client, `{"type":"connection_init"}`)
+	expectMessage(t, client, `{"type":"connection_ack"}`)
+	return client
+}
+
+func TestServer_StreamsSubscriptionResults(t *testing.T) {
+	client := connect(t, newTestServer(t, Config{}))
+	send(t, client, `{"id":"1","type":"subscribe","payload":{"query":"subscription { count(to: 2) }"}}`)
+	expectMessage(t, client, `{"id":"1","type":"next","payload":{"data":{"count":1}}}`)
+	expectMessage(t, client, `{"id":"1","type":"next","payload":{"data":{"count":2}}}`)
+	expectMessage(t, client, `{"id":"1","type":"complete"}`)
+}
+
+func TestServer_ExecutesQueries(t *testing.T) {
+	client := connect(t, newTestServer(t, Config{}))
+	send(t, client, `{"id":"1","type":"subscribe","payload":{"query":"query Hello { hello }","operationName":"Hello"}}`)
+	expectMessage(t, client, `{"id":"1","type":"next","payload":{"data":{"hello":"world"}}}`)
+	expectMessage(t, client, `{"id":"1","type":"complete"}`)
+}
+
+func TestServer_SendsErrorsOfInvalidOperations(t *testing.T) {
+	client := connect(t, newTestServer(t, Config{}))
+	send(t, client, `{"id":"1","type":"subscribe","payload":{"query":"subscription { unknown }"}}`)
+	expectMessage(t, client, `{"id":"1","type":"error","payload":[{
+		"message":"Cannot query field \"unknown\" on type \"Subscription\".",
+		"locations":[{"line":1,"column":16}],
+		"extensions":{"code":"GRAPHQL_VALIDATION_FAILED","rule":"FieldsOnCorrectTypeRule"}
+	}]}`)
+
+	// the connection remains usable
+	send(t, client, `{"type":"ping"}`)
+	expectMessage(t, client, `{"type":"pong"}`)
+}
+
+func TestServer_SendsExecutionErrorsInNextMessages(t *testing.T) {
+	client := connect(t, newTestServer(t, Config{}))
+	send(t, client, `{"id":"1","type":"subscribe","payload":{"query":"{ failing }"}}`)
+	expectMessage(t, client, `{"id":"1","type":"next","payload":{
+		"data":null,
+		"errors":[{
+			"message":"failed",
+			"locations":[{"line":1,"column":3}],
+			"path":["failing"],
+			"extensions":{"code":"INTERNAL_SERVER_ERROR"}
+		}]
+	}}`)
+	expectMessage(t, client, `{"id":"1","type":"complete"}`)
+}
+
+func TestNew_RejectsConfigsWithoutSchema(t *testing.T) {
+	if _, err := New(Config{}); err == nil {
+		t.Fatalf("Expected an error")
+	}
+}
+
+func TestServer_CompleteCancelsTheOperation(t *testing.T) {
+	canceled := make(chan struct{})
+	client := connect(t, newTestServer(t, Config{
+		OnConnect: func(ctx context.Context, payload map[string]interface{}) (context.Context, error) {
+			return context.WithValue(ctx, contextKey("canceled"), canceled), nil
+		},
+	}))
+	send(t, client, `{"id":"1","type":"subscribe","payload":{"query":"subscription { forever }"}}`)
+	send(t, client, `{"id":"1","type":"complete"}`)
+	select {
+	case <-canceled:
+	case <-time.After(5 * time.Second):
+		t.Fatal("The context of the operation was not canceled")
+	}
+
+	// the id can be reused once completed
+	send(t, client, `{"id":"1","type":"subscribe","payload":{"query":"subscription { count(to: 1) }"}}`)
+	expectMessage(t, client, `{"id":"1","type":"next","payload":{"data":{"count":1}}}`)
+	expectMessage(t, client, `{"id":"1","type":"complete"}`)
+}
+
+func TestServer_PassesTheContextOfOnConnect(t *testing.T) {
+	server := newTestServer(t, Config{
+		OnConnect: func(ctx context.Context, payload map[string]interface{}) (context.Context, error) {
+			if payload["token"] != "secret" {
+				return nil, errors.New("invalid token")
+			}
+			return context.WithValue(ctx, contextKey("user"), "luke"), nil
+		},
+	})
+
+	client := dial(t, server)
+	send(t, client, `{"type":"connection_init","payload":{"token":"secret"}}`)
+	expectMessage(t, client, `{"type":"connection_ack"}`)
+	send(t, client, `{"id":"1","type":"subscribe","payload":{"query":"subscription { user }"}}`)
+	expectMessage(t, client, `{"id":"1","type":"next","payload":{"data":{"user":"luke"}}}`)
+	expectMessage(t, client, `{"id":"1","type":"complete"}`)
+
+	client = dial(t, server)
+	send(t, client, `{"type":"connection_init","payload":{"token":"wrong"}}`)
+	expectClose(t, client, CloseCodeForbidden)
+}
+
+func TestServer_ClosesConnectionsBreakingTheProtocol(t *testing.T) {
+	server := newTestServer(t, Config{ConnectionInitWaitTimeout: 10 * time.Millisecond})
+
+	client := dial(t, server)
+	expectClose(t, client, CloseCodeConnectionInitTimeout)
+
+	client = dial(t, server)
+	send(t, client, `{"id":"1","type":"subscribe","payload":{"query":"{ hello }"}}`)
+	expectClose(t, client, CloseCodeUnauthorized)
+
+	client = connect(t, server)
+	send(t, client, `{"type":"connection_init"}`)
+	expectClose(t, client, CloseCodeTooManyInitialisationRequests)
+
+	client = connect(t, server)
+	send(t, client, `{"id":"1","type":"subscribe","payload":{"query":"subscription { forever }"}}`)
+	send(t, client, `{"id":"1","type":"subscribe","payload":{"query":"subscription { forever }"}}`)
+	expectClose(t, client, CloseCodeSubscriberAlreadyExists)
+
+	client = connect(t, server)
+	send(t, client, `{"type":"unknown"}`)
+	expectClose(t, client, CloseCodeBadRequest)
+}
+
+func TestServer_ClosesConnectionsSendingInvalidFrames(t *testing.T) {
+	server := newTestServer(t, Config{})
+	tests := []struct {
+		name   string
+		frames [][]byte
+		code   int
+	}{
+		{"fragmented ping", [][]byte{{opPing}}, closeProtocolError},
+		{"large ping", [][]byte{append([]byte{0x80 | opPing}, make([]byte, 126)...)}, closeProtocolError},
+		{"invalid UTF-8", [][]byte{{0x80 | opText, 0xff, 0xfe}}, closeInvalidPayload},
+		{"invalid UTF-8 across frames", [][]byte{{opText, 0xe2, 0x82}, {0x80 | opContinuation, 0x28}}, closeInvalidPayload},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client := connect(t, server)
+			for _, frame := range test.frames {
+				sendFrame(t, client, frame[0], frame[1:])
+			}
+			expectClose(t, client, test.code)
+		})
+	}
+
+	// a character may be split across the frames of a message
+	client := connect(t, server)
+	message := []byte(`{"type":"ping","payload":{"text":"€"}}`)
+	split := bytes.IndexByte(message, 0xe2) + 1
+	sendFrame(t, client, opText, message[:split])
+	sendFrame(t, client, 0x80|opContinuation, message[split:])
+	expectMessage(t, client, `{"type":"pong"}`)
+}
+
+func TestServer_SendsKeepAlivePings(t *testing.T) {
+	client := connect(t, newTestServer(t, Config{KeepAlive: 10 * time.Millisecond}))
+	expectMessage(t, client, `{"type":"ping"}`)
+	send(t, client, `{"type":"pong"}`)
+	expectMessage(t, client, `{"type":"ping"}`)
+}
+
+func TestServer_RejectsRequestsWithoutTheSubprotocol(t *testing.T) {
+	server := newTestServer(t, Config{})
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	req.Header.Set("Sec-WebSocket-Key", "dGhlIHNhbXBsZSBub25jZQ==")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("Unexpected status code: %v", resp.StatusCode)
+	}
+}
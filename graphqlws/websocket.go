@@ -0,0 +1,315 @@
+package graphqlws
+
+import (
+	"bufio"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// websocketGUID is used to compute the Sec-WebSocket-Accept header, see
+// RFC 6455 section 1.3.
+const websocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// maxMessageSize is the size of the largest message accepted from a client.
+const maxMessageSize = 1 << 20
+
+const (
+	opContinuation = 0x0
+	opText         = 0x1
+	opBinary       = 0x2
+	opClose        = 0x8
+	opPing         = 0x9
+	opPong         = 0xa
+)
+
+const (
+	closeNormal          = 1000
+	closeProtocolError   = 1002
+	closeInvalidPayload  = 1007
+	closeMessageTooLarge = 1009
+)
+
+var errClosed = errors.New("graphqlws: connection closed")
+
+// websocketConn is a minimal implementation of the server side of RFC 6455,
+// or of the client side when client is true.
+type websocketConn struct {
+	conn   net.Conn
+	br     *bufio.Reader
+	client bool
+
+	writeMu   sync.Mutex
+	closeOnce sync.Once
+}
+
+// upgrade performs the opening handshake of a websocket connection speaking
+// the graphql-transport-ws subprotocol. On failure it has already replied to
+// the request.
+func upgrade(w http.ResponseWriter, r *http.Request, checkOrigin func(r *http.Request) bool) (*websocketConn, error) {
+	if r.Method != http.MethodGet ||
+		!headerContainsToken(r.Header, "Connection", "upgrade") ||
+		!headerContainsToken(r.Header, "Upgrade", "websocket") {
+		http.Error(w, "Expected a websocket upgrade request", http.StatusBadRequest)
+		return nil, errors.New("graphqlws: not a websocket upgrade request")
+	}
+	if r.Header.Get("Sec-WebSocket-Version") != "13" {
+		w.Header().Set("Sec-WebSocket-Version", "13")
+		http.Error(w, "Unsupported websocket version", http.StatusBadRequest)
+		return nil, errors.New("graphqlws: unsupported websocket version")
+	}
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if key == "" {
+		http.Error(w, "Missing Sec-WebSocket-Key header", http.StatusBadRequest)
+		return nil, errors.New("graphqlws: missing Sec-WebSocket-Key header")
+	}
+	if !headerContainsToken(r.Header, "Sec-WebSocket-Protocol", Subprotocol) {
+		http.Error(w, "Unsupported websocket subprotocol", http.StatusBadRequest)
+		return nil, errors.New("graphqlws: unsupported websocket subprotocol")
+	}
+	if checkOrigin == nil {
+		checkOrigin = checkSameOrigin
+	}
+	if !checkOrigin(r) {
+		http.Error(w, "Origin not allowed", http.StatusForbidden)
+		return nil, errors.New("graphqlws: origin not allowed")
+	}
+
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, "Websocket upgrades are not supported", http.StatusInternalServerError)
+		return nil, errors.New("graphqlws: response does not implement http.Hijacker")
+	}
+	conn, rw, err := hijacker.Hijack()
+	if err != nil {
+		return nil, err
+	}
+
+	response := "HTTP/1.1 101 Switching Protocols\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + acceptKey(key) + "\r\n" +
+		"Sec-WebSocket-Protocol: " + Subprotocol + "\r\n\r\n"
+	if _, err := rw.WriteString(response); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if err := rw.Flush(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return &websocketConn{conn: conn, br: rw.Reader}, nil
+}
+
+// checkSameOrigin accepts requests without an Origin header, and requests
+// whose Origin matches the Host header.
+func checkSameOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(u.Host, r.Host)
+}
+
+func headerContainsToken(header http.Header, name, token string) bool {
+	for _, value := range header[http.CanonicalHeaderKey(name)] {
+		for _, t := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(t), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func acceptKey(key string) string {
+	h := sha1.New()
+	h.Write([]byte(key + websocketGUID))
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
+
+// ReadMessage returns the payload of the next text or binary message,
+// answering the ping frames received meanwhile. Text messages which are not
+// valid UTF-8 close the connection, see RFC 6455 section 8.1.
+func (c *websocketConn) ReadMessage() ([]byte, error) {
+	var message []byte
+	started, text := false, false
+	for {
+		fin, opcode, payload, err := c.readFrame()
+		if err != nil {
+			return nil, err
+		}
+		switch opcode {
+		case opPing:
+			if err := c.writeFrame(opPong, payload); err != nil {
+				return nil, err
+			}
+			continue
+		case opPong:
+			continue
+		case opClose:
+			code := closeNormal
+			if len(payload) >= 2 {
+				code = int(binary.BigEndian.Uint16(payload))
+			}
+			c.Close(code, "")
+			return nil, errClosed
+		case opText, opBinary:
+			if started {
+				c.Close(closeProtocolError, "Expected a continuation frame")
+				return nil, errClosed
+			}
+			started, text = true, opcode == opText
+		case opContinuation:
+			if !started {
+				c.Close(closeProtocolError, "Unexpected continuation frame")
+				return nil, errClosed
+			}
+		default:
+			c.Close(closeProtocolError, "Unknown opcode")
+			return nil, errClosed
+		}
+		if len(message)+len(payload) > maxMessageSize {
+			c.Close(closeMessageTooLarge, "Message too large")
+			return nil, errClosed
+		}
+		message = append(message, payload...)
+		if fin {
+			if text && !utf8.Valid(message) {
+				c.Close(closeInvalidPayload, "Invalid UTF-8 text")
+				return nil, errClosed
+			}
+			return message, nil
+		}
+	}
+}
+
+func (c *websocketConn) readFrame() (bool, byte, []byte, error) {
+	var header [2]byte
+	if _, err := io.ReadFull(c.br, header[:]); err != nil {
+		return false, 0, nil, err
+	}
+	fin := header[0]&0x80 != 0
+	opcode := header[0] & 0x0f
+	masked := header[1]&0x80 != 0
+	if header[0]&0x70 != 0 || masked == c.client {
+		c.Close(closeProtocolError, "Invalid frame")
+		return false, 0, nil, errClosed
+	}
+
+	length := uint64(header[1] & 0x7f)
+	switch length {
+	case 126:
+		var b [2]byte
+		if _, err := io.ReadFull(c.br, b[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = uint64(binary.BigEndian.Uint16(b[:]))
+	case 127:
+		var b [8]byte
+		if _, err := io.ReadFull(c.br, b[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = binary.BigEndian.Uint64(b[:])
+	}
+	// control frames cannot be fragmented and their payload is limited to
+	// 125 bytes, see RFC 6455 section 5.5
+	if opcode >= opClose && (!fin || length > 125) {
+		c.Close(closeProtocolError, "Invalid control frame")
+		return false, 0, nil, errClosed
+	}
+	if length > maxMessageSize {
+		c.Close(closeMessageTooLarge, "Message too large")
+		return false, 0, nil, errClosed
+	}
+
+	var mask [4]byte
+	if masked {
+		if _, err := io.ReadFull(c.br, mask[:]); err != nil {
+			return false, 0, nil, err
+		}
+	}
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(c.br, payload); err != nil {
+		return false, 0, nil, err
+	}
+	if masked {
+		for i := range payload {
+			payload[i] ^= mask[i%4]
+		}
+	}
+	return fin, opcode, payload, nil
+}
+
+// WriteMessage sends data in a single text frame.
+func (c *websocketConn) WriteMessage(data []byte) error {
+	return c.writeFrame(opText, data)
+}
+
+func (c *websocketConn) writeFrame(opcode byte, payload []byte) error {
+	frame := []byte{0x80 | opcode}
+	var maskBit byte
+	if c.client {
+		maskBit = 0x80
+	}
+	switch length := len(payload); {
+	case length < 126:
+		frame = append(frame, maskBit|byte(length))
+	case length <= 0xffff:
+		frame = append(frame, maskBit|126, 0, 0)
+		binary.BigEndian.PutUint16(frame[2:], uint16(length))
+	default:
+		frame = append(frame, maskBit|127, 0, 0, 0, 0, 0, 0, 0, 0)
+		binary.BigEndian.PutUint64(frame[2:], uint64(length))
+	}
+	if c.client {
+		var mask [4]byte
+		if _, err := rand.Read(mask[:]); err != nil {
+			return err
+		}
+		frame = append(frame, mask[:]...)
+		start := len(frame)
+		frame = append(frame, payload...)
+		for i := range frame[start:] {
+			frame[start+i] ^= mask[i%4]
+		}
+	} else {
+		frame = append(frame, payload...)
+	}
+
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	_, err := c.conn.Write(frame)
+	return err
+}
+
+// Close sends a close frame with the given code and reason, then closes the
+// underlying connection without waiting for the close frame of the peer.
+func (c *websocketConn) Close(code int, reason string) error {
+	err := errClosed
+	c.closeOnce.Do(func() {
+		// the payload of control frames is limited to 125 bytes
+		if len(reason) > 123 {
+			reason = reason[:123]
+		}
+		payload := make([]byte, 2, 2+len(reason))
+		binary.BigEndian.PutUint16(payload, uint16(code))
+		payload = append(payload, reason...)
+		c.writeFrame(opClose, payload)
+		err = c.conn.Close()
+	})
+	return err
+}
@@ -0,0 +1,288 @@
+// Package handler serves a GraphQL schema over HTTP.
+//
+// Operations are accepted in GET requests, with the query, variables and
+// operationName URL parameters, and in POST requests with an
+// application/json, application/graphql, application/x-www-form-urlencoded
+// or multipart/form-data body. A JSON body may hold an array of operations,
+// which are executed in order and answered with an array of results.
+//
+// Automatic persisted queries are supported once a PersistedQueryStore is
+// configured: an operation may then be sent with the SHA-256 hash of its
+// query in extensions.persistedQuery instead of its query text.
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/visitor"
+)
+
+// RootObjectFn returns the root object of the operations of a request.
+type RootObjectFn func(ctx context.Context, r *http.Request) map[string]interface{}
+
+// ContextFn returns the context in which the operations of a request are
+// executed. It is typically derived from the context of the request, for
+// instance to carry the authenticated user.
+type ContextFn func(r *http.Request) context.Context
+
+// Config configures a Handler.
+type Config struct {
+	// Schema is the schema against which the operations are executed.
+	Schema *graphql.Schema
+
+	// Pretty indents the JSON responses.
+	Pretty bool
+
+	// GraphiQL serves the GraphiQL IDE to browsers requesting HTML with GET.
+	GraphiQL bool
+
+	// Playground serves the GraphQL Playground IDE to browsers requesting
+	// HTML with GET, unless GraphiQL is also enabled.
+	Playground bool
+
+	// RootObjectFn returns the root object of the operations of a request.
+	RootObjectFn RootObjectFn
+
+	// ContextFn returns the context of the operations of a request. It
+	// defaults to the context of the request.
+	ContextFn ContextFn
+
+	// PersistedQueryStore enables automatic persisted queries, storing their
+	// query texts.
+	PersistedQueryStore graphql.PersistedQueryStore
+
+	// ErrorFormatter rewrites the errors of the responses, e.g.
+	// graphql.MaskErrors to mask the internal errors.
+	ErrorFormatter graphql.ErrorFormatter
+
+	// MaxBodySize bounds the size of the bodies of POST requests in bytes.
+	// It defaults to DefaultMaxBodySize.
+	MaxBodySize int64
+}
+
+// DefaultMaxBodySize is the default size limit of the bodies of POST
+// requests, 1MB.
+const DefaultMaxBodySize = 1 << 20
+
+// Handler is an http.Handler executing GraphQL operations.
+type Handler struct {
+	schema       *graphql.Schema
+	pretty       bool
+	graphiql     bool
+	playground   bool
+	rootObjectFn RootObjectFn
+	contextFn    ContextFn
+	queryStore   graphql.PersistedQueryStore
+	formatter    graphql.ErrorFormatter
+	maxBodySize  int64
+}
+
+// New returns a Handler with the given configuration.
+func New(c Config) *Handler {
+	h := &Handler{
+		schema:       c.Schema,
+		pretty:       c.Pretty,
+		graphiql:     c.GraphiQL,
+		playground:   c.Playground,
+		rootObjectFn: c.RootObjectFn,
+		contextFn:    c.ContextFn,
+		queryStore:   c.PersistedQueryStore,
+		formatter:    c.ErrorFormatter,
+		maxBodySize:  c.MaxBodySize,
+	}
+	if h.maxBodySize <= 0 {
+		h.maxBodySize = DefaultMaxBodySize
+	}
+	return h
+}
+
+// ServeHTTP executes the operations of the request.
+//
+// The response has status 200 when the operation was executed, even if some
+// fields failed, and status 400 when it could not be executed because the
+// request or the document was invalid. Mutations sent with GET are rejected
+// with status 405. A batch is answered with status 200 as soon as it is
+// well-formed, each of its results holding its own errors. Persisted queries
+// which are not found are answered with status 200, as clients then send the
+// query text again. Bodies larger than the MaxBodySize of the configuration
+// are rejected with status 413.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodGet && acceptsHTML(r) && r.URL.Query().Get("raw") == "" {
+		switch {
+		case h.graphiql:
+			renderGraphiQL(w, r)
+			return
+		case h.playground:
+			renderPlayground(w, r)
+			return
+		}
+	}
+
+	ctx := r.Context()
+	if h.contextFn != nil {
+		ctx = h.contextFn(r)
+	}
+
+	if r.Body != nil {
+		r.Body = newLimitedBody(r.Body, h.maxBodySize)
+	}
+	operations, batch, reqErr := parseRequest(r)
+	if reqErr != nil {
+		switch reqErr.status {
+		case http.StatusMethodNotAllowed:
+			w.Header().Set("Allow", "GET, POST")
+		case http.StatusRequestEntityTooLarge:
+			// the rest of the body is not read
+			w.Header().Set("Connection", "close")
+		}
+		h.writeJSON(w, reqErr.status, h.errorResult(reqErr))
+		return
+	}
+
+	if batch {
+		results := make([]*graphql.Result, len(operations))
+		for i, opts := range operations {
+			results[i], _ = h.execute(ctx, r, opts)
+		}
+		h.writeJSON(w, http.StatusOK, results)
+		return
+	}
+
+	result, status := h.execute(ctx, r, operations[0])
+	if status == http.StatusMethodNotAllowed {
+		w.Header().Set("Allow", "POST")
+	}
+	h.writeJSON(w, status, result)
+}
+
+// execute executes an operation, returning its result along with the status
+// code of the response.
+func (h *Handler) execute(ctx context.Context, r *http.Request, opts *RequestOptions) (*graphql.Result, int) {
+	query := opts.Query
+	// persisted queries are loaded here, so that mutations are rejected
+	// whether they are sent by hash or not
+	if persistedQuery := opts.persistedQuery(); persistedQuery != nil {
+		var err error
+		query, err = graphql.LoadPersistedQuery(ctx, h.queryStore, persistedQuery, query)
+		if err != nil {
+			status := http.StatusBadRequest
+			if err == graphql.ErrPersistedQueryNotFound || err == graphql.ErrPersistedQueryNotSupported {
+				status = http.StatusOK
+			}
+			return h.errorResult(err), status
+		}
+	}
+
+	// mutations sent with GET are rejected while validating the document,
+	// which is then parsed once, or found in the DocumentCache
+	var validationRules []graphql.ValidationRuleFn
+	mutation := false
+	if r.Method == http.MethodGet {
+		validationRules = []graphql.ValidationRuleFn{noMutationRule(opts.OperationName, &mutation)}
+	}
+
+	var rootObject map[string]interface{}
+	if h.rootObjectFn != nil {
+		rootObject = h.rootObjectFn(ctx, r)
+	}
+
+	result := graphql.Do(graphql.Params{
+		Schema:          *h.schema,
+		RequestString:   query,
+		RootObject:      rootObject,
+		VariableValues:  opts.Variables,
+		OperationName:   opts.OperationName,
+		Context:         ctx,
+		ErrorFormatter:  h.formatter,
+		ValidationRules: validationRules,
+	})
+	if mutation {
+		return h.errorResult(errMutationFromGET), http.StatusMethodNotAllowed
+	}
+	if result.Data == nil && result.HasErrors() {
+		return result, http.StatusBadRequest
+	}
+	return result, http.StatusOK
+}
+
+// errorResult returns the result of an operation which could not be executed
+// because of err, rewritten by the ErrorFormatter like the results of the
+// executed operations.
+func (h *Handler) errorResult(err error) *graphql.Result {
+	result := &graphql.Result{
+		Errors: gqlerrors.FormatErrors(err),
+	}
+	if h.formatter != nil {
+		for i, err := range result.Errors {
+			result.Errors[i] = h.formatter(err)
+		}
+	}
+	return result
+}
+
+func (h *Handler) writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	var (
+		b   []byte
+		err error
+	)
+	if h.pretty {
+		b, err = json.MarshalIndent(v, "", "\t")
+	} else {
+		b, err = json.Marshal(v)
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	w.Write(b)
+}
+
+func acceptsHTML(r *http.Request) bool {
+	for _, accept := range r.Header["Accept"] {
+		if strings.Contains(accept, "text/html") {
+			return true
+		}
+	}
+	return false
+}
+
+// errMutationFromGET is the error of the mutations sent with GET.
+var errMutationFromGET = gqlerrors.NewFormattedError(
+	"Can only perform a mutation operation from a POST request.",
+).WithCode(gqlerrors.CodeBadUserInput)
+
+// noMutationRule rejects the document when the operation to execute is a
+// mutation, setting *mutation to true.
+func noMutationRule(operationName string, mutation *bool) graphql.ValidationRuleFn {
+	return func(context *graphql.ValidationContext) *graphql.ValidationRuleInstance {
+		if isMutation(context.Document(), operationName) {
+			*mutation = true
+			context.ReportError(errMutationFromGET)
+		}
+		return &graphql.ValidationRuleInstance{
+			VisitorOpts: &visitor.VisitorOptions{},
+		}
+	}
+}
+
+// isMutation reports whether the operation to execute is a mutation.
+func isMutation(doc *ast.Document, operationName string) bool {
+	for _, definition := range doc.Definitions {
+		operation, ok := definition.(*ast.OperationDefinition)
+		if !ok {
+			continue
+		}
+		if operationName == "" || (operation.Name != nil && operation.Name.Value == operationName) {
+			return operation.Operation == ast.OperationTypeMutation
+		}
+	}
+	return false
+}
@@ -0,0 +1,389 @@
+package handler_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/handler"
+	"github.com/graphql-go/graphql/testutil"
+)
+
+type contextKey string
+
+var schema, _ = graphql.NewSchema(graphql.SchemaConfig{
+	Query: graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"hello": &graphql.Field{
+				Type: graphql.String,
+				Args: graphql.FieldConfigArgument{
+					"name": &graphql.ArgumentConfig{
+						Type:         graphql.String,
+						DefaultValue: "world",
+					},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return "hello " + p.Args["name"].(string), nil
+				},
+			},
+			"user": &graphql.Field{
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Context.Value(contextKey("user")), nil
+				},
+			},
+			"failing": &graphql.Field{
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return nil, errors.New("connection refused")
+				},
+			},
+			"version": &graphql.Field{
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Info.RootValue.(map[string]interface{})["version"], nil
+				},
+			},
+		},
+	}),
+	Mutation: graphql.NewObject(graphql.ObjectConfig{
+		Name: "Mutation",
+		Fields: graphql.Fields{
+			"ping": &graphql.Field{
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return "pong", nil
+				},
+			},
+		},
+	}),
+})
+
+func serve(h http.Handler, req *http.Request) *httptest.ResponseRecorder {
+	resp := httptest.NewRecorder()
+	h.ServeHTTP(resp, req)
+	return resp
+}
+
+func expectResponse(t *testing.T, resp *httptest.ResponseRecorder, status int, body string) {
+	if resp.Code != status {
+		t.Fatalf("Unexpected status code %v, body: %s", resp.Code, resp.Body.String())
+	}
+	if contentType := resp.Header().Get("Content-Type"); contentType != "application/json; charset=utf-8" {
+		t.Fatalf("Unexpected content type: %v", contentType)
+	}
+	var expected, got interface{}
+	if err := json.Unmarshal([]byte(body), &expected); err != nil {
+		t.Fatalf("Invalid expected body: %v", err)
+	}
+	if err := json.Unmarshal(resp.Body.Bytes(), &got); err != nil {
+		t.Fatalf("Invalid body: %v", err)
+	}
+	if !reflect.DeepEqual(expected, got) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, got))
+	}
+}
+
+func TestHandler_AcceptsOperationsInAllEncodings(t *testing.T) {
+	h := handler.New(handler.Config{Schema: &schema})
+	query := `query Hello($name: String) { hello(name: $name) }`
+	variables := `{"name": "luke"}`
+	expected := `{"data": {"hello": "hello luke"}}`
+
+	params := url.Values{}
+	params.Set("query", query)
+	params.Set("variables", variables)
+	params.Set("operationName", "Hello")
+
+	multipartBody := &bytes.Buffer{}
+	writer := multipart.NewWriter(multipartBody)
+	writer.WriteField("query", query)
+	writer.WriteField("variables", variables)
+	writer.Close()
+
+	tests := map[string]*http.Request{
+		"GET": httptest.NewRequest(http.MethodGet, "/graphql?"+params.Encode(), nil),
+		"POST application/json": httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(
+			`{"query": "query Hello($name: String) { hello(name: $name) }", "variables": {"name": "luke"}}`,
+		)),
+		"POST application/graphql":               httptest.NewRequest(http.MethodPost, "/graphql?variables="+url.QueryEscape(variables), strings.NewReader(query)),
+		"POST application/x-www-form-urlencoded": httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(params.Encode())),
+		"POST multipart/form-data":               httptest.NewRequest(http.MethodPost, "/graphql", multipartBody),
+	}
+	tests["POST application/json"].Header.Set("Content-Type", "application/json")
+	tests["POST application/graphql"].Header.Set("Content-Type", "application/graphql")
+	tests["POST application/x-www-form-urlencoded"].Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	tests["POST multipart/form-data"].Header.Set("Content-Type", writer.FormDataContentType())
+
+	for name, req := range tests {
+		t.Run(name, func(t *testing.T) {
+			expectResponse(t, serve(h, req), http.StatusOK, expected)
+		})
+	}
+}
+
+func TestHandler_ExecutesBatches(t *testing.T) {
+	h := handler.New(handler.Config{Schema: &schema})
+	req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(`[
+		{"query": "{ hello }"},
+		{"query": "query Hello($name: String) { hello(name: $name) }", "variables": {"name": "leia"}},
+		{"query": "{ unknown }"}
+	]`))
+	req.Header.Set("Content-Type", "application/json")
+	expectResponse(t, serve(h, req), http.StatusOK, `[
+		{"data": {"hello": "hello world"}},
+		{"data": {"hello": "hello leia"}},
+		{"data": null, "errors": [{"message": "Cannot query field \"unknown\" on type \"Query\".", "locations": [{"line": 1, "column": 3}], "extensions": {"code": "GRAPHQL_VALIDATION_FAILED", "rule": "FieldsOnCorrectTypeRule"}}]}
+	]`)
+}
+
+func TestHandler_RejectsMutationsOverGET(t *testing.T) {
+	h := handler.New(handler.Config{Schema: &schema})
+
+	resp := serve(h, httptest.NewRequest(http.MethodGet, "/graphql?query="+url.QueryEscape("mutation { ping }"), nil))
+	expectResponse(t, resp, http.StatusMethodNotAllowed, `{
+		"data": null,
+		"errors": [{"message": "Can only perform a mutation operation from a POST request.", "locations": [], "extensions": {"code": "BAD_USER_INPUT"}}]
+	}`)
+	if allow := resp.Header().Get("Allow"); allow != "POST" {
+		t.Fatalf("Unexpected Allow header: %v", allow)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(`{"query": "mutation { ping }"}`))
+	req.Header.Set("Content-Type", "application/json")
+	expectResponse(t, serve(h, req), http.StatusOK, `{"data": {"ping": "pong"}}`)
+
+	// the document is now cached, and still rejected
+	resp = serve(h, httptest.NewRequest(http.MethodGet, "/graphql?query="+url.QueryEscape("mutation { ping }"), nil))
+	expectResponse(t, resp, http.StatusMethodNotAllowed, `{
+		"data": null,
+		"errors": [{"message": "Can only perform a mutation operation from a POST request.", "locations": [], "extensions": {"code": "BAD_USER_INPUT"}}]
+	}`)
+
+	query := "query Hello { hello } mutation Ping { ping }"
+	resp = serve(h, httptest.NewRequest(http.MethodGet, "/graphql?operationName=Hello&query="+url.QueryEscape(query), nil))
+	expectResponse(t, resp, http.StatusOK, `{"data": {"hello": "hello world"}}`)
+}
+
+func TestHandler_MapsErrorsToStatusCodes(t *testing.T) {
+	h := handler.New(handler.Config{Schema: &schema})
+	tests := []struct {
+		name        string
+		method      string
+		contentType string
+		body        string
+		status      int
+		message     string
+	}{
+		{"missing query", http.MethodPost, "application/json", `{}`, http.StatusBadRequest, "Must provide query string."},
+		{"invalid JSON", http.MethodPost, "application/json", `{`, http.StatusBadRequest, "POST body sent invalid JSON."},
+		{"empty batch", http.MethodPost, "application/json", `[]`, http.StatusBadRequest, "Must provide at least one operation."},
+		{"unsupported content type", http.MethodPost, "text/plain", `{ hello }`, http.StatusUnsupportedMediaType, "Unsupported Content-Type, expected one of application/json, application/graphql, application/x-www-form-urlencoded or multipart/form-data."},
+		{"unsupported method", http.MethodPut, "application/json", `{"query": "{ hello }"}`, http.StatusMethodNotAllowed, "GraphQL only supports GET and POST requests."},
+		{"syntax error", http.MethodPost, "application/graphql", `{ hello `, http.StatusBadRequest, "Syntax Error GraphQL request (1:9) Expected Name, found EOF\n\n1: { hello \n           ^\n"},
+		{"validation error", http.MethodPost, "application/graphql", `{ unknown }`, http.StatusBadRequest, `Cannot query field "unknown" on type "Query".`},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := httptest.NewRequest(test.method, "/graphql", strings.NewReader(test.body))
+			req.Header.Set("Content-Type", test.contentType)
+			resp := serve(h, req)
+			if resp.Code != test.status {
+				t.Fatalf("Unexpected status code %v, body: %s", resp.Code, resp.Body.String())
+			}
+			var result struct {
+				Errors []struct{ Message string }
+			}
+			if err := json.Unmarshal(resp.Body.Bytes(), &result); err != nil {
+				t.Fatalf("Invalid body: %v", err)
+			}
+			if len(result.Errors) != 1 || result.Errors[0].Message != test.message {
+				t.Fatalf("Unexpected errors: %s", resp.Body.String())
+			}
+		})
+	}
+}
+
+func TestHandler_UsesContextFnAndRootObjectFn(t *testing.T) {
+	h := handler.New(handler.Config{
+		Schema: &schema,
+		ContextFn: func(r *http.Request) context.Context {
+			return context.WithValue(r.Context(), contextKey("user"), r.Header.Get("X-User"))
+		},
+		RootObjectFn: func(ctx context.Context, r *http.Request) map[string]interface{} {
+			return map[string]interface{}{"version": "1.0"}
+		},
+	})
+	req := httptest.NewRequest(http.MethodGet, "/graphql?query="+url.QueryEscape("{ user version }"), nil)
+	req.Header.Set("X-User", "luke")
+	expectResponse(t, serve(h, req), http.StatusOK, `{"data": {"user": "luke", "version": "1.0"}}`)
+}
+
+func TestHandler_IndentsPrettyResponses(t *testing.T) {
+	h := handler.New(handler.Config{Schema: &schema, Pretty: true})
+	resp := serve(h, httptest.NewRequest(http.MethodGet, "/graphql?query="+url.QueryEscape("{ hello }"), nil))
+	expected := "{\n\t\"data\": {\n\t\t\"hello\": \"hello world\"\n\t}\n}"
+	if resp.Body.String() != expected {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, resp.Body.String()))
+	}
+}
+
+func TestHandler_ServesIDEsToBrowsers(t *testing.T) {
+	tests := map[string]struct {
+		config   handler.Config
+		expected string
+	}{
+		"GraphiQL":   {handler.Config{Schema: &schema, GraphiQL: true, Playground: true}, "<title>GraphiQL</title>"},
+		"Playground": {handler.Config{Schema: &schema, Playground: true}, "<title>GraphQL Playground</title>"},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			h := handler.New(test.config)
+
+			req := httptest.NewRequest(http.MethodGet, "/graphql", nil)
+			req.Header.Set("Accept", "text/html")
+			resp := serve(h, req)
+			if resp.Code != http.StatusOK || !strings.Contains(resp.Body.String(), test.expected) {
+				t.Fatalf("Unexpected response %v: %s", resp.Code, resp.Body.String())
+			}
+
+			req = httptest.NewRequest(http.MethodGet, "/graphql?raw=true&query="+url.QueryEscape("{ hello }"), nil)
+			req.Header.Set("Accept", "text/html")
+			expectResponse(t, serve(h, req), http.StatusOK, `{"data": {"hello": "hello world"}}`)
+		})
+	}
+}
+
+func TestHandler_SupportsAutomaticPersistedQueries(t *testing.T) {
+	h := handler.New(handler.Config{
+		Schema:              &schema,
+		PersistedQueryStore: graphql.NewInMemoryPersistedQueryStore(10),
+	})
+	query := "{ hello }"
+	sum := sha256.Sum256([]byte(query))
+	extensions := `{"persistedQuery": {"version": 1, "sha256Hash": "` + hex.EncodeToString(sum[:]) + `"}}`
+	get := func() *http.Request {
+		return httptest.NewRequest(http.MethodGet, "/graphql?extensions="+url.QueryEscape(extensions), nil)
+	}
+
+	expectResponse(t, serve(h, get()), http.StatusOK, `{
+		"data": null,
+		"errors": [{"message": "PersistedQueryNotFound", "locations": [], "extensions": {"code": "PERSISTED_QUERY_NOT_FOUND"}}]
+	}`)
+
+	req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(
+		`{"query": "{ hello }", "extensions": `+extensions+`}`,
+	))
+	req.Header.Set("Content-Type", "application/json")
+	expectResponse(t, serve(h, req), http.StatusOK, `{"data": {"hello": "hello world"}}`)
+
+	expectResponse(t, serve(h, get()), http.StatusOK, `{"data": {"hello": "hello world"}}`)
+
+	req = httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(
+		`{"query": "{ user }", "extensions": `+extensions+`}`,
+	))
+	req.Header.Set("Content-Type", "application/json")
+	expectResponse(t, serve(h, req), http.StatusBadRequest, `{
+		"data": null,
+		"errors": [{"message": "provided sha does not match query", "locations": [], "extensions": {"code": "BAD_USER_INPUT"}}]
+	}`)
+}
+
+func TestHandler_FormatsErrorsWithErrorFormatter(t *testing.T) {
+	var logged []string
+	h := handler.New(handler.Config{
+		Schema: &schema,
+		ErrorFormatter: graphql.MaskErrors(graphql.ErrorLoggerFunc(func(correlationID string, err error, stack []byte) {
+			logged = append(logged, correlationID)
+		})),
+	})
+	resp := serve(h, httptest.NewRequest(http.MethodGet, "/graphql?query="+url.QueryEscape("{ failing }"), nil))
+	if len(logged) != 1 {
+		t.Fatalf("Expected a single logged error, got %v: %s", logged, resp.Body.String())
+	}
+	expectResponse(t, resp, http.StatusOK, `{
+		"data": {"failing": null},
+		"errors": [{
+			"message": "Internal server error",
+			"locations": [{"line": 1, "column": 3}],
+			"path": ["failing"],
+			"extensions": {"code": "INTERNAL_SERVER_ERROR", "correlationId": "`+logged[0]+`"}
+		}]
+	}`)
+}
+
+func TestHandler_FormatsRequestErrorsWithErrorFormatter(t *testing.T) {
+	h := handler.New(handler.Config{
+		Schema:              &schema,
+		PersistedQueryStore: graphql.NewInMemoryPersistedQueryStore(10),
+		ErrorFormatter: func(err gqlerrors.FormattedError) gqlerrors.FormattedError {
+			return err.WithExtension("formatted", true)
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(`{`))
+	req.Header.Set("Content-Type", "application/json")
+	expectResponse(t, serve(h, req), http.StatusBadRequest, `{
+		"data": null,
+		"errors": [{"message": "POST body sent invalid JSON.", "locations": [], "extensions": {"code": "BAD_USER_INPUT", "formatted": true}}]
+	}`)
+
+	expectResponse(t, serve(h, httptest.NewRequest(http.MethodGet, "/graphql?query="+url.QueryEscape("mutation { ping }"), nil)), http.StatusMethodNotAllowed, `{
+		"data": null,
+		"errors": [{"message": "Can only perform a mutation operation from a POST request.", "locations": [], "extensions": {"code": "BAD_USER_INPUT", "formatted": true}}]
+	}`)
+
+	extensions := `{"persistedQuery": {"version": 1, "sha256Hash": "unknown"}}`
+	expectResponse(t, serve(h, httptest.NewRequest(http.MethodGet, "/graphql?extensions="+url.QueryEscape(extensions), nil)), http.StatusOK, `{
+		"data": null,
+		"errors": [{"message": "PersistedQueryNotFound", "locations": [], "extensions": {"code": "PERSISTED_QUERY_NOT_FOUND", "formatted": true}}]
+	}`)
+}
+
+func TestHandler_RejectsLargeBodies(t *testing.T) {
+	h := handler.New(handler.Config{Schema: &schema, MaxBodySize: 32})
+	query := `{"query": "{ hello }"}`
+	multipartBody := &bytes.Buffer{}
+	writer := multipart.NewWriter(multipartBody)
+	writer.WriteField("query", `{ hello(name: "`+strings.Repeat("a", 32)+`") }`)
+	writer.Close()
+	for _, test := range []struct {
+		contentType string
+		body        string
+	}{
+		{"application/json", `{"query": "{ hello(name: \"` + strings.Repeat("a", 32) + `\") }"}`},
+		{"application/graphql", `{ hello(name: "` + strings.Repeat("a", 32) + `") }`},
+		{"application/x-www-form-urlencoded", "query=" + url.QueryEscape(`{ hello(name: "`+strings.Repeat("a", 32)+`") }`)},
+		{writer.FormDataContentType(), multipartBody.String()},
+	} {
+		t.Run(test.contentType, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(test.body))
+			req.Header.Set("Content-Type", test.contentType)
+			resp := serve(h, req)
+			expectResponse(t, resp, http.StatusRequestEntityTooLarge, `{
+				"data": null,
+				"errors": [{"message": "Request body is too large.", "locations": [], "extensions": {"code": "BAD_USER_INPUT"}}]
+			}`)
+			if connection := resp.Header().Get("Connection"); connection != "close" {
+				t.Fatalf("Unexpected Connection header: %v", connection)
+			}
+		})
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(query))
+	req.Header.Set("Content-Type", "application/json")
+	expectResponse(t, serve(h, req), http.StatusOK, `{"data": {"hello": "hello world"}}`)
+}
@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"html/template"
+	"net/http"
+)
+
+// ideData is the data rendered into the pages of the IDEs.
+type ideData struct {
+	Endpoint      string
+	Query         string
+	Variables     string
+	OperationName string
+}
+
+func newIDEData(r *http.Request) ideData {
+	values := r.URL.Query()
+	return ideData{
+		Endpoint:      r.URL.Path,
+		Query:         values.Get("query"),
+		Variables:     values.Get("variables"),
+		OperationName: values.Get("operationName"),
+	}
+}
+
+func renderGraphiQL(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	graphiqlTemplate.Execute(w, newIDEData(r))
+}
+
+func renderPlayground(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	playgroundTemplate.Execute(w, newIDEData(r))
+}
+
+var graphiqlTemplate = template.Must(template.New("graphiql").Parse(`<!DOCTYPE html>
+<html>
+<head>
+  <meta charset="utf-8" />
+  <title>GraphiQL</title>
+  <style>
+    body { height: 100vh; margin: 0; overflow: hidden; }
+    #graphiql { height: 100vh; }
+  </style>
+  <link rel="stylesheet" href="https://unpkg.com/graphiql@3/graphiql.min.css" />
+  <script crossorigin src="https://unpkg.com/react@18/umd/react.production.min.js"></script>
+  <script crossorigin src="https://unpkg.com/react-dom@18/umd/react-dom.production.min.js"></script>
+  <script crossorigin src="https://unpkg.com/graphiql@3/graphiql.min.js"></script>
+</head>
+<body>
+  <div id="graphiql">Loading...</div>
+  <script>
+    var root = ReactDOM.createRoot(document.getElementById('graphiql'));
+    root.render(React.createElement(GraphiQL, {
+      fetcher: GraphiQL.createFetcher({ url: {{.Endpoint}} }),
+      defaultQuery: {{.Query}} || undefined,
+      variables: {{.Variables}} || undefined,
+      operationName: {{.OperationName}} || undefined
+    }));
+  </script>
+</body>
+</html>
+`))
+
+var playgroundTemplate = template.Must(template.New("playground").Parse(`<!DOCTYPE html>
+<html>
+<head>
+  <meta charset="utf-8" />
+  <meta name="viewport" content="user-scalable=no, initial-scale=1.0, minimum-scale=1.0, maximum-scale=1.0, minimal-ui" />
+  <title>GraphQL Playground</title>
+  <link rel="stylesheet" href="https://cdn.jsdelivr.net/npm/graphql-playground-react/build/static/css/index.css" />
+  <script src="https://cdn.jsdelivr.net/npm/graphql-playground-react/build/static/js/middleware.js"></script>
+</head>
+<body>
+  <div id="root"></div>
+  <script>
+    window.addEventListener('load', function () {
+      GraphQLPlayground.init(document.getElementById('root'), {
+        endpoint: {{.Endpoint}},
+        tabs: {{.Query}} ? [{ endpoint: {{.Endpoint}}, query: {{.Query}}, variables: {{.Variables}} }] : undefined
+      });
+    });
+  </script>
+</body>
+</html>
+`))
@@ -0,0 +1,251 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"net/url"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+)
+
+// The content types of the request bodies understood by the handler.
+const (
+	ContentTypeJSON           = "application/json"
+	ContentTypeGraphQL        = "application/graphql"
+	ContentTypeFormURLEncoded = "application/x-www-form-urlencoded"
+	ContentTypeMultipartForm  = "multipart/form-data"
+)
+
+// maxMemory is the size of the parts of multipart forms kept in memory.
+const maxMemory = 32 << 20
+
+// RequestOptions is an operation sent in a request.
+type RequestOptions struct {
+	Query         string                 `json:"query"`
+	Variables     map[string]interface{} `json:"variables"`
+	OperationName string                 `json:"operationName"`
+	Extensions    map[string]interface{} `json:"extensions"`
+}
+
+// persistedQuery returns the persistedQuery extension of the operation, if
+// any.
+func (opts *RequestOptions) persistedQuery() *graphql.PersistedQuery {
+	extension, ok := opts.Extensions["persistedQuery"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	persistedQuery := &graphql.PersistedQuery{}
+	if version, ok := extension["version"].(float64); ok {
+		persistedQuery.Version = int(version)
+	}
+	persistedQuery.Sha256Hash, _ = extension["sha256Hash"].(string)
+	return persistedQuery
+}
+
+// hasQuery reports whether the operation has a query text, or the hash of a
+// persisted query.
+func (opts *RequestOptions) hasQuery() bool {
+	return opts.Query != "" || opts.persistedQuery() != nil
+}
+
+// requestError is an error in the request itself, reported with the given
+// status code.
+type requestError struct {
+	status  int
+	message string
+}
+
+func (e *requestError) Error() string {
+	return e.message
+}
+
+// Extensions implements gqlerrors.ExtendedError.
+func (e *requestError) Extensions() map[string]interface{} {
+	return map[string]interface{}{"code": gqlerrors.CodeBadUserInput}
+}
+
+func badRequest(message string) *requestError {
+	return &requestError{status: http.StatusBadRequest, message: message}
+}
+
+// errBodyTooLarge is the error of the reads of a body past its size limit.
+var errBodyTooLarge = errors.New("request body too large")
+
+// limitedBody is a request body which fails with errBodyTooLarge once more
+// than n bytes are read from it. Unlike http.MaxBytesReader, its error can be
+// told apart from the other errors before Go 1.19.
+type limitedBody struct {
+	body io.ReadCloser
+	// n is the number of bytes which can still be read
+	n   int64
+	err error
+}
+
+func newLimitedBody(body io.ReadCloser, n int64) *limitedBody {
+	return &limitedBody{body: body, n: n}
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if b.err != nil {
+		return 0, b.err
+	}
+	if len(p) == 0 {
+		return 0, nil
+	}
+	// read one byte more than allowed to tell whether the body is too large
+	if int64(len(p)) > b.n+1 {
+		p = p[:b.n+1]
+	}
+	n, err := b.body.Read(p)
+	if int64(n) <= b.n {
+		b.n -= int64(n)
+		b.err = err
+		return n, err
+	}
+	n = int(b.n)
+	b.n = 0
+	b.err = errBodyTooLarge
+	return n, b.err
+}
+
+func (b *limitedBody) Close() error {
+	return b.body.Close()
+}
+
+// bodyError returns the error of a request whose body could not be read or
+// parsed because of err, which is reported with status 413 once the body
+// exceeds the limit of its limitedBody.
+func bodyError(err error, message string) *requestError {
+	if errors.Is(err, errBodyTooLarge) {
+		return &requestError{
+			status:  http.StatusRequestEntityTooLarge,
+			message: "Request body is too large.",
+		}
+	}
+	return badRequest(message)
+}
+
+// parseRequest returns the operations sent in the request, and whether they
+// were sent as a batch.
+func parseRequest(r *http.Request) ([]*RequestOptions, bool, *requestError) {
+	switch r.Method {
+	case http.MethodGet:
+		opts, reqErr := optionsFromValues(r.URL.Query())
+		if reqErr != nil {
+			return nil, false, reqErr
+		}
+		return []*RequestOptions{opts}, false, nil
+	case http.MethodPost:
+		return parsePostRequest(r)
+	default:
+		return nil, false, &requestError{
+			status:  http.StatusMethodNotAllowed,
+			message: "GraphQL only supports GET and POST requests.",
+		}
+	}
+}
+
+func parsePostRequest(r *http.Request) ([]*RequestOptions, bool, *requestError) {
+	contentType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		return nil, false, &requestError{
+			status:  http.StatusUnsupportedMediaType,
+			message: "Invalid Content-Type header.",
+		}
+	}
+
+	switch contentType {
+	case ContentTypeJSON:
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			return nil, false, bodyError(err, "Could not read the request body.")
+		}
+		body = bytes.TrimSpace(body)
+		if len(body) > 0 && body[0] == '[' {
+			var batch []*RequestOptions
+			if err := json.Unmarshal(body, &batch); err != nil {
+				return nil, false, badRequest("POST body sent invalid JSON.")
+			}
+			if len(batch) == 0 {
+				return nil, false, badRequest("Must provide at least one operation.")
+			}
+			for _, opts := range batch {
+				if opts == nil || !opts.hasQuery() {
+					return nil, false, badRequest("Must provide query string.")
+				}
+			}
+			return batch, true, nil
+		}
+		var opts RequestOptions
+		if err := json.Unmarshal(body, &opts); err != nil {
+			return nil, false, badRequest("POST body sent invalid JSON.")
+		}
+		if !opts.hasQuery() {
+			return nil, false, badRequest("Must provide query string.")
+		}
+		return []*RequestOptions{&opts}, false, nil
+
+	case ContentTypeGraphQL:
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			return nil, false, bodyError(err, "Could not read the request body.")
+		}
+		values := r.URL.Query()
+		values.Set("query", string(body))
+		opts, reqErr := optionsFromValues(values)
+		if reqErr != nil {
+			return nil, false, reqErr
+		}
+		return []*RequestOptions{opts}, false, nil
+
+	case ContentTypeFormURLEncoded, ContentTypeMultipartForm:
+		if contentType == ContentTypeMultipartForm {
+			err = r.ParseMultipartForm(maxMemory)
+		} else {
+			err = r.ParseForm()
+		}
+		if err != nil {
+			return nil, false, bodyError(err, "Could not parse the form.")
+		}
+		opts, reqErr := optionsFromValues(r.PostForm)
+		if reqErr != nil {
+			return nil, false, reqErr
+		}
+		return []*RequestOptions{opts}, false, nil
+
+	default:
+		return nil, false, &requestError{
+			status:  http.StatusUnsupportedMediaType,
+			message: "Unsupported Content-Type, expected one of " + ContentTypeJSON + ", " + ContentTypeGraphQL + ", " + ContentTypeFormURLEncoded + " or " + ContentTypeMultipartForm + ".",
+		}
+	}
+}
+
+// optionsFromValues reads an operation from the parameters of a URL or a form,
+// where variables and extensions are encoded in JSON.
+func optionsFromValues(values url.Values) (*RequestOptions, *requestError) {
+	opts := &RequestOptions{
+		Query:         values.Get("query"),
+		OperationName: values.Get("operationName"),
+	}
+	if variables := values.Get("variables"); variables != "" {
+		if err := json.Unmarshal([]byte(variables), &opts.Variables); err != nil {
+			return nil, badRequest("Variables are invalid JSON.")
+		}
+	}
+	if extensions := values.Get("extensions"); extensions != "" {
+		if err := json.Unmarshal([]byte(extensions), &opts.Extensions); err != nil {
+			return nil, badRequest("Extensions are invalid JSON.")
+		}
+	}
+	if !opts.hasQuery() {
+		return nil, badRequest("Must provide query string.")
+	}
+	return opts, nil
+}
@@ -0,0 +1,414 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// DeferDirective is used to defer the delivery of fragments with
+// ExecuteIncremental. It is not one of the SpecifiedDirectives, so it must be
+// added to the directives of the schema to be used.
+var DeferDirective = NewDirective(DirectiveConfig{
+	Name: "defer",
+	Description: "Directs the executor to deliver this fragment after the initial " +
+		"result when the `if` argument is true.",
+	Args: FieldConfigArgument{
+		"if": &ArgumentConfig{
+			Type:         Boolean,
+			Description:  "Deferred when true.",
+			DefaultValue: true,
+		},
+		"label": &ArgumentConfig{
+			Type:        String,
+			Description: "Unique name identifying the payload of the deferred fragment.",
+		},
+	},
+	Locations: []string{
+		DirectiveLocationFragmentSpread,
+		DirectiveLocationInlineFragment,
+	},
+})
+
+// StreamDirective is used to deliver the items of a list field one at a time
+// with ExecuteIncremental. It is not one of the SpecifiedDirectives, so it must
+// be added to the directives of the schema to be used.
+var StreamDirective = NewDirective(DirectiveConfig{
+	Name: "stream",
+	Description: "Directs the executor to deliver the items of this list field after " +
+		"the initial result when the `if` argument is true.",
+	Args: FieldConfigArgument{
+		"if": &ArgumentConfig{
+			Type:         Boolean,
+			Description:  "Streamed when true.",
+			DefaultValue: true,
+		},
+		"label": &ArgumentConfig{
+			Type:        String,
+			Description: "Unique name identifying the payloads of the streamed items.",
+		},
+		"initialCount": &ArgumentConfig{
+			Type:         Int,
+			Description:  "Number of items to deliver in the initial result.",
+			DefaultValue: 0,
+		},
+	},
+	Locations: []string{
+		DirectiveLocationField,
+	},
+})
+
+// IncrementalResult is one payload of the response to an operation executed
+// with ExecuteIncremental. The first payload holds the initial result, and
+// each following payload holds either the Data of a deferred fragment or the
+// Items of a streamed list, to be merged at Path, which is [] at the top
+// level. HasNext reports whether more payloads follow.
+type IncrementalResult struct {
+	Data       interface{}                `json:"data,omitempty"`
+	Items      []interface{}              `json:"items,omitempty"`
+	Path       []interface{}              `json:"path"`
+	Label      string                     `json:"label,omitempty"`
+	Errors     []gqlerrors.FormattedError `json:"errors,omitempty"`
+	Extensions map[string]interface{}     `json:"extensions,omitempty"`
+	HasNext    bool                       `json:"hasNext"`
+}
+
+// MarshalJSON omits the path of the initial payload, which has none, while
+// the other payloads always have a path, even an empty one.
+func (r IncrementalResult) MarshalJSON() ([]byte, error) {
+	type payload IncrementalResult
+	if r.Path != nil {
+		return json.Marshal(payload(r))
+	}
+	return json.Marshal(struct {
+		payload
+		Path []interface{} `json:"path,omitempty"`
+	}{payload: payload(r)})
+}
+
+// ExecuteIncremental is similar to graphql.Execute but delivers the fragments
+// marked with @defer and the list items marked with @stream in payloads
+// following the initial result. The channel is closed after the payload whose
+// HasNext is false, or when the context is done. The extensions of the schema
+// finish once every payload is executed, their results being delivered in the
+// Extensions of the last payload.
+func ExecuteIncremental(p ExecuteParams) chan *IncrementalResult {
+	if p.Context == nil {
+		p.Context = context.Background()
+	}
+
+	resultChannel := make(chan *IncrementalResult)
+	go func() {
+		defer close(resultChannel)
+
+		send := func(result *IncrementalResult) bool {
+			select {
+			case <-p.Context.Done():
+				return false
+			case resultChannel <- result:
+				return true
+			}
+		}
+
+		publisher := &incrementalPublisher{}
+		result := execute(p, publisher)
+		publisher.initial.data = result.Data
+		publisher.prune()
+
+		// the extensions finish once every payload is executed, their
+		// errors and results being delivered with the last payload
+		finished := false
+		finish := func() {
+			finished = true
+			publisher.finish(result)
+		}
+		defer func() {
+			if !finished {
+				finish()
+			}
+		}()
+
+		hasNext := publisher.hasNext()
+		if !hasNext {
+			finish()
+		}
+		initial := &IncrementalResult{
+			Data:       result.Data,
+			Errors:     result.Errors,
+			Extensions: result.Extensions,
+			HasNext:    hasNext,
+		}
+		if !send(initial) {
+			return
+		}
+
+		for publisher.hasNext() {
+			if p.Context.Err() != nil {
+				return
+			}
+			subsequent := publisher.executeNext()
+			subsequent.HasNext = publisher.hasNext()
+			if !subsequent.HasNext {
+				errCount := len(result.Errors)
+				finish()
+				subsequent.Errors = append(subsequent.Errors, result.Errors[errCount:]...)
+				subsequent.Extensions = result.Extensions
+			}
+			if !send(subsequent) {
+				return
+			}
+		}
+	}()
+
+	return resultChannel
+}
+
+// deferredFragment holds the fields of a fragment marked with @defer.
+type deferredFragment struct {
+	label  string
+	fields map[string][]*ast.Field
+}
+
+// incrementalRecord is a payload delivered after the initial result.
+type incrementalRecord struct {
+	label string
+	path  *ResponsePath
+	// parent is the payload during which this payload was created
+	parent  *incrementalRecord
+	execute func(eCtx *executionContext) *IncrementalResult
+	eCtx    *executionContext
+	// data is the value at path once the payload has been executed
+	data interface{}
+}
+
+// incrementalPublisher queues the payloads created during execution. Payloads
+// are executed in order, one at a time, after the initial result.
+type incrementalPublisher struct {
+	initial incrementalRecord
+	pending []*incrementalRecord
+	// finish runs the finish funcs of the extensions and adds their results
+	// to the result of the execution
+	finish func(result *Result)
+}
+
+func (p *incrementalPublisher) add(eCtx *executionContext, record *incrementalRecord) {
+	record.parent = eCtx.IncrementalRecord
+	if record.parent == nil {
+		record.parent = &p.initial
+	}
+	record.eCtx = eCtx
+	p.pending = append(p.pending, record)
+}
+
+func (p *incrementalPublisher) hasNext() bool {
+	return len(p.pending) > 0
+}
+
+// executeNext executes the next pending payload, then discards the payloads
+// which can no longer be delivered.
+func (p *incrementalPublisher) executeNext() *IncrementalResult {
+	record := p.pending[0]
+	p.pending = p.pending[1:]
+
+	eCtx := *record.eCtx
+	eCtx.Errors = nil
+	eCtx.IncrementalRecord = record
+	result := record.execute(&eCtx)
+
+	p.prune()
+	return result
+}
+
+// prune discards the pending payloads whose parent object or list is null in
+// the payload they were created in, because an error nulled it.
+func (p *incrementalPublisher) prune() {
+	pending := []*incrementalRecord{}
+	for _, record := range p.pending {
+		if record.isReachable() {
+			pending = append(pending, record)
+		}
+	}
+	p.pending = pending
+}
+
+func (r *incrementalRecord) isReachable() bool {
+	if r.parent != &r.eCtx.Incremental.initial && !r.parent.isReachable() {
+		return false
+	}
+	keys := r.path.AsArray()[len(r.parent.path.AsArray()):]
+	value := r.parent.data
+	for _, key := range keys {
+		switch key := key.(type) {
+		case string:
+			m, ok := value.(map[string]interface{})
+			if !ok {
+				return false
+			}
+			value = m[key]
+		case int:
+			// Stream payloads are created for items which are not in the list yet
+			list, ok := value.([]interface{})
+			if !ok {
+				return false
+			}
+			if key >= len(list) {
+				return true
+			}
+			value = list[key]
+		}
+	}
+	return value != nil
+}
+
+// newDeferredFragments returns the list in which collectFields collects the
+// fragments marked with @defer, or nil if they are not to be deferred.
+func newDeferredFragments(eCtx *executionContext) *[]*deferredFragment {
+	if eCtx.Incremental == nil {
+		return nil
+	}
+	return &[]*deferredFragment{}
+}
+
+// getDeferLabel reports whether a fragment with the given directives is to be
+// deferred, and the label of its payload.
+func getDeferLabel(eCtx *executionContext, deferredFragments *[]*deferredFragment, directives []*ast.Directive) (string, bool) {
+	if deferredFragments == nil {
+		return "", false
+	}
+	argValues, ok := getExecutableDirectiveValues(eCtx, DeferDirective, directives)
+	if !ok {
+		return "", false
+	}
+	if deferIf, ok := argValues["if"].(bool); ok && !deferIf {
+		return "", false
+	}
+	label, _ := argValues["label"].(string)
+	return label, true
+}
+
+// getStreamValues reports whether the list of the given field is to be
+// streamed, along with the number of items to deliver in the current payload
+// and the label of the following payloads.
+func getStreamValues(eCtx *executionContext, fieldASTs []*ast.Field) (int, string, bool) {
+	if eCtx.Incremental == nil || len(fieldASTs) == 0 {
+		return 0, "", false
+	}
+	argValues, ok := getExecutableDirectiveValues(eCtx, StreamDirective, fieldASTs[0].Directives)
+	if !ok {
+		return 0, "", false
+	}
+	if streamIf, ok := argValues["if"].(bool); ok && !streamIf {
+		return 0, "", false
+	}
+	initialCount, _ := argValues["initialCount"].(int)
+	if err := invariant(initialCount >= 0, "initialCount must be a positive integer"); err != nil {
+		panic(gqlerrors.FormatError(err).WithCode(gqlerrors.CodeBadUserInput))
+	}
+	label, _ := argValues["label"].(string)
+	return initialCount, label, true
+}
+
+func getExecutableDirectiveValues(eCtx *executionContext, directive *Directive, directives []*ast.Directive) (map[string]interface{}, bool) {
+	for _, directiveAST := range directives {
+		if directiveAST == nil || directiveAST.Name == nil || directiveAST.Name.Value != directive.Name {
+			continue
+		}
+		return getArgumentValues(directive.Args, directiveAST.Arguments, eCtx.VariableValues), true
+	}
+	return nil, false
+}
+
+// deferFragments queues a payload for each of the deferred fragments of the
+// object at p.Path.
+func deferFragments(p executeFieldsParams, deferredFragments *[]*deferredFragment) {
+	if deferredFragments == nil {
+		return
+	}
+	for _, fragment := range *deferredFragments {
+		fields := fragment.fields
+		p.ExecutionContext.Incremental.add(p.ExecutionContext, &incrementalRecord{
+			label: fragment.label,
+			path:  p.Path,
+			execute: func(eCtx *executionContext) (result *IncrementalResult) {
+				record := eCtx.IncrementalRecord
+				result = &IncrementalResult{
+					// the path of a top-level fragment is [], not null
+					Path:  append([]interface{}{}, p.Path.AsArray()...),
+					Label: record.label,
+				}
+				defer func() {
+					if r := recover(); r != nil {
+						result.Data = nil
+						result.Errors = append(eCtx.Errors, formatRecovered(r))
+					}
+				}()
+
+				data := executeSubFields(executeFieldsParams{
+					ExecutionContext: eCtx,
+					ParentType:       p.ParentType,
+					Source:           p.Source,
+					Fields:           fields,
+					Path:             p.Path,
+				})
+				dethunkMapWithBreadthFirstTraversal(eCtx, data)
+
+				record.data = data
+				result.Data = data
+				result.Errors = eCtx.Errors
+				return result
+			},
+		})
+	}
+}
+
+// streamItem queues a payload for an item of a streamed list.
+func streamItem(eCtx *executionContext, label string, itemType Type, fieldASTs []*ast.Field, info ResolveInfo, path *ResponsePath, item interface{}) {
+	eCtx.Incremental.add(eCtx, &incrementalRecord{
+		label: label,
+		path:  path,
+		execute: func(eCtx *executionContext) (result *IncrementalResult) {
+			record := eCtx.IncrementalRecord
+			result = &IncrementalResult{
+				Path:  path.AsArray(),
+				Label: record.label,
+			}
+			defer func() {
+				if r := recover(); r != nil {
+					result.Items = nil
+					result.Errors = append(eCtx.Errors, formatRecovered(r))
+				}
+			}()
+
+			items := []interface{}{
+				completeValueCatchingError(eCtx, itemType, fieldASTs, info, path, item),
+			}
+			dethunkListWithBreadthFirstTraversal(eCtx, items)
+
+			record.data = items[0]
+			result.Items = items
+			result.Errors = eCtx.Errors
+			return result
+		},
+	})
+}
+
+// formatRecovered formats a value recovered from a panic during the execution,
+// which is either the error of a non-null field propagated to the top or the
+// value of an unexpected panic.
+func formatRecovered(r interface{}) gqlerrors.FormattedError {
+	switch err := r.(type) {
+	case *gqlerrors.Error:
+		return internalError(err)
+	case gqlerrors.FormattedError:
+		return err
+	}
+	return gqlerrors.FormatError(newPanicError(r))
+}
+
+func dethunkListWithBreadthFirstTraversal(eCtx *executionContext, list []interface{}) {
+	dethunkQueue := &dethunkQueue{DethunkFuncs: []func(){}}
+	dethunkQueue.push(func() { dethunkListBreadthFirst(list, dethunkQueue) })
+	dethunkQueue.run(eCtx)
+}
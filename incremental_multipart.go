@@ -0,0 +1,74 @@
+package graphql
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// MultipartMixedContentType is the content type of the responses written by a
+// MultipartMixedEncoder.
+const MultipartMixedContentType = `multipart/mixed; boundary="-"`
+
+const (
+	multipartMixedDelimiter      = "\r\n---"
+	multipartMixedCloseDelimiter = "\r\n-----\r\n"
+	multipartMixedPartHeader     = "\r\nContent-Type: application/json; charset=utf-8\r\n\r\n"
+)
+
+// MultipartMixedEncoder writes the payloads of an incremental response as the
+// JSON parts of a multipart/mixed body, so they can be streamed over HTTP.
+type MultipartMixedEncoder struct {
+	w io.Writer
+}
+
+// NewMultipartMixedEncoder returns an encoder writing to w. If w has a Flush
+// method, such as an http.ResponseWriter implementing http.Flusher, it is
+// called after each part.
+func NewMultipartMixedEncoder(w io.Writer) *MultipartMixedEncoder {
+	return &MultipartMixedEncoder{w: w}
+}
+
+// Encode writes the given payload as a part of the body.
+func (e *MultipartMixedEncoder) Encode(result *IncrementalResult) error {
+	b, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(e.w, multipartMixedDelimiter+multipartMixedPartHeader); err != nil {
+		return err
+	}
+	if _, err := e.w.Write(b); err != nil {
+		return err
+	}
+	e.flush()
+	return nil
+}
+
+// Close writes the end of the body. It does not close the underlying writer.
+func (e *MultipartMixedEncoder) Close() error {
+	if _, err := io.WriteString(e.w, multipartMixedCloseDelimiter); err != nil {
+		return err
+	}
+	e.flush()
+	return nil
+}
+
+func (e *MultipartMixedEncoder) flush() {
+	if flusher, ok := e.w.(interface{ Flush() }); ok {
+		flusher.Flush()
+	}
+}
+
+// WriteMultipartMixed writes every payload received from results as a part
+// of a multipart/mixed body, then ends the body once results is closed. On a
+// write error it returns immediately, so the context of the execution should
+// then be canceled to release the goroutine sending the payloads.
+func WriteMultipartMixed(w io.Writer, results <-chan *IncrementalResult) error {
+	encoder := NewMultipartMixedEncoder(w)
+	for result := range results {
+		if err := encoder.Encode(result); err != nil {
+			return err
+		}
+	}
+	return encoder.Close()
+}
@@ -0,0 +1,376 @@
+package graphql_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/location"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/testutil"
+)
+
+var incrementalPersonType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "Person",
+	Fields: graphql.Fields{
+		"name": &graphql.Field{
+			Type: graphql.String,
+		},
+		"bio": &graphql.Field{
+			Type: graphql.String,
+		},
+		"failing": &graphql.Field{
+			Type: graphql.String,
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return nil, errors.New("failing field")
+			},
+		},
+		"nonNullFailing": &graphql.Field{
+			Type: graphql.NewNonNull(graphql.String),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return nil, errors.New("non-null failing field")
+			},
+		},
+	},
+})
+
+var incrementalSchema, _ = graphql.NewSchema(graphql.SchemaConfig{
+	Query: graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"hello": &graphql.Field{
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return "world", nil
+				},
+			},
+			"person": &graphql.Field{
+				Type: incrementalPersonType,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return map[string]interface{}{"name": "Luke", "bio": "Jedi"}, nil
+				},
+			},
+			"people": &graphql.Field{
+				Type: graphql.NewList(incrementalPersonType),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return []interface{}{
+						map[string]interface{}{"name": "Luke"},
+						map[string]interface{}{"name": "Leia"},
+						map[string]interface{}{"name": "Han"},
+					}, nil
+				},
+			},
+		},
+	}),
+	Directives: append(graphql.SpecifiedDirectives, graphql.DeferDirective, graphql.StreamDirective),
+})
+
+func executeIncremental(t *testing.T, query string) []*graphql.IncrementalResult {
+	astDoc, err := parser.Parse(parser.ParseParams{Source: query})
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if validationResult := graphql.ValidateDocument(&incrementalSchema, astDoc, nil); !validationResult.IsValid {
+		t.Fatalf("Unexpected errors: %v", validationResult.Errors)
+	}
+	results := []*graphql.IncrementalResult{}
+	for result := range graphql.ExecuteIncremental(graphql.ExecuteParams{
+		Schema: incrementalSchema,
+		AST:    astDoc,
+	}) {
+		results = append(results, result)
+	}
+	return results
+}
+
+func expectIncrementalResults(t *testing.T, expected, results []*graphql.IncrementalResult) {
+	if len(expected) != len(results) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, results))
+	}
+	for i := range expected {
+		if !testutil.EqualFormattedErrors(expected[i].Errors, results[i].Errors) {
+			t.Fatalf("Unexpected errors, Diff: %v", testutil.Diff(expected[i].Errors, results[i].Errors))
+		}
+		exp, res := *expected[i], *results[i]
+		exp.Errors, res.Errors = nil, nil
+		if !reflect.DeepEqual(exp, res) {
+			t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(exp, res))
+		}
+	}
+}
+
+func TestExecuteIncremental_DefersInlineFragments(t *testing.T) {
+	results := executeIncremental(t, `{
+		hello
+		... @defer(label: "person") {
+			person { name }
+		}
+	}`)
+	expected := []*graphql.IncrementalResult{
+		{
+			Data:    map[string]interface{}{"hello": "world"},
+			HasNext: true,
+		},
+		{
+			Data: map[string]interface{}{
+				"person": map[string]interface{}{"name": "Luke"},
+			},
+			Path:    []interface{}{},
+			Label:   "person",
+			HasNext: false,
+		},
+	}
+	expectIncrementalResults(t, expected, results)
+
+	b, err := json.Marshal(results[1])
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if expected := `{"data":{"person":{"name":"Luke"}},"path":[],"label":"person","hasNext":false}`; string(b) != expected {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, string(b)))
+	}
+}
+
+func TestExecuteIncremental_FinishesExtensionsWithTheLastPayload(t *testing.T) {
+	var calls []string
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query:      incrementalSchema.QueryType(),
+		Directives: incrementalSchema.Directives(),
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	schema.AddExtensions(newRecordingExt("ext", &calls))
+	astDoc, err := parser.Parse(parser.ParseParams{Source: `{
+		hello
+		... @defer(label: "person") {
+			person { name }
+		}
+	}`})
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	results := []*graphql.IncrementalResult{}
+	for result := range graphql.ExecuteIncremental(graphql.ExecuteParams{
+		Schema: schema,
+		AST:    astDoc,
+	}) {
+		results = append(results, result)
+	}
+
+	expected := []*graphql.IncrementalResult{
+		{
+			Data:    map[string]interface{}{"hello": "world"},
+			HasNext: true,
+		},
+		{
+			Data: map[string]interface{}{
+				"person": map[string]interface{}{"name": "Luke"},
+			},
+			Path:       []interface{}{},
+			Label:      "person",
+			Extensions: map[string]interface{}{"ext": "ext"},
+			HasNext:    false,
+		},
+	}
+	expectIncrementalResults(t, expected, results)
+	expectedCalls := []string{
+		"ext.ExecutionDidStart",
+		"ext.ResolveFieldDidStart", "ext.ResolveFieldFinishFunc",
+		"ext.ResolveFieldDidStart", "ext.ResolveFieldFinishFunc",
+		"ext.ResolveFieldDidStart", "ext.ResolveFieldFinishFunc",
+		"ext.ExecutionFinishFunc",
+		"ext.GetResult",
+	}
+	if !reflect.DeepEqual(expectedCalls, calls) {
+		t.Fatalf("Unexpected calls, Diff: %v", testutil.Diff(expectedCalls, calls))
+	}
+}
+
+func TestExecuteIncremental_DefersNestedFragmentSpreads(t *testing.T) {
+	results := executeIncremental(t, `{
+		person {
+			name
+			...Bio @defer(label: "bio")
+		}
+	}
+	fragment Bio on Person {
+		bio
+	}`)
+	expected := []*graphql.IncrementalResult{
+		{
+			Data: map[string]interface{}{
+				"person": map[string]interface{}{"name": "Luke"},
+			},
+			HasNext: true,
+		},
+		{
+			Data:    map[string]interface{}{"bio": "Jedi"},
+			Path:    []interface{}{"person"},
+			Label:   "bio",
+			HasNext: false,
+		},
+	}
+	expectIncrementalResults(t, expected, results)
+}
+
+func TestExecuteIncremental_DoesNotDeferWhenIfIsFalse(t *testing.T) {
+	results := executeIncremental(t, `{
+		person {
+			... @defer(if: false) { name }
+		}
+	}`)
+	expected := []*graphql.IncrementalResult{
+		{
+			Data: map[string]interface{}{
+				"person": map[string]interface{}{"name": "Luke"},
+			},
+			HasNext: false,
+		},
+	}
+	expectIncrementalResults(t, expected, results)
+}
+
+func TestExecuteIncremental_StreamsListItems(t *testing.T) {
+	results := executeIncremental(t, `{
+		people @stream(initialCount: 1, label: "people") { name }
+	}`)
+	expected := []*graphql.IncrementalResult{
+		{
+			Data: map[string]interface{}{
+				"people": []interface{}{
+					map[string]interface{}{"name": "Luke"},
+				},
+			},
+			HasNext: true,
+		},
+		{
+			Items: []interface{}{
+				map[string]interface{}{"name": "Leia"},
+			},
+			Path:    []interface{}{"people", 1},
+			Label:   "people",
+			HasNext: true,
+		},
+		{
+			Items: []interface{}{
+				map[string]interface{}{"name": "Han"},
+			},
+			Path:    []interface{}{"people", 2},
+			Label:   "people",
+			HasNext: false,
+		},
+	}
+	expectIncrementalResults(t, expected, results)
+}
+
+func TestExecuteIncremental_ReportsErrorsInDeferredPayloads(t *testing.T) {
+	results := executeIncremental(t, `{
+		person {
+			name
+			... @defer { failing }
+		}
+	}`)
+	expected := []*graphql.IncrementalResult{
+		{
+			Data: map[string]interface{}{
+				"person": map[string]interface{}{"name": "Luke"},
+			},
+			HasNext: true,
+		},
+		{
+			Data: map[string]interface{}{"failing": nil},
+			Path: []interface{}{"person"},
+			Errors: []gqlerrors.FormattedError{
+				{
+					Message:    "failing field",
+					Locations:  []location.SourceLocation{{Line: 4, Column: 17}},
+					Path:       []interface{}{"person", "failing"},
+					Extensions: map[string]interface{}{"code": gqlerrors.CodeInternalServerError},
+				},
+			},
+			HasNext: false,
+		},
+	}
+	expectIncrementalResults(t, expected, results)
+}
+
+func TestExecuteIncremental_DropsPayloadsOfNulledObjects(t *testing.T) {
+	results := executeIncremental(t, `{
+		person {
+			nonNullFailing
+			... @defer { name }
+		}
+	}`)
+	expected := []*graphql.IncrementalResult{{
+		Data: map[string]interface{}{
+			"person": nil,
+		},
+		Errors: []gqlerrors.FormattedError{
+			{
+				Message:    "non-null failing field",
+				Locations:  []location.SourceLocation{{Line: 3, Column: 4}},
+				Path:       []interface{}{"person", "nonNullFailing"},
+				Extensions: map[string]interface{}{"code": gqlerrors.CodeInternalServerError},
+			},
+		},
+		HasNext: false,
+	}}
+	expectIncrementalResults(t, expected, results)
+}
+
+func TestExecute_InlinesDeferredFragmentsAndStreamedLists(t *testing.T) {
+	result := graphql.Do(graphql.Params{
+		Schema: incrementalSchema,
+		RequestString: `{
+			hello
+			... @defer { person { name } }
+			people @stream { name }
+		}`,
+	})
+	expected := &graphql.Result{
+		Data: map[string]interface{}{
+			"hello":  "world",
+			"person": map[string]interface{}{"name": "Luke"},
+			"people": []interface{}{
+				map[string]interface{}{"name": "Luke"},
+				map[string]interface{}{"name": "Leia"},
+				map[string]interface{}{"name": "Han"},
+			},
+		},
+	}
+	if !reflect.DeepEqual(expected, result) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
+	}
+}
+
+func TestWriteMultipartMixed(t *testing.T) {
+	results := make(chan *graphql.IncrementalResult, 2)
+	results <- &graphql.IncrementalResult{
+		Data:    map[string]interface{}{"hello": "world"},
+		HasNext: true,
+	}
+	results <- &graphql.IncrementalResult{
+		Data:    map[string]interface{}{"bio": "Jedi"},
+		Path:    []interface{}{"person"},
+		HasNext: false,
+	}
+	close(results)
+
+	var b bytes.Buffer
+	if err := graphql.WriteMultipartMixed(&b, results); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := "\r\n---\r\nContent-Type: application/json; charset=utf-8\r\n\r\n" +
+		`{"data":{"hello":"world"},"hasNext":true}` +
+		"\r\n---\r\nContent-Type: application/json; charset=utf-8\r\n\r\n" +
+		`{"data":{"bio":"Jedi"},"path":["person"],"hasNext":false}` +
+		"\r\n-----\r\n"
+	if b.String() != expected {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, b.String()))
+	}
+}
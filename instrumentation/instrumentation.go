@@ -0,0 +1,264 @@
+// Package instrumentation provides a graphql.Extension creating the spans
+// of the distributed traces of requests: a span for each operation, with
+// child spans for its parsing, its validation and its resolvers.
+//
+// The spans are created by a Tracer, a small interface which can be
+// implemented on top of OpenTelemetry, or any other tracing library, without
+// this package depending on it:
+//
+//	schema.AddExtensions(instrumentation.New(instrumentation.Config{
+//		Tracer:               tracer, // e.g. an adapter of an OpenTelemetry trace.Tracer
+//		SkipDefaultResolvers: true,
+//	}))
+//
+// The Recorder is a Tracer keeping the spans in memory, e.g. for tests.
+package instrumentation
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// The names of the spans.
+const (
+	SpanOperation = "graphql.operation"
+	SpanParse     = "graphql.parse"
+	SpanValidate  = "graphql.validate"
+	SpanResolve   = "graphql.resolve"
+)
+
+// The keys of the attributes of the spans.
+const (
+	AttributeOperationName = "graphql.operation.name"
+	AttributeOperationType = "graphql.operation.type"
+	AttributeDocumentHash  = "graphql.document.hash"
+	AttributeFieldName     = "graphql.field.name"
+	AttributeFieldPath     = "graphql.field.path"
+	AttributeParentType    = "graphql.field.parentType"
+	AttributeReturnType    = "graphql.field.type"
+)
+
+// EventError is the name of the events recording errors, with the
+// AttributeErrorMessage and, for the errors of fields, the
+// AttributeErrorPath.
+const EventError = "exception"
+
+// The keys of the attributes of the error events.
+const (
+	AttributeErrorMessage = "exception.message"
+	AttributeErrorPath    = "graphql.error.path"
+)
+
+// Attribute is a key-value pair describing a span or an event.
+type Attribute struct {
+	Key   string
+	Value interface{}
+}
+
+// Tracer starts spans. The span started by Start is the child of the span
+// of ctx, if any, and is the span of the returned context.
+type Tracer interface {
+	Start(ctx context.Context, name string, attributes ...Attribute) (context.Context, Span)
+}
+
+// Span is a span started by a Tracer. Its methods may be called
+// concurrently, as the resolvers of sibling fields may run in parallel.
+type Span interface {
+	// SetAttributes adds attributes to the span.
+	SetAttributes(attributes ...Attribute)
+
+	// AddEvent records an event in the span.
+	AddEvent(name string, attributes ...Attribute)
+
+	// End ends the span.
+	End()
+}
+
+// Config is the configuration of an Extension.
+type Config struct {
+	// Tracer starts the spans of the requests.
+	Tracer Tracer
+
+	// SkipDefaultResolvers skips the spans of the fields resolved with
+	// graphql.DefaultResolveFn, which merely read a property of their
+	// source, so that only the spans of the fields with a resolver are
+	// recorded.
+	SkipDefaultResolvers bool
+}
+
+// Extension creates the spans of the requests of a schema.
+type Extension struct {
+	tracer               Tracer
+	skipDefaultResolvers bool
+}
+
+var _ graphql.Extension = (*Extension)(nil)
+
+// New returns an Extension creating the spans of the requests with the
+// Tracer of config.
+func New(config Config) *Extension {
+	return &Extension{
+		tracer:               config.Tracer,
+		skipDefaultResolvers: config.SkipDefaultResolvers,
+	}
+}
+
+type operationKey struct{}
+
+// operation is the span of an operation, shared by the hooks of its request.
+type operation struct {
+	span Span
+	// typeOnce sets the name and the type of the operation, known once the
+	// document is parsed, with the first resolved field.
+	typeOnce sync.Once
+	endOnce  sync.Once
+}
+
+// end records the errors of the operation and ends its span, once.
+func (o *operation) end(errs []gqlerrors.FormattedError) {
+	o.endOnce.Do(func() {
+		recordErrors(o.span, errs)
+		o.span.End()
+	})
+}
+
+func operationFrom(ctx context.Context) *operation {
+	if ctx == nil {
+		return nil
+	}
+	o, _ := ctx.Value(operationKey{}).(*operation)
+	return o
+}
+
+// recordErrors records errs as events of span.
+func recordErrors(span Span, errs []gqlerrors.FormattedError) {
+	for _, err := range errs {
+		attributes := []Attribute{{Key: AttributeErrorMessage, Value: err.Message}}
+		if len(err.Path) != 0 {
+			attributes = append(attributes, Attribute{Key: AttributeErrorPath, Value: err.Path})
+		}
+		span.AddEvent(EventError, attributes...)
+	}
+}
+
+// Init starts the span of the operation.
+func (e *Extension) Init(ctx context.Context, p *graphql.Params) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	sum := sha256.Sum256([]byte(p.RequestString))
+	attributes := []Attribute{{Key: AttributeDocumentHash, Value: hex.EncodeToString(sum[:])}}
+	if p.OperationName != "" {
+		attributes = append(attributes, Attribute{Key: AttributeOperationName, Value: p.OperationName})
+	}
+	ctx, span := e.tracer.Start(ctx, SpanOperation, attributes...)
+	return context.WithValue(ctx, operationKey{}, &operation{span: span})
+}
+
+// Name returns "instrumentation".
+func (e *Extension) Name() string {
+	return "instrumentation"
+}
+
+// ParseDidStart starts the span of the parsing. The span of the operation
+// ends with it if the document is invalid.
+func (e *Extension) ParseDidStart(ctx context.Context) (context.Context, graphql.ParseFinishFunc) {
+	_, span := e.tracer.Start(ctx, SpanParse)
+	return ctx, func(err error) {
+		if err != nil {
+			errs := gqlerrors.FormatErrors(err)
+			recordErrors(span, errs)
+			if o := operationFrom(ctx); o != nil {
+				o.end(errs)
+			}
+		}
+		span.End()
+	}
+}
+
+// ValidationDidStart starts the span of the validation. The span of the
+// operation ends with it if the document is invalid.
+func (e *Extension) ValidationDidStart(ctx context.Context) (context.Context, graphql.ValidationFinishFunc) {
+	_, span := e.tracer.Start(ctx, SpanValidate)
+	return ctx, func(errs []gqlerrors.FormattedError) {
+		recordErrors(span, errs)
+		if o := operationFrom(ctx); o != nil && len(errs) != 0 {
+			o.end(errs)
+		}
+		span.End()
+	}
+}
+
+// ExecutionDidStart ends the span of the operation with the execution,
+// recording the errors of its result.
+func (e *Extension) ExecutionDidStart(ctx context.Context) (context.Context, graphql.ExecutionFinishFunc) {
+	return ctx, func(result *graphql.Result) {
+		o := operationFrom(ctx)
+		if o == nil {
+			return
+		}
+		var errs []gqlerrors.FormattedError
+		if result != nil {
+			errs = result.Errors
+		}
+		o.end(errs)
+	}
+}
+
+// ResolveFieldDidStart starts the span of the resolver of a field, unless
+// it is a default resolver skipped by the Config.
+func (e *Extension) ResolveFieldDidStart(ctx context.Context, i *graphql.ResolveInfo) (context.Context, graphql.ResolveFieldFinishFunc) {
+	if o := operationFrom(ctx); o != nil {
+		o.typeOnce.Do(func() {
+			if op, ok := i.Operation.(*ast.OperationDefinition); ok {
+				o.span.SetAttributes(Attribute{Key: AttributeOperationType, Value: op.Operation})
+				if op.Name != nil {
+					o.span.SetAttributes(Attribute{Key: AttributeOperationName, Value: op.Name.Value})
+				}
+			}
+		})
+	}
+	if e.skipDefaultResolvers && hasDefaultResolver(i) {
+		return ctx, func(interface{}, error) {}
+	}
+
+	ctx, span := e.tracer.Start(ctx, SpanResolve,
+		Attribute{Key: AttributeFieldName, Value: i.FieldName},
+		Attribute{Key: AttributeFieldPath, Value: i.Path.AsArray()},
+		Attribute{Key: AttributeParentType, Value: i.ParentType.Name()},
+		Attribute{Key: AttributeReturnType, Value: i.ReturnType.String()},
+	)
+	return ctx, func(_ interface{}, err error) {
+		if err != nil {
+			span.AddEvent(EventError, Attribute{Key: AttributeErrorMessage, Value: err.Error()})
+		}
+		span.End()
+	}
+}
+
+// hasDefaultResolver reports whether the field is resolved with
+// graphql.DefaultResolveFn.
+func hasDefaultResolver(i *graphql.ResolveInfo) bool {
+	parentType, ok := i.ParentType.(*graphql.Object)
+	if !ok {
+		return false
+	}
+	field, ok := parentType.Fields()[i.FieldName]
+	return ok && field.Resolve == nil
+}
+
+// HasResult returns false, as the spans are reported by the Tracer.
+func (e *Extension) HasResult() bool {
+	return false
+}
+
+// GetResult returns nil.
+func (e *Extension) GetResult(context.Context) interface{} {
+	return nil
+}
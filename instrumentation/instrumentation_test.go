@@ -0,0 +1,224 @@
+package instrumentation_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/instrumentation"
+	"github.com/graphql-go/graphql/testutil"
+)
+
+type user struct {
+	Name string `json:"name"`
+}
+
+func newSchema(t *testing.T, config instrumentation.Config) graphql.Schema {
+	userType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "User",
+		Fields: graphql.Fields{
+			"name": &graphql.Field{Type: graphql.String},
+		},
+	})
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Query",
+			Fields: graphql.Fields{
+				"me": &graphql.Field{
+					Type: userType,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						// the spans started by resolvers are the children of
+						// the spans of their fields
+						_, span := config.Tracer.Start(p.Context, "db.query")
+						span.End()
+						return &user{Name: "Luke"}, nil
+					},
+				},
+				"failing": &graphql.Field{
+					Type: graphql.String,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return nil, errors.New("failed")
+					},
+				},
+			},
+		}),
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	schema.AddExtensions(instrumentation.New(config))
+	return schema
+}
+
+// span is a RecordedSpan without its timings.
+type span struct {
+	ID         int
+	ParentID   int
+	Parent     string
+	Name       string
+	Attributes map[string]interface{}
+	Events     []map[string]interface{}
+}
+
+func spans(t *testing.T, recorder *instrumentation.Recorder) []span {
+	var spans []span
+	for _, s := range recorder.Spans() {
+		if !s.Ended {
+			t.Fatalf("Expected the span %v to be ended", s.Name)
+		}
+		var events []map[string]interface{}
+		for _, e := range s.Events {
+			if e.Name != instrumentation.EventError {
+				t.Fatalf("Unexpected event %v", e.Name)
+			}
+			events = append(events, e.Attributes)
+		}
+		spans = append(spans, span{ID: s.ID, ParentID: s.ParentID, Name: s.Name, Attributes: s.Attributes, Events: events})
+	}
+	return spans
+}
+
+// spansByKey returns the spans by key, their name followed by their field
+// path if any, with the keys of their parents in place of their IDs, as the
+// sibling fields are resolved in any order.
+func spansByKey(t *testing.T, recorder *instrumentation.Recorder) map[string]span {
+	spans := spans(t, recorder)
+	key := func(s span) string {
+		if path, ok := s.Attributes[instrumentation.AttributeFieldPath]; ok {
+			return fmt.Sprintf("%s %v", s.Name, path)
+		}
+		return s.Name
+	}
+	keyed := map[string]span{}
+	for _, s := range spans {
+		if s.ParentID != 0 {
+			s.Parent = key(spans[s.ParentID-1])
+		}
+		s.ID, s.ParentID = 0, 0
+		keyed[key(s)] = s
+	}
+	return keyed
+}
+
+func hash(query string) string {
+	sum := sha256.Sum256([]byte(query))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestExtension_CreatesTheSpansOfRequests(t *testing.T) {
+	recorder := instrumentation.NewRecorder()
+	query := `query Me { me { name } failing }`
+	graphql.Do(graphql.Params{
+		Schema:        newSchema(t, instrumentation.Config{Tracer: recorder}),
+		RequestString: query,
+	})
+
+	expected := map[string]span{
+		instrumentation.SpanOperation: {
+			Name: instrumentation.SpanOperation,
+			Attributes: map[string]interface{}{
+				instrumentation.AttributeDocumentHash:  hash(query),
+				instrumentation.AttributeOperationName: "Me",
+				instrumentation.AttributeOperationType: "query",
+			},
+			Events: []map[string]interface{}{{
+				instrumentation.AttributeErrorMessage: "failed",
+				instrumentation.AttributeErrorPath:    []interface{}{"failing"},
+			}},
+		},
+		instrumentation.SpanParse:    {Parent: instrumentation.SpanOperation, Name: instrumentation.SpanParse, Attributes: map[string]interface{}{}},
+		instrumentation.SpanValidate: {Parent: instrumentation.SpanOperation, Name: instrumentation.SpanValidate, Attributes: map[string]interface{}{}},
+		instrumentation.SpanResolve + " [me]": {
+			Parent: instrumentation.SpanOperation,
+			Name:   instrumentation.SpanResolve,
+			Attributes: map[string]interface{}{
+				instrumentation.AttributeFieldName:  "me",
+				instrumentation.AttributeFieldPath:  []interface{}{"me"},
+				instrumentation.AttributeParentType: "Query",
+				instrumentation.AttributeReturnType: "User",
+			},
+		},
+		"db.query": {Parent: instrumentation.SpanResolve + " [me]", Name: "db.query", Attributes: map[string]interface{}{}},
+		instrumentation.SpanResolve + " [me name]": {
+			Parent: instrumentation.SpanOperation,
+			Name:   instrumentation.SpanResolve,
+			Attributes: map[string]interface{}{
+				instrumentation.AttributeFieldName:  "name",
+				instrumentation.AttributeFieldPath:  []interface{}{"me", "name"},
+				instrumentation.AttributeParentType: "User",
+				instrumentation.AttributeReturnType: "String",
+			},
+		},
+		instrumentation.SpanResolve + " [failing]": {
+			Parent: instrumentation.SpanOperation,
+			Name:   instrumentation.SpanResolve,
+			Attributes: map[string]interface{}{
+				instrumentation.AttributeFieldName:  "failing",
+				instrumentation.AttributeFieldPath:  []interface{}{"failing"},
+				instrumentation.AttributeParentType: "Query",
+				instrumentation.AttributeReturnType: "String",
+			},
+			Events: []map[string]interface{}{{instrumentation.AttributeErrorMessage: "failed"}},
+		},
+	}
+	if got := spansByKey(t, recorder); !reflect.DeepEqual(expected, got) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, got))
+	}
+}
+
+func TestExtension_SkipsDefaultResolvers(t *testing.T) {
+	recorder := instrumentation.NewRecorder()
+	graphql.Do(graphql.Params{
+		Schema:        newSchema(t, instrumentation.Config{Tracer: recorder, SkipDefaultResolvers: true}),
+		RequestString: `{ me { name } }`,
+	})
+
+	var fields []interface{}
+	for _, s := range spans(t, recorder) {
+		if s.Name == instrumentation.SpanResolve {
+			fields = append(fields, s.Attributes[instrumentation.AttributeFieldName])
+		}
+	}
+	expected := []interface{}{"me"}
+	if !reflect.DeepEqual(expected, fields) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, fields))
+	}
+}
+
+func TestExtension_EndsTheSpansOfInvalidDocuments(t *testing.T) {
+	tests := map[string]struct {
+		query    string
+		lastSpan string
+	}{
+		"syntax error":     {`{ me`, instrumentation.SpanParse},
+		"validation error": {`{ unknown }`, instrumentation.SpanValidate},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			recorder := instrumentation.NewRecorder()
+			result := graphql.Do(graphql.Params{
+				Schema:        newSchema(t, instrumentation.Config{Tracer: recorder}),
+				RequestString: test.query,
+				Context:       context.Background(),
+			})
+
+			got := spans(t, recorder)
+			if len(got) == 0 || got[len(got)-1].Name != test.lastSpan {
+				t.Fatalf("Unexpected spans: %v", got)
+			}
+			expected := []map[string]interface{}{{
+				instrumentation.AttributeErrorMessage: result.Errors[0].Message,
+			}}
+			for _, s := range []span{got[0], got[len(got)-1]} {
+				if !reflect.DeepEqual(expected, s.Events) {
+					t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, s.Events))
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package instrumentation
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Event is an event recorded in a RecordedSpan.
+type Event struct {
+	Name       string
+	Attributes map[string]interface{}
+	Time       time.Time
+}
+
+// RecordedSpan is a span started by a Recorder. The spans are identified by
+// their order of start, from 1, the ParentID of the root spans being 0.
+type RecordedSpan struct {
+	ID         int
+	ParentID   int
+	Name       string
+	Attributes map[string]interface{}
+	Events     []Event
+	StartTime  time.Time
+	EndTime    time.Time
+	Ended      bool
+}
+
+// Recorder is a Tracer keeping the spans it starts in memory.
+type Recorder struct {
+	mu    sync.Mutex
+	spans []*RecordedSpan
+}
+
+var _ Tracer = (*Recorder)(nil)
+
+// NewRecorder returns an empty Recorder.
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+type recordedSpanKey struct{}
+
+// Start starts a RecordedSpan, the child of the RecordedSpan of ctx if any.
+func (r *Recorder) Start(ctx context.Context, name string, attributes ...Attribute) (context.Context, Span) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	s := &RecordedSpan{
+		Name:       name,
+		Attributes: map[string]interface{}{},
+		StartTime:  time.Now(),
+	}
+	if parent, ok := ctx.Value(recordedSpanKey{}).(*RecordedSpan); ok {
+		s.ParentID = parent.ID
+	}
+	for _, a := range attributes {
+		s.Attributes[a.Key] = a.Value
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.spans = append(r.spans, s)
+	s.ID = len(r.spans)
+	return context.WithValue(ctx, recordedSpanKey{}, s), &recorderSpan{recorder: r, span: s}
+}
+
+// Spans returns copies of the spans started so far, in the order they were
+// started.
+func (r *Recorder) Spans() []RecordedSpan {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	spans := make([]RecordedSpan, len(r.spans))
+	for i, s := range r.spans {
+		spans[i] = *s
+		spans[i].Attributes = make(map[string]interface{}, len(s.Attributes))
+		for k, v := range s.Attributes {
+			spans[i].Attributes[k] = v
+		}
+		spans[i].Events = append([]Event(nil), s.Events...)
+	}
+	return spans
+}
+
+// Reset forgets the spans started so far.
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.spans = nil
+}
+
+// recorderSpan is the Span of a RecordedSpan, which is guarded by the mutex
+// of its Recorder.
+type recorderSpan struct {
+	recorder *Recorder
+	span     *RecordedSpan
+}
+
+func (s *recorderSpan) SetAttributes(attributes ...Attribute) {
+	s.recorder.mu.Lock()
+	defer s.recorder.mu.Unlock()
+	for _, a := range attributes {
+		s.span.Attributes[a.Key] = a.Value
+	}
+}
+
+func (s *recorderSpan) AddEvent(name string, attributes ...Attribute) {
+	event := Event{Name: name, Attributes: map[string]interface{}{}, Time: time.Now()}
+	for _, a := range attributes {
+		event.Attributes[a.Key] = a.Value
+	}
+	s.recorder.mu.Lock()
+	defer s.recorder.mu.Unlock()
+	s.span.Events = append(s.span.Events, event)
+}
+
+func (s *recorderSpan) End() {
+	s.recorder.mu.Lock()
+	defer s.recorder.mu.Unlock()
+	if !s.span.Ended {
+		s.span.EndTime = time.Now()
+		s.span.Ended = true
+	}
+}
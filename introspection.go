@@ -226,6 +226,15 @@ func init() {
 			"description": &Field{
 				Type: String,
 			},
+			"specifiedByURL": &Field{
+				Type: String,
+				Resolve: func(p ResolveParams) (interface{}, error) {
+					if scalar, ok := p.Source.(*Scalar); ok && scalar.SpecifiedByURL() != "" {
+						return scalar.SpecifiedByURL(), nil
+					}
+					return nil, nil
+				},
+			},
 			"fields":        &Field{},
 			"interfaces":    &Field{},
 			"possibleTypes": &Field{},
@@ -262,14 +271,14 @@ func init() {
 						if isNullish(inputVal.DefaultValue) {
 							return nil, nil
 						}
-						astVal := astFromValue(inputVal.DefaultValue, inputVal)
+						astVal := astFromValue(inputVal.DefaultValue, inputVal.Type)
 						return printer.Print(astVal), nil
 					}
 					if inputVal, ok := p.Source.(*InputObjectField); ok {
 						if inputVal.DefaultValue == nil {
 							return nil, nil
 						}
-						astVal := astFromValue(inputVal.DefaultValue, inputVal)
+						astVal := astFromValue(inputVal.DefaultValue, inputVal.Type)
 						return printer.Print(astVal), nil
 					}
 					return nil, nil
@@ -566,7 +575,10 @@ func init() {
 	TypeType.AddFieldConfig("interfaces", &Field{
 		Type: NewList(NewNonNull(TypeType)),
 		Resolve: func(p ResolveParams) (interface{}, error) {
-			if ttype, ok := p.Source.(*Object); ok {
+			switch ttype := p.Source.(type) {
+			case *Object:
+				return ttype.Interfaces(), nil
+			case *Interface:
 				return ttype.Interfaces(), nil
 			}
 			return nil, nil
@@ -735,8 +747,43 @@ func astFromValue(value interface{}, ttype Type) ast.Value {
 		return val
 	}
 
+	// Populate the fields of the input object by creating ASTs from each value
+	// in the Golang map according to the fields in the input type.
 	if valueVal.Type().Kind() == reflect.Map {
-		// TODO: implement astFromValue from Map to Value
+		if ttype, ok := ttype.(*InputObject); ok && valueVal.Type().Key().Kind() == reflect.String {
+			fieldNames := []string{}
+			for fieldName := range ttype.Fields() {
+				fieldNames = append(fieldNames, fieldName)
+			}
+			sort.Strings(fieldNames)
+			fields := []*ast.ObjectField{}
+			for _, fieldName := range fieldNames {
+				fieldVal := valueVal.MapIndex(reflect.ValueOf(fieldName).Convert(valueVal.Type().Key()))
+				if !fieldVal.IsValid() {
+					continue
+				}
+				fieldValue := astFromValue(fieldVal.Interface(), ttype.Fields()[fieldName].Type)
+				if fieldValue == nil {
+					continue
+				}
+				fields = append(fields, ast.NewObjectField(&ast.ObjectField{
+					Name:  ast.NewName(&ast.Name{Value: fieldName}),
+					Value: fieldValue,
+				}))
+			}
+			return ast.NewObjectValue(&ast.ObjectValue{
+				Fields: fields,
+			})
+		}
+	}
+
+	// Enum values are represented by their name rather than their internal value.
+	if ttype, ok := ttype.(*Enum); ok {
+		if name, ok := ttype.Serialize(value).(string); ok {
+			return ast.NewEnumValue(&ast.EnumValue{
+				Value: name,
+			})
+		}
 	}
 
 	if value, ok := value.(bool); ok {
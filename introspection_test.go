@@ -1303,6 +1303,7 @@ func TestIntrospection_FailsAsExpectedOnThe__TypeRootFieldWithoutAnArg(t *testin
 			{
 				Message: `Field "__type" argument "name" of type "String!" ` +
 					`is required but not provided.`,
+				Extensions: map[string]interface{}{"code": gqlerrors.CodeValidationFailed, "rule": "ProvidedNonNullArgumentsRule"},
 				Locations: []location.SourceLocation{
 					{Line: 3, Column: 9},
 				},
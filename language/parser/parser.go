@@ -79,8 +79,9 @@ func Parse(p ParseParams) (*ast.Document, error) {
 	return doc, nil
 }
 
-// TODO: test and expose parseValue as a public
-func parseValue(p ParseParams) (ast.Value, error) {
+// ParseValue parses the given source as a single GraphQL value, e.g. the
+// printed default value of an argument as found in an introspection result.
+func ParseValue(p ParseParams) (ast.Value, error) {
 	var value ast.Value
 	var sourceObj *source.Source
 	switch src := p.Source.(type) {
@@ -1148,7 +1149,7 @@ func parseInputValueDef(parser *Parser) (interface{}, error) {
 /**
  * InterfaceTypeDefinition :
  *   Description?
- *   interface Name Directives? { FieldDefinition+ }
+ *   interface Name ImplementsInterfaces? Directives? { FieldDefinition+ }
  */
 func parseInterfaceTypeDefinition(parser *Parser) (ast.Node, error) {
 	start := parser.Token.Start
@@ -1164,6 +1165,10 @@ func parseInterfaceTypeDefinition(parser *Parser) (ast.Node, error) {
 	if err != nil {
 		return nil, err
 	}
+	interfaces, err := parseImplementsInterfaces(parser)
+	if err != nil {
+		return nil, err
+	}
 	directives, err := parseDirectives(parser)
 	if err != nil {
 		return nil, err
@@ -1184,6 +1189,7 @@ func parseInterfaceTypeDefinition(parser *Parser) (ast.Node, error) {
 	return ast.NewInterfaceDefinition(&ast.InterfaceDefinition{
 		Name:        name,
 		Description: description,
+		Interfaces:  interfaces,
 		Directives:  directives,
 		Loc:         loc(parser, start),
 		Fields:      fields,
@@ -1365,7 +1371,12 @@ func parseInputObjectTypeDefinition(parser *Parser) (ast.Node, error) {
 }
 
 /**
- * TypeExtensionDefinition : extend ObjectTypeDefinition
+ * TypeExtensionDefinition :
+ *   - extend ObjectTypeDefinition
+ *   - extend InterfaceTypeDefinition
+ *   - extend UnionTypeDefinition
+ *   - extend EnumTypeDefinition
+ *   - extend InputObjectTypeDefinition
  */
 func parseTypeExtensionDefinition(parser *Parser) (ast.Node, error) {
 	start := parser.Token.Start
@@ -1374,14 +1385,58 @@ func parseTypeExtensionDefinition(parser *Parser) (ast.Node, error) {
 		return nil, err
 	}
 
-	definition, err := parseObjectTypeDefinition(parser)
-	if err != nil {
-		return nil, err
+	keywordToken := parser.Token
+	if keywordToken.Kind != lexer.NAME {
+		return nil, unexpected(parser, keywordToken)
 	}
-	return ast.NewTypeExtensionDefinition(&ast.TypeExtensionDefinition{
-		Loc:        loc(parser, start),
-		Definition: definition.(*ast.ObjectDefinition),
-	}), nil
+	switch keywordToken.Value {
+	case lexer.TYPE:
+		definition, err := parseObjectTypeDefinition(parser)
+		if err != nil {
+			return nil, err
+		}
+		return ast.NewTypeExtensionDefinition(&ast.TypeExtensionDefinition{
+			Loc:        loc(parser, start),
+			Definition: definition.(*ast.ObjectDefinition),
+		}), nil
+	case lexer.INTERFACE:
+		definition, err := parseInterfaceTypeDefinition(parser)
+		if err != nil {
+			return nil, err
+		}
+		return ast.NewInterfaceExtensionDefinition(&ast.InterfaceExtensionDefinition{
+			Loc:        loc(parser, start),
+			Definition: definition.(*ast.InterfaceDefinition),
+		}), nil
+	case lexer.UNION:
+		definition, err := parseUnionTypeDefinition(parser)
+		if err != nil {
+			return nil, err
+		}
+		return ast.NewUnionExtensionDefinition(&ast.UnionExtensionDefinition{
+			Loc:        loc(parser, start),
+			Definition: definition.(*ast.UnionDefinition),
+		}), nil
+	case lexer.ENUM:
+		definition, err := parseEnumTypeDefinition(parser)
+		if err != nil {
+			return nil, err
+		}
+		return ast.NewEnumExtensionDefinition(&ast.EnumExtensionDefinition{
+			Loc:        loc(parser, start),
+			Definition: definition.(*ast.EnumDefinition),
+		}), nil
+	case lexer.INPUT:
+		definition, err := parseInputObjectTypeDefinition(parser)
+		if err != nil {
+			return nil, err
+		}
+		return ast.NewInputObjectExtensionDefinition(&ast.InputObjectExtensionDefinition{
+			Loc:        loc(parser, start),
+			Definition: definition.(*ast.InputObjectDefinition),
+		}), nil
+	}
+	return nil, unexpected(parser, keywordToken)
 }
 
 /**
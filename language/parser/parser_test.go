@@ -156,6 +156,20 @@ func TestParsesVariableInlineValues(t *testing.T) {
 	}
 }
 
+func TestParseValue(t *testing.T) {
+	value, err := ParseValue(ParseParams{Source: `{ a: [1, "two", THREE], b: $var }`})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if printed := printer.Print(value); printed != `{a: [1, "two", THREE], b: $var}` {
+		t.Fatalf("unexpected printed value: %v", printed)
+	}
+
+	if _, err := ParseValue(ParseParams{Source: `{ a: }`}); err == nil {
+		t.Fatalf("expected error for incomplete value")
+	}
+}
+
 func TestParsesConstantDefaultValues(t *testing.T) {
 	test := errorMessageTest{
 		`query Foo($x: Complex = { a: { b: [ $var ] } }) { field }`,
@@ -121,6 +121,46 @@ extend type Hello {
 	}
 }
 
+func TestSchemaParser_SimpleUnionExtension(t *testing.T) {
+
+	body := `
+extend union Hello = World`
+	astDoc := parse(t, body)
+	expected := ast.NewDocument(&ast.Document{
+		Loc: testLoc(1, 27),
+		Definitions: []ast.Node{
+			ast.NewUnionExtensionDefinition(&ast.UnionExtensionDefinition{
+				Loc: testLoc(1, 27),
+				Definition: ast.NewUnionDefinition(&ast.UnionDefinition{
+					Loc: testLoc(8, 27),
+					Name: ast.NewName(&ast.Name{
+						Value: "Hello",
+						Loc:   testLoc(14, 19),
+					}),
+					Directives: []*ast.Directive{},
+					Types: []*ast.Named{
+						ast.NewNamed(&ast.Named{
+							Loc: testLoc(22, 27),
+							Name: ast.NewName(&ast.Name{
+								Value: "World",
+								Loc:   testLoc(22, 27),
+							}),
+						}),
+					},
+				}),
+			}),
+		},
+	})
+	if !reflect.DeepEqual(astDoc, expected) {
+		t.Fatalf("unexpected document, expected: %v, got: %v", expected, astDoc)
+	}
+}
+
+func TestSchemaParser_RejectsExtensionOfUnknownKind(t *testing.T) {
+	_, err := Parse(ParseParams{Source: `extend schema { query: Query }`})
+	checkErrorMessage(t, err, `Syntax Error GraphQL (1:8) Unexpected Name "schema"`)
+}
+
 func TestSchemaParser_SimpleNonNullType(t *testing.T) {
 
 	body := `
@@ -361,6 +401,7 @@ interface Hello {
 					Value: "Hello",
 					Loc:   testLoc(11, 16),
 				}),
+				Interfaces: []*ast.Named{},
 				Directives: []*ast.Directive{},
 				Fields: []*ast.FieldDefinition{
 					ast.NewFieldDefinition(&ast.FieldDefinition{
@@ -388,6 +429,54 @@ interface Hello {
 	}
 }
 
+func TestSchemaParser_SimpleInterfaceInheritingInterface(t *testing.T) {
+	body := `interface Hello implements World { field: String }`
+	astDoc := parse(t, body)
+	expected := ast.NewDocument(&ast.Document{
+		Loc: testLoc(0, 50),
+		Definitions: []ast.Node{
+			ast.NewInterfaceDefinition(&ast.InterfaceDefinition{
+				Loc: testLoc(0, 50),
+				Name: ast.NewName(&ast.Name{
+					Value: "Hello",
+					Loc:   testLoc(10, 15),
+				}),
+				Interfaces: []*ast.Named{
+					ast.NewNamed(&ast.Named{
+						Name: ast.NewName(&ast.Name{
+							Value: "World",
+							Loc:   testLoc(27, 32),
+						}),
+						Loc: testLoc(27, 32),
+					}),
+				},
+				Directives: []*ast.Directive{},
+				Fields: []*ast.FieldDefinition{
+					ast.NewFieldDefinition(&ast.FieldDefinition{
+						Loc: testLoc(35, 48),
+						Name: ast.NewName(&ast.Name{
+							Value: "field",
+							Loc:   testLoc(35, 40),
+						}),
+						Directives: []*ast.Directive{},
+						Arguments:  []*ast.InputValueDefinition{},
+						Type: ast.NewNamed(&ast.Named{
+							Loc: testLoc(42, 48),
+							Name: ast.NewName(&ast.Name{
+								Value: "String",
+								Loc:   testLoc(42, 48),
+							}),
+						}),
+					}),
+				},
+			}),
+		},
+	})
+	if !reflect.DeepEqual(astDoc, expected) {
+		t.Fatalf("unexpected document, expected: %v, got: %v", expected, astDoc)
+	}
+}
+
 func TestSchemaParser_SimpleFieldWithArg(t *testing.T) {
 	body := `
 type Hello {
@@ -821,6 +910,7 @@ input Hello {
 		Locations: []location.SourceLocation{
 			{Line: 3, Column: 8},
 		},
+		Extensions: map[string]interface{}{"code": gqlerrors.CodeParseFailed},
 	}
 	if err == nil {
 		t.Fatalf("expected error, expected: %v, got: %v", expectedError, nil)
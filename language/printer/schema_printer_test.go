@@ -80,6 +80,11 @@ interface AnnotatedInterface @onInterface {
   annotatedField(arg: Type @onArg): Type @onField
 }
 
+interface Baz implements Bar {
+  one: Type
+  four(argument: String = "string"): String
+}
+
 union Feed = Story | Article | Advert
 
 union AnnotatedUnion @onUnion = A | B
@@ -113,6 +118,20 @@ extend type Foo {
 
 extend type Foo @onType {}
 
+extend interface Bar {
+  five: Int
+}
+
+extend union Feed = Video
+
+extend enum Site {
+  WEB
+}
+
+extend input InputType {
+  other: Int
+}
+
 type NoFields {}
 
 directive @skip(if: Boolean!) on FIELD | FRAGMENT_SPREAD | INLINE_FRAGMENT
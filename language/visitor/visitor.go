@@ -114,6 +114,7 @@ var QueryDocumentKeys = KeyMap{
 	},
 	"InterfaceDefinition": []string{
 		"Name",
+		"Interfaces",
 		"Directives",
 		"Fields",
 	},
@@ -137,7 +138,11 @@ var QueryDocumentKeys = KeyMap{
 		"Fields",
 	},
 
-	"TypeExtensionDefinition": []string{"Definition"},
+	"TypeExtensionDefinition":        []string{"Definition"},
+	"InterfaceExtensionDefinition":   []string{"Definition"},
+	"UnionExtensionDefinition":       []string{"Definition"},
+	"EnumExtensionDefinition":        []string{"Definition"},
+	"InputObjectExtensionDefinition": []string{"Definition"},
 
 	"DirectiveDefinition": []string{"Name", "Arguments", "Locations"},
 }
@@ -255,7 +255,8 @@ func TestLists_NonNullListOfNullableObjectsReturnsNull(t *testing.T) {
 		},
 		Errors: []gqlerrors.FormattedError{
 			{
-				Message: "Cannot return null for non-nullable field DataType.test.",
+				Message:    "Cannot return null for non-nullable field DataType.test.",
+				Extensions: map[string]interface{}{"code": gqlerrors.CodeInternalServerError},
 				Locations: []location.SourceLocation{
 					{
 						Line:   1,
@@ -329,7 +330,8 @@ func TestLists_NonNullListOfNullableFunc_ReturnsNull(t *testing.T) {
 		},
 		Errors: []gqlerrors.FormattedError{
 			{
-				Message: "Cannot return null for non-nullable field DataType.test.",
+				Message:    "Cannot return null for non-nullable field DataType.test.",
+				Extensions: map[string]interface{}{"code": gqlerrors.CodeInternalServerError},
 				Locations: []location.SourceLocation{
 					{
 						Line:   1,
@@ -429,7 +431,8 @@ func TestLists_NullableListOfNonNullObjects_ContainsNull(t *testing.T) {
 		},
 		Errors: []gqlerrors.FormattedError{
 			{
-				Message: "Cannot return null for non-nullable field DataType.test.",
+				Message:    "Cannot return null for non-nullable field DataType.test.",
+				Extensions: map[string]interface{}{"code": gqlerrors.CodeInternalServerError},
 				Locations: []location.SourceLocation{
 					{
 						Line:   1,
@@ -499,7 +502,8 @@ func TestLists_NullableListOfNonNullFunc_ContainsNull(t *testing.T) {
 		},
 		Errors: []gqlerrors.FormattedError{
 			{
-				Message: "Cannot return null for non-nullable field DataType.test.",
+				Message:    "Cannot return null for non-nullable field DataType.test.",
+				Extensions: map[string]interface{}{"code": gqlerrors.CodeInternalServerError},
 				Locations: []location.SourceLocation{
 					{
 						Line:   1,
@@ -590,7 +594,8 @@ func TestLists_NullableListOfNonNullArrayOfFunc_ContainsNulls(t *testing.T) {
 		Data: nil,
 		Errors: []gqlerrors.FormattedError{
 			{
-				Message: "Cannot return null for non-nullable field DataType.test.",
+				Message:    "Cannot return null for non-nullable field DataType.test.",
+				Extensions: map[string]interface{}{"code": gqlerrors.CodeInternalServerError},
 				Locations: []location.SourceLocation{
 					{
 						Line:   1,
@@ -636,7 +641,8 @@ func TestLists_NonNullListOfNonNullObjects_ContainsNull(t *testing.T) {
 		},
 		Errors: []gqlerrors.FormattedError{
 			{
-				Message: "Cannot return null for non-nullable field DataType.test.",
+				Message:    "Cannot return null for non-nullable field DataType.test.",
+				Extensions: map[string]interface{}{"code": gqlerrors.CodeInternalServerError},
 				Locations: []location.SourceLocation{
 					{
 						Line:   1,
@@ -661,7 +667,8 @@ func TestLists_NonNullListOfNonNullObjects_ReturnsNull(t *testing.T) {
 		},
 		Errors: []gqlerrors.FormattedError{
 			{
-				Message: "Cannot return null for non-nullable field DataType.test.",
+				Message:    "Cannot return null for non-nullable field DataType.test.",
+				Extensions: map[string]interface{}{"code": gqlerrors.CodeInternalServerError},
 				Locations: []location.SourceLocation{
 					{
 						Line:   1,
@@ -716,7 +723,8 @@ func TestLists_NonNullListOfNonNullFunc_ContainsNull(t *testing.T) {
 		},
 		Errors: []gqlerrors.FormattedError{
 			{
-				Message: "Cannot return null for non-nullable field DataType.test.",
+				Message:    "Cannot return null for non-nullable field DataType.test.",
+				Extensions: map[string]interface{}{"code": gqlerrors.CodeInternalServerError},
 				Locations: []location.SourceLocation{
 					{
 						Line:   1,
@@ -747,7 +755,8 @@ func TestLists_NonNullListOfNonNullFunc_ReturnsNull(t *testing.T) {
 		},
 		Errors: []gqlerrors.FormattedError{
 			{
-				Message: "Cannot return null for non-nullable field DataType.test.",
+				Message:    "Cannot return null for non-nullable field DataType.test.",
+				Extensions: map[string]interface{}{"code": gqlerrors.CodeInternalServerError},
 				Locations: []location.SourceLocation{
 					{
 						Line:   1,
@@ -818,7 +827,8 @@ func TestLists_NonNullListOfNonNullArrayOfFunc_ContainsNulls(t *testing.T) {
 		Data: nil,
 		Errors: []gqlerrors.FormattedError{
 			{
-				Message: "Cannot return null for non-nullable field DataType.test.",
+				Message:    "Cannot return null for non-nullable field DataType.test.",
+				Extensions: map[string]interface{}{"code": gqlerrors.CodeInternalServerError},
 				Locations: []location.SourceLocation{
 					{
 						Line:   1,
@@ -847,7 +857,8 @@ func TestLists_UserErrorExpectIterableButDidNotGetOne(t *testing.T) {
 		},
 		Errors: []gqlerrors.FormattedError{
 			{
-				Message: "User Error: expected iterable, but did not find one for field DataType.test.",
+				Message:    "User Error: expected iterable, but did not find one for field DataType.test.",
+				Extensions: map[string]interface{}{"code": gqlerrors.CodeInternalServerError},
 				Locations: []location.SourceLocation{
 					{
 						Line:   1,
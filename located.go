@@ -2,6 +2,7 @@ package graphql
 
 import (
 	"errors"
+	"runtime/debug"
 
 	"github.com/graphql-go/graphql/gqlerrors"
 	"github.com/graphql-go/graphql/language/ast"
@@ -49,3 +50,55 @@ func FieldASTsToNodeASTs(fieldASTs []*ast.Field) []ast.Node {
 	}
 	return nodes
 }
+
+// PanicError is the error of a resolver which panicked, holding the value it
+// panicked with and the stack of its goroutine. It is reported as an
+// INTERNAL_SERVER_ERROR, unless the value is an error with a code.
+type PanicError struct {
+	Value interface{}
+	Stack []byte
+}
+
+// newPanicError returns the error of a panic with the value r, to be called
+// by the deferred function recovering it so that the stack is the stack of
+// the panic.
+func newPanicError(r interface{}) *PanicError {
+	if err, ok := r.(*PanicError); ok {
+		return err
+	}
+	return &PanicError{Value: r, Stack: debug.Stack()}
+}
+
+func (e *PanicError) Error() string {
+	switch value := e.Value.(type) {
+	case error:
+		return value.Error()
+	case string:
+		return value
+	}
+	return "An unknown error occurred."
+}
+
+// Unwrap returns the value of the panic if it is an error.
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// Extensions implements gqlerrors.ExtendedError.
+func (e *PanicError) Extensions() map[string]interface{} {
+	if err, ok := e.Value.(gqlerrors.ExtendedError); ok {
+		return err.Extensions()
+	}
+	return map[string]interface{}{"code": gqlerrors.CodeInternalServerError}
+}
+
+// recoverPanic calls f, returning the error of its panic if it panics.
+func recoverPanic(f func() (interface{}, error)) (result interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			result, err = nil, newPanicError(r)
+		}
+	}()
+	return f()
+}
@@ -0,0 +1,88 @@
+package graphql
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"log"
+
+	"github.com/graphql-go/graphql/gqlerrors"
+)
+
+// InternalErrorMessage is the message of the errors masked by MaskErrors.
+const InternalErrorMessage = "Internal server error"
+
+// ErrorLogger logs the errors masked by MaskErrors, with the correlation ID
+// reported to the client in their place and, for the panics of resolvers,
+// the stack of the panic.
+type ErrorLogger interface {
+	LogError(correlationID string, err error, stack []byte)
+}
+
+// ErrorLoggerFunc is a function used as an ErrorLogger.
+type ErrorLoggerFunc func(correlationID string, err error, stack []byte)
+
+// LogError calls f.
+func (f ErrorLoggerFunc) LogError(correlationID string, err error, stack []byte) {
+	f(correlationID, err, stack)
+}
+
+// DefaultErrorLogger logs the masked errors with the standard logger.
+var DefaultErrorLogger ErrorLogger = ErrorLoggerFunc(func(correlationID string, err error, stack []byte) {
+	if len(stack) != 0 {
+		log.Printf("graphql: internal error %s: %v\n%s", correlationID, err, stack)
+		return
+	}
+	log.Printf("graphql: internal error %s: %v", correlationID, err)
+})
+
+// MaskErrors returns an ErrorFormatter masking the unexpected errors, such
+// as the errors of the resolvers and their panics, so that their messages
+// are not leaked to clients. Each masked error is reported with the
+// InternalErrorMessage, the INTERNAL_SERVER_ERROR code and a correlation ID
+// in the "correlationId" of its extensions, under which the original error
+// is logged with logger, or DefaultErrorLogger if logger is nil. The other
+// extensions of the error are kept.
+//
+// The errors of the request, such as syntax or validation errors or the
+// errors of cancelled requests, and the errors marked as user-facing with
+// gqlerrors.UserFacingError are reported as they are.
+func MaskErrors(logger ErrorLogger) ErrorFormatter {
+	if logger == nil {
+		logger = DefaultErrorLogger
+	}
+	return func(err gqlerrors.FormattedError) gqlerrors.FormattedError {
+		if !isInternalError(err) {
+			return err
+		}
+		correlationID := newCorrelationID()
+		var stack []byte
+		var panicErr *PanicError
+		if errors.As(err, &panicErr) {
+			stack = panicErr.Stack
+		}
+		logger.LogError(correlationID, err, stack)
+
+		err.Message = InternalErrorMessage
+		return err.
+			WithExtension("code", gqlerrors.CodeInternalServerError).
+			WithExtension("correlationId", correlationID)
+	}
+}
+
+// isInternalError reports whether err is an unexpected error, to be masked.
+func isInternalError(err gqlerrors.FormattedError) bool {
+	switch err.Code() {
+	case gqlerrors.CodeParseFailed, gqlerrors.CodeValidationFailed, gqlerrors.CodeBadUserInput, gqlerrors.CodeRequestCancelled:
+		return false
+	}
+	return !gqlerrors.IsUserFacing(err)
+}
+
+func newCorrelationID() string {
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(id)
+}
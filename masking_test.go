@@ -0,0 +1,313 @@
+package graphql_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/testutil"
+)
+
+// loggedError is an error logged by an errorRecorder.
+type loggedError struct {
+	correlationID string
+	message       string
+	stack         string
+}
+
+type errorRecorder struct {
+	mu     sync.Mutex
+	logged map[string]loggedError
+}
+
+func (r *errorRecorder) LogError(correlationID string, err error, stack []byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.logged == nil {
+		r.logged = map[string]loggedError{}
+	}
+	r.logged[correlationID] = loggedError{
+		correlationID: correlationID,
+		message:       err.Error(),
+		stack:         string(stack),
+	}
+}
+
+// retryableError is an error whose extensions tell whether it is retryable.
+type retryableError struct{}
+
+func (retryableError) Error() string {
+	return "connection reset"
+}
+
+func (retryableError) Extensions() map[string]interface{} {
+	return map[string]interface{}{"retryable": true}
+}
+
+func newMaskingSchema(t *testing.T) graphql.Schema {
+	field := func(resolve graphql.FieldResolveFn) *graphql.Field {
+		return &graphql.Field{Type: graphql.String, Resolve: resolve}
+	}
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Query",
+			Fields: graphql.Fields{
+				"failing": field(func(p graphql.ResolveParams) (interface{}, error) {
+					return nil, errors.New(`pq: relation "users" does not exist`)
+				}),
+				"panicking": field(func(p graphql.ResolveParams) (interface{}, error) {
+					panic(struct{ reason string }{"unexpected"})
+				}),
+				"userFacing": field(func(p graphql.ResolveParams) (interface{}, error) {
+					return nil, gqlerrors.NewUserFacingError("You cannot see this.")
+				}),
+				"forbidden": field(func(p graphql.ResolveParams) (interface{}, error) {
+					return nil, gqlerrors.UserFacing(forbiddenError{})
+				}),
+				"retryable": field(func(p graphql.ResolveParams) (interface{}, error) {
+					return nil, retryableError{}
+				}),
+			},
+		}),
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return schema
+}
+
+func TestMaskErrors_MasksUnexpectedErrors(t *testing.T) {
+	recorder := &errorRecorder{}
+	result := graphql.Do(graphql.Params{
+		Schema:         newMaskingSchema(t),
+		RequestString:  `{ failing panicking userFacing forbidden }`,
+		ErrorFormatter: graphql.MaskErrors(recorder),
+	})
+	if len(result.Errors) != 4 {
+		t.Fatalf("Unexpected errors: %v", result.Errors)
+	}
+
+	errs := map[string]gqlerrors.FormattedError{}
+	for _, err := range result.Errors {
+		errs[err.Path[0].(string)] = err
+	}
+	for _, name := range []string{"failing", "panicking"} {
+		err := errs[name]
+		correlationID, _ := err.Extensions["correlationId"].(string)
+		if err.Message != graphql.InternalErrorMessage || err.Code() != gqlerrors.CodeInternalServerError || correlationID == "" {
+			t.Fatalf("Expected the error of %v to be masked, got %#v", name, err)
+		}
+		logged, ok := recorder.logged[correlationID]
+		if !ok {
+			t.Fatalf("Expected the error of %v to be logged with its correlation ID", name)
+		}
+		switch name {
+		case "failing":
+			if logged.message != `pq: relation "users" does not exist` || logged.stack != "" {
+				t.Fatalf("Unexpected logged error: %#v", logged)
+			}
+		case "panicking":
+			if logged.message != "An unknown error occurred." || !strings.Contains(logged.stack, "masking_test.go") {
+				t.Fatalf("Expected the stack of the panic to be logged, got %#v", logged)
+			}
+		}
+	}
+	if len(recorder.logged) != 2 {
+		t.Fatalf("Expected 2 logged errors, got %v", len(recorder.logged))
+	}
+
+	expectedExtensions := map[string]interface{}{"code": gqlerrors.CodeInternalServerError}
+	if err := errs["userFacing"]; err.Message != "You cannot see this." || !reflect.DeepEqual(expectedExtensions, err.Extensions) {
+		t.Fatalf("Expected the user-facing error to be kept, got %#v", err)
+	}
+	expectedExtensions = map[string]interface{}{"code": "FORBIDDEN"}
+	if err := errs["forbidden"]; err.Message != "forbidden" || !reflect.DeepEqual(expectedExtensions, err.Extensions) {
+		t.Fatalf("Expected the user-facing error to be kept, got %#v", err)
+	}
+}
+
+func TestMaskErrors_KeepsTheErrorsOfTheRequest(t *testing.T) {
+	recorder := &errorRecorder{}
+	for _, query := range []string{`{ failing`, `{ unknown }`, `query Q { failing } query R { failing }`} {
+		result := graphql.Do(graphql.Params{
+			Schema:         newMaskingSchema(t),
+			RequestString:  query,
+			ErrorFormatter: graphql.MaskErrors(recorder),
+		})
+		if len(result.Errors) != 1 || result.Errors[0].Message == graphql.InternalErrorMessage {
+			t.Fatalf("Unexpected errors for %v: %v", query, result.Errors)
+		}
+	}
+	if len(recorder.logged) != 0 {
+		t.Fatalf("Unexpected logged errors: %v", recorder.logged)
+	}
+}
+
+func TestMaskErrors_KeepsTheErrorsOfCancelledRequests(t *testing.T) {
+	recorder := &errorRecorder{}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	result := graphql.Do(graphql.Params{
+		Schema:         newMaskingSchema(t),
+		RequestString:  `{ failing }`,
+		Context:        ctx,
+		ErrorFormatter: graphql.MaskErrors(recorder),
+	})
+	if len(result.Errors) != 1 || result.Errors[0].Message == graphql.InternalErrorMessage || result.Errors[0].Code() != gqlerrors.CodeRequestCancelled {
+		t.Fatalf("Unexpected errors: %v", result.Errors)
+	}
+	if len(recorder.logged) != 0 {
+		t.Fatalf("Unexpected logged errors: %v", recorder.logged)
+	}
+}
+
+func TestMaskErrors_KeepsTheOtherExtensions(t *testing.T) {
+	result := graphql.Do(graphql.Params{
+		Schema:         newMaskingSchema(t),
+		RequestString:  `{ retryable }`,
+		ErrorFormatter: graphql.MaskErrors(&errorRecorder{}),
+	})
+	if len(result.Errors) != 1 {
+		t.Fatalf("Unexpected errors: %v", result.Errors)
+	}
+	err := result.Errors[0]
+	correlationID, _ := err.Extensions["correlationId"].(string)
+	expectedExtensions := map[string]interface{}{
+		"code":          gqlerrors.CodeInternalServerError,
+		"correlationId": correlationID,
+		"retryable":     true,
+	}
+	if err.Message != graphql.InternalErrorMessage || correlationID == "" || !reflect.DeepEqual(expectedExtensions, err.Extensions) {
+		t.Fatalf("Expected the error to be masked with its extensions, got %#v", err)
+	}
+}
+
+func TestExecuteReportsPanicsWithValuesWhichAreNotErrors(t *testing.T) {
+	ext := newtestExt("testExt")
+	ext.resolveFieldDidStartFn = func(ctx context.Context, i *graphql.ResolveInfo) (context.Context, graphql.ResolveFieldFinishFunc) {
+		panic("not an error")
+	}
+	schema := tinit(t)
+	schema.AddExtensions(ext)
+
+	result := graphql.Do(graphql.Params{
+		Schema:        schema,
+		RequestString: `{ a }`,
+	})
+	expected := &graphql.Result{
+		Data: map[string]interface{}{"a": "foo"},
+		Errors: []gqlerrors.FormattedError{
+			gqlerrors.NewFormattedError("testExt.ResolveFieldDidStart: not an error").
+				WithCode(gqlerrors.CodeInternalServerError),
+		},
+		Extensions: map[string]interface{}{"testExt": ext.GetResult(context.Background())},
+	}
+	if !testutil.EqualResults(expected, result) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
+	}
+}
+
+func TestSubscribeReportsPanicsWithValuesWhichAreNotErrors(t *testing.T) {
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name:   "Query",
+			Fields: graphql.Fields{"a": &graphql.Field{Type: graphql.String}},
+		}),
+		Subscription: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Subscription",
+			Fields: graphql.Fields{
+				"events": &graphql.Field{
+					Type: graphql.String,
+					Subscribe: func(p graphql.ResolveParams) (interface{}, error) {
+						panic(42)
+					},
+				},
+			},
+		}),
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	recorder := &errorRecorder{}
+	var results []*graphql.Result
+	for result := range graphql.Subscribe(graphql.Params{
+		Schema:         schema,
+		RequestString:  `subscription { events }`,
+		ErrorFormatter: graphql.MaskErrors(recorder),
+	}) {
+		results = append(results, result)
+	}
+	if len(results) != 1 || len(results[0].Errors) != 1 || results[0].Errors[0].Message != graphql.InternalErrorMessage {
+		t.Fatalf("Unexpected results: %v", results)
+	}
+	for _, logged := range recorder.logged {
+		if !strings.Contains(logged.stack, "masking_test.go") {
+			t.Fatalf("Expected the stack of the panic to be logged, got %#v", logged)
+		}
+	}
+}
+
+func TestSubscribeWithErrorFormatterStopsOnceCancelled(t *testing.T) {
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name:   "Query",
+			Fields: graphql.Fields{"a": &graphql.Field{Type: graphql.String}},
+		}),
+		Subscription: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Subscription",
+			Fields: graphql.Fields{
+				"events": &graphql.Field{
+					Type: graphql.Int,
+					Subscribe: func(p graphql.ResolveParams) (interface{}, error) {
+						c := make(chan interface{})
+						go func() {
+							defer close(c)
+							for i := 0; ; i++ {
+								select {
+								case c <- i:
+								case <-p.Context.Done():
+									return
+								}
+							}
+						}()
+						return c, nil
+					},
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return p.Source, nil
+					},
+				},
+			},
+		}),
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	goroutines := runtime.NumGoroutine()
+	ctx, cancel := context.WithCancel(context.Background())
+	results := graphql.Subscribe(graphql.Params{
+		Schema:         schema,
+		RequestString:  `subscription { events }`,
+		Context:        ctx,
+		ErrorFormatter: graphql.MaskErrors(&errorRecorder{}),
+	})
+	if result := <-results; result.HasErrors() {
+		t.Fatalf("Unexpected result: %v", result)
+	}
+	// the results are no longer read once the subscription is cancelled
+	cancel()
+	for deadline := time.Now().Add(time.Second); runtime.NumGoroutine() > goroutines; {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected the goroutines of the subscription to stop, %v left", runtime.NumGoroutine()-goroutines)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -0,0 +1,250 @@
+// Package metrics provides a graphql.Extension reporting Prometheus-style
+// metrics of the requests and of their resolvers to a Sink:
+//
+//	sink := metrics.NewMemorySink(nil)
+//	schema.AddExtensions(metrics.New(metrics.Config{
+//		Sink:               sink,
+//		InstrumentedFields: []string{"Query.user"},
+//	}))
+//	http.Handle("/metrics", sink)
+//
+// The metrics are:
+//
+//   - MetricOperations, counting the operations by name and type;
+//   - MetricParseDuration, MetricValidateDuration and MetricExecuteDuration,
+//     the histograms of the latencies of the phases of the requests;
+//   - MetricErrors, counting the errors by the code of their extensions;
+//   - MetricFieldDuration, the histogram of the latencies of the resolvers of
+//     the instrumented fields.
+package metrics
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// The names of the metrics.
+const (
+	MetricOperations       = "graphql_operations_total"
+	MetricParseDuration    = "graphql_parse_duration_seconds"
+	MetricValidateDuration = "graphql_validate_duration_seconds"
+	MetricExecuteDuration  = "graphql_execute_duration_seconds"
+	MetricErrors           = "graphql_errors_total"
+	MetricFieldDuration    = "graphql_field_resolve_duration_seconds"
+)
+
+// The names of the labels of the metrics.
+const (
+	LabelOperationName = "operation_name"
+	LabelOperationType = "operation_type"
+	LabelCode          = "code"
+	LabelParentType    = "parent_type"
+	LabelFieldName     = "field_name"
+)
+
+// help describes the metrics of the package.
+var help = map[string]string{
+	MetricOperations:       "Number of GraphQL operations by name and type.",
+	MetricParseDuration:    "Latency of the parsing of GraphQL documents in seconds.",
+	MetricValidateDuration: "Latency of the validation of GraphQL documents in seconds.",
+	MetricExecuteDuration:  "Latency of the execution of GraphQL operations in seconds.",
+	MetricErrors:           "Number of GraphQL errors by code.",
+	MetricFieldDuration:    "Latency of the resolvers of the instrumented GraphQL fields in seconds.",
+}
+
+// Labels are the labels of a metric, by name.
+type Labels map[string]string
+
+// Sink receives the metrics. Its methods are called concurrently, by
+// concurrent requests and by the resolvers of sibling fields resolved in
+// parallel.
+type Sink interface {
+	// IncCounter increments the counter with the given name and labels.
+	IncCounter(name string, labels Labels)
+
+	// ObserveHistogram adds an observation to the histogram with the given
+	// name and labels.
+	ObserveHistogram(name string, labels Labels, value float64)
+}
+
+// Config is the configuration of an Extension.
+type Config struct {
+	// Sink receives the metrics.
+	Sink Sink
+
+	// InstrumentedFields are the fields whose resolvers are timed, as
+	// schema coordinates such as "Query.user".
+	InstrumentedFields []string
+}
+
+// Extension reports the metrics of the requests of a schema.
+type Extension struct {
+	sink               Sink
+	instrumentedFields map[string]bool
+}
+
+var _ graphql.Extension = (*Extension)(nil)
+
+// New returns an Extension reporting metrics to the Sink of config.
+func New(config Config) *Extension {
+	e := &Extension{
+		sink:               config.Sink,
+		instrumentedFields: map[string]bool{},
+	}
+	for _, field := range config.InstrumentedFields {
+		e.instrumentedFields[field] = true
+	}
+	return e
+}
+
+type requestKey struct{}
+
+// request holds the operation of a request, reported once the request is
+// done.
+type request struct {
+	mu            sync.Mutex
+	operationName string
+	operationType string
+	done          bool
+}
+
+func requestFrom(ctx context.Context) *request {
+	if ctx == nil {
+		return nil
+	}
+	r, _ := ctx.Value(requestKey{}).(*request)
+	return r
+}
+
+func withRequest(ctx context.Context, operationName string) (context.Context, *request) {
+	if r := requestFrom(ctx); r != nil {
+		return ctx, r
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	r := &request{operationName: operationName}
+	return context.WithValue(ctx, requestKey{}, r), r
+}
+
+// setOperation sets the operation of the request, once it is known.
+func (r *request) setOperation(op *ast.OperationDefinition) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.operationType != "" {
+		return
+	}
+	r.operationType = op.Operation
+	if op.Name != nil {
+		r.operationName = op.Name.Value
+	}
+}
+
+// done reports the operation and the errors of the request, once.
+func (e *Extension) done(r *request, errs []gqlerrors.FormattedError) {
+	r.mu.Lock()
+	if r.done {
+		r.mu.Unlock()
+		return
+	}
+	r.done = true
+	labels := Labels{LabelOperationName: r.operationName, LabelOperationType: r.operationType}
+	r.mu.Unlock()
+
+	e.sink.IncCounter(MetricOperations, labels)
+	for _, err := range errs {
+		e.sink.IncCounter(MetricErrors, Labels{LabelCode: err.Code()})
+	}
+}
+
+// observe observes the latency of a phase which started at start.
+func (e *Extension) observe(name string, labels Labels, start time.Time) {
+	e.sink.ObserveHistogram(name, labels, time.Since(start).Seconds())
+}
+
+// Init starts the metrics of the request.
+func (e *Extension) Init(ctx context.Context, p *graphql.Params) context.Context {
+	ctx, _ = withRequest(ctx, p.OperationName)
+	return ctx
+}
+
+// Name returns "metrics".
+func (e *Extension) Name() string {
+	return "metrics"
+}
+
+// ParseDidStart times the parsing, and reports the request if the document
+// is invalid.
+func (e *Extension) ParseDidStart(ctx context.Context) (context.Context, graphql.ParseFinishFunc) {
+	start := time.Now()
+	return ctx, func(err error) {
+		e.observe(MetricParseDuration, Labels{}, start)
+		if r := requestFrom(ctx); r != nil && err != nil {
+			e.done(r, gqlerrors.FormatErrors(err))
+		}
+	}
+}
+
+// ValidationDidStart times the validation, and reports the request if the
+// document is invalid.
+func (e *Extension) ValidationDidStart(ctx context.Context) (context.Context, graphql.ValidationFinishFunc) {
+	start := time.Now()
+	return ctx, func(errs []gqlerrors.FormattedError) {
+		e.observe(MetricValidateDuration, Labels{}, start)
+		if r := requestFrom(ctx); r != nil && len(errs) != 0 {
+			e.done(r, errs)
+		}
+	}
+}
+
+// ExecutionDidStart times the execution, and reports the request with the
+// errors of its result.
+func (e *Extension) ExecutionDidStart(ctx context.Context) (context.Context, graphql.ExecutionFinishFunc) {
+	ctx, r := withRequest(ctx, "")
+	start := time.Now()
+	return ctx, func(result *graphql.Result) {
+		r.mu.Lock()
+		labels := Labels{LabelOperationName: r.operationName, LabelOperationType: r.operationType}
+		r.mu.Unlock()
+		e.observe(MetricExecuteDuration, labels, start)
+
+		var errs []gqlerrors.FormattedError
+		if result != nil {
+			errs = result.Errors
+		}
+		e.done(r, errs)
+	}
+}
+
+// ResolveFieldDidStart times the resolver of the field if it is
+// instrumented.
+func (e *Extension) ResolveFieldDidStart(ctx context.Context, i *graphql.ResolveInfo) (context.Context, graphql.ResolveFieldFinishFunc) {
+	if r := requestFrom(ctx); r != nil {
+		if op, ok := i.Operation.(*ast.OperationDefinition); ok {
+			r.setOperation(op)
+		}
+	}
+	parentType := i.ParentType.Name()
+	if !e.instrumentedFields[parentType+"."+i.FieldName] {
+		return ctx, func(interface{}, error) {}
+	}
+	start := time.Now()
+	return ctx, func(interface{}, error) {
+		e.observe(MetricFieldDuration, Labels{LabelParentType: parentType, LabelFieldName: i.FieldName}, start)
+	}
+}
+
+// HasResult returns false, as the metrics are reported to the Sink.
+func (e *Extension) HasResult() bool {
+	return false
+}
+
+// GetResult returns nil.
+func (e *Extension) GetResult(context.Context) interface{} {
+	return nil
+}
@@ -0,0 +1,180 @@
+package metrics_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/metrics"
+	"github.com/graphql-go/graphql/testutil"
+)
+
+type forbiddenError struct{}
+
+func (forbiddenError) Error() string {
+	return "forbidden"
+}
+
+func (forbiddenError) Extensions() map[string]interface{} {
+	return map[string]interface{}{"code": "FORBIDDEN"}
+}
+
+func newSchema(t *testing.T, sink metrics.Sink) graphql.Schema {
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Query",
+			Fields: graphql.Fields{
+				"hero": &graphql.Field{
+					Type: graphql.String,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return "Luke", nil
+					},
+				},
+				"secret": &graphql.Field{
+					Type: graphql.String,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return nil, forbiddenError{}
+					},
+				},
+			},
+		}),
+		Mutation: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Mutation",
+			Fields: graphql.Fields{
+				"ping": &graphql.Field{
+					Type: graphql.String,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return "pong", nil
+					},
+				},
+			},
+		}),
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	schema.AddExtensions(metrics.New(metrics.Config{
+		Sink:               sink,
+		InstrumentedFields: []string{"Query.hero"},
+	}))
+	return schema
+}
+
+func TestExtension_ReportsTheMetricsOfRequests(t *testing.T) {
+	sink := metrics.NewMemorySink(nil)
+	schema := newSchema(t, sink)
+	for _, query := range []string{
+		`query Hero { hero }`,
+		`query Hero { hero secret }`,
+		`mutation { ping }`,
+		`{ hero`,
+		`{ unknown }`,
+	} {
+		graphql.Do(graphql.Params{Schema: schema, RequestString: query})
+	}
+
+	counters := map[string]struct {
+		name   string
+		labels metrics.Labels
+	}{
+		"Hero queries": {metrics.MetricOperations, metrics.Labels{"operation_name": "Hero", "operation_type": "query"}},
+		"mutations":    {metrics.MetricOperations, metrics.Labels{"operation_name": "", "operation_type": "mutation"}},
+		"invalid":      {metrics.MetricOperations, metrics.Labels{"operation_name": "", "operation_type": ""}},
+		"forbidden":    {metrics.MetricErrors, metrics.Labels{"code": "FORBIDDEN"}},
+		"parse":        {metrics.MetricErrors, metrics.Labels{"code": "GRAPHQL_PARSE_FAILED"}},
+		"validation":   {metrics.MetricErrors, metrics.Labels{"code": "GRAPHQL_VALIDATION_FAILED"}},
+	}
+	expected := map[string]float64{
+		"Hero queries": 2,
+		"mutations":    1,
+		"invalid":      2,
+		"forbidden":    1,
+		"parse":        1,
+		"validation":   1,
+	}
+	got := map[string]float64{}
+	for name, c := range counters {
+		got[name] = sink.Counter(c.name, c.labels)
+	}
+	if !reflect.DeepEqual(expected, got) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, got))
+	}
+
+	histograms := map[string]struct {
+		name   string
+		labels metrics.Labels
+	}{
+		"parse":      {metrics.MetricParseDuration, metrics.Labels{}},
+		"validate":   {metrics.MetricValidateDuration, metrics.Labels{}},
+		"execute":    {metrics.MetricExecuteDuration, metrics.Labels{"operation_name": "Hero", "operation_type": "query"}},
+		"hero":       {metrics.MetricFieldDuration, metrics.Labels{"parent_type": "Query", "field_name": "hero"}},
+		"not marked": {metrics.MetricFieldDuration, metrics.Labels{"parent_type": "Query", "field_name": "secret"}},
+	}
+	expectedCounts := map[string]uint64{
+		"parse":      5,
+		"validate":   4,
+		"execute":    2,
+		"hero":       2,
+		"not marked": 0,
+	}
+	gotCounts := map[string]uint64{}
+	for name, h := range histograms {
+		gotCounts[name], _ = sink.Histogram(h.name, h.labels)
+	}
+	if !reflect.DeepEqual(expectedCounts, gotCounts) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expectedCounts, gotCounts))
+	}
+}
+
+func TestMemorySink_WritesTheTextExpositionFormat(t *testing.T) {
+	sink := metrics.NewMemorySink([]float64{0.1, 1})
+	sink.IncCounter(metrics.MetricErrors, metrics.Labels{"code": "FORBIDDEN"})
+	sink.IncCounter(metrics.MetricErrors, metrics.Labels{"code": "FORBIDDEN"})
+	sink.IncCounter(metrics.MetricErrors, metrics.Labels{"code": `say "hi"`})
+	sink.IncCounter("custom_total", nil)
+	sink.ObserveHistogram(metrics.MetricParseDuration, metrics.Labels{}, 0.05)
+	sink.ObserveHistogram(metrics.MetricParseDuration, metrics.Labels{}, 0.5)
+	sink.ObserveHistogram(metrics.MetricParseDuration, metrics.Labels{}, 2)
+	sink.ObserveHistogram(metrics.MetricFieldDuration, metrics.Labels{"parent_type": "Query", "field_name": "hero"}, 1)
+
+	var b bytes.Buffer
+	if err := sink.WriteText(&b); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := strings.Join([]string{
+		`# TYPE custom_total counter`,
+		`custom_total 1`,
+		`# HELP graphql_errors_total Number of GraphQL errors by code.`,
+		`# TYPE graphql_errors_total counter`,
+		`graphql_errors_total{code="FORBIDDEN"} 2`,
+		`graphql_errors_total{code="say \"hi\""} 1`,
+		`# HELP graphql_field_resolve_duration_seconds Latency of the resolvers of the instrumented GraphQL fields in seconds.`,
+		`# TYPE graphql_field_resolve_duration_seconds histogram`,
+		`graphql_field_resolve_duration_seconds_bucket{field_name="hero",le="0.1",parent_type="Query"} 0`,
+		`graphql_field_resolve_duration_seconds_bucket{field_name="hero",le="1",parent_type="Query"} 1`,
+		`graphql_field_resolve_duration_seconds_bucket{field_name="hero",le="+Inf",parent_type="Query"} 1`,
+		`graphql_field_resolve_duration_seconds_sum{field_name="hero",parent_type="Query"} 1`,
+		`graphql_field_resolve_duration_seconds_count{field_name="hero",parent_type="Query"} 1`,
+		`# HELP graphql_parse_duration_seconds Latency of the parsing of GraphQL documents in seconds.`,
+		`# TYPE graphql_parse_duration_seconds histogram`,
+		`graphql_parse_duration_seconds_bucket{le="0.1"} 1`,
+		`graphql_parse_duration_seconds_bucket{le="1"} 2`,
+		`graphql_parse_duration_seconds_bucket{le="+Inf"} 3`,
+		`graphql_parse_duration_seconds_sum 2.55`,
+		`graphql_parse_duration_seconds_count 3`,
+		``,
+	}, "\n")
+	if b.String() != expected {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, b.String()))
+	}
+
+	resp := httptest.NewRecorder()
+	sink.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if resp.Code != http.StatusOK || resp.Body.String() != expected {
+		t.Fatalf("Unexpected response %v: %s", resp.Code, resp.Body.String())
+	}
+}
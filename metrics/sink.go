@@ -0,0 +1,221 @@
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultBuckets are the upper bounds of the buckets of the histograms of a
+// MemorySink, in seconds, when none are given.
+var DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// MemorySink is a Sink keeping the metrics in memory. It is an
+// http.Handler serving them in the Prometheus text exposition format.
+type MemorySink struct {
+	buckets []float64
+
+	mu         sync.Mutex
+	counters   map[string]map[string]*counter
+	histograms map[string]map[string]*histogram
+}
+
+var _ Sink = (*MemorySink)(nil)
+
+type counter struct {
+	labels Labels
+	value  float64
+}
+
+type histogram struct {
+	labels Labels
+	// counts are the numbers of observations in each bucket, not cumulated.
+	counts []uint64
+	sum    float64
+	count  uint64
+}
+
+// NewMemorySink returns an empty MemorySink whose histograms have the given
+// buckets, or the DefaultBuckets if buckets is empty.
+func NewMemorySink(buckets []float64) *MemorySink {
+	if len(buckets) == 0 {
+		buckets = DefaultBuckets
+	}
+	buckets = append([]float64(nil), buckets...)
+	sort.Float64s(buckets)
+	return &MemorySink{
+		buckets:    buckets,
+		counters:   map[string]map[string]*counter{},
+		histograms: map[string]map[string]*histogram{},
+	}
+}
+
+// labelsKey returns the labels in the format of the exposition, which
+// identifies them.
+func labelsKey(labels Labels) string {
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	pairs := make([]string, len(names))
+	for i, name := range names {
+		pairs[i] = name + `="` + escapeLabelValue(labels[name]) + `"`
+	}
+	return strings.Join(pairs, ",")
+}
+
+var labelValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabelValue(value string) string {
+	return labelValueEscaper.Replace(value)
+}
+
+func copyLabels(labels Labels) Labels {
+	c := make(Labels, len(labels))
+	for name, value := range labels {
+		c[name] = value
+	}
+	return c
+}
+
+// IncCounter increments the counter with the given name and labels.
+func (s *MemorySink) IncCounter(name string, labels Labels) {
+	key := labelsKey(labels)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	counters, ok := s.counters[name]
+	if !ok {
+		counters = map[string]*counter{}
+		s.counters[name] = counters
+	}
+	c, ok := counters[key]
+	if !ok {
+		c = &counter{labels: copyLabels(labels)}
+		counters[key] = c
+	}
+	c.value++
+}
+
+// ObserveHistogram adds an observation to the histogram with the given name
+// and labels.
+func (s *MemorySink) ObserveHistogram(name string, labels Labels, value float64) {
+	key := labelsKey(labels)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	histograms, ok := s.histograms[name]
+	if !ok {
+		histograms = map[string]*histogram{}
+		s.histograms[name] = histograms
+	}
+	h, ok := histograms[key]
+	if !ok {
+		h = &histogram{labels: copyLabels(labels), counts: make([]uint64, len(s.buckets)+1)}
+		histograms[key] = h
+	}
+	// the last count is the one of the +Inf bucket
+	h.counts[sort.SearchFloat64s(s.buckets, value)]++
+	h.sum += value
+	h.count++
+}
+
+// Counter returns the value of the counter with the given name and labels.
+func (s *MemorySink) Counter(name string, labels Labels) float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if c, ok := s.counters[name][labelsKey(labels)]; ok {
+		return c.value
+	}
+	return 0
+}
+
+// Histogram returns the number of observations of the histogram with the
+// given name and labels, and their sum.
+func (s *MemorySink) Histogram(name string, labels Labels) (count uint64, sum float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if h, ok := s.histograms[name][labelsKey(labels)]; ok {
+		return h.count, h.sum
+	}
+	return 0, 0
+}
+
+// WriteText writes the metrics in the Prometheus text exposition format,
+// sorted by name and labels.
+func (s *MemorySink) WriteText(w io.Writer) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	bw := bufio.NewWriter(w)
+	for _, name := range sortedKeys(s.counters) {
+		writeHeader(bw, name, "counter")
+		counters := s.counters[name]
+		for _, key := range sortedKeys(counters) {
+			writeSample(bw, name, key, counters[key].value)
+		}
+	}
+	for _, name := range sortedKeys(s.histograms) {
+		writeHeader(bw, name, "histogram")
+		histograms := s.histograms[name]
+		for _, key := range sortedKeys(histograms) {
+			h := histograms[key]
+			var cumulated uint64
+			for i, count := range h.counts {
+				cumulated += count
+				le := math.Inf(1)
+				if i < len(s.buckets) {
+					le = s.buckets[i]
+				}
+				bucketLabels := copyLabels(h.labels)
+				bucketLabels["le"] = formatValue(le)
+				writeSample(bw, name+"_bucket", labelsKey(bucketLabels), float64(cumulated))
+			}
+			writeSample(bw, name+"_sum", key, h.sum)
+			writeSample(bw, name+"_count", key, float64(h.count))
+		}
+	}
+	return bw.Flush()
+}
+
+// ServeHTTP serves the metrics in the Prometheus text exposition format.
+func (s *MemorySink) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	s.WriteText(w)
+}
+
+func writeHeader(w *bufio.Writer, name, typ string) {
+	if h, ok := help[name]; ok {
+		fmt.Fprintf(w, "# HELP %s %s\n", name, h)
+	}
+	fmt.Fprintf(w, "# TYPE %s %s\n", name, typ)
+}
+
+func writeSample(w *bufio.Writer, name, labels string, value float64) {
+	if labels != "" {
+		fmt.Fprintf(w, "%s{%s} %s\n", name, labels, formatValue(value))
+		return
+	}
+	fmt.Fprintf(w, "%s %s\n", name, formatValue(value))
+}
+
+func formatValue(value float64) string {
+	if math.IsInf(value, 1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
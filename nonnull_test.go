@@ -121,7 +121,8 @@ func TestNonNull_NullsANullableFieldThatThrowsSynchronously(t *testing.T) {
 		},
 		Errors: []gqlerrors.FormattedError{
 			{
-				Message: syncError,
+				Message:    syncError,
+				Extensions: map[string]interface{}{"code": gqlerrors.CodeInternalServerError},
 				Locations: []location.SourceLocation{
 					{
 						Line: 3, Column: 9,
@@ -159,7 +160,8 @@ func TestNonNull_NullsANullableFieldThatThrowsInAPromise(t *testing.T) {
 		},
 		Errors: []gqlerrors.FormattedError{
 			{
-				Message: promiseError,
+				Message:    promiseError,
+				Extensions: map[string]interface{}{"code": gqlerrors.CodeInternalServerError},
 				Locations: []location.SourceLocation{
 					{
 						Line: 3, Column: 9,
@@ -199,7 +201,8 @@ func TestNonNull_NullsASynchronouslyReturnedObjectThatContainsANullableFieldThat
 		},
 		Errors: []gqlerrors.FormattedError{
 			{
-				Message: nonNullSyncError,
+				Message:    nonNullSyncError,
+				Extensions: map[string]interface{}{"code": gqlerrors.CodeInternalServerError},
 				Locations: []location.SourceLocation{
 					{
 						Line: 4, Column: 11,
@@ -240,7 +243,8 @@ func TestNonNull_NullsASynchronouslyReturnedObjectThatContainsANonNullableFieldT
 		},
 		Errors: []gqlerrors.FormattedError{
 			{
-				Message: nonNullPromiseError,
+				Message:    nonNullPromiseError,
+				Extensions: map[string]interface{}{"code": gqlerrors.CodeInternalServerError},
 				Locations: []location.SourceLocation{
 					{
 						Line: 4, Column: 11,
@@ -281,7 +285,8 @@ func TestNonNull_NullsAnObjectReturnedInAPromiseThatContainsANonNullableFieldTha
 		},
 		Errors: []gqlerrors.FormattedError{
 			{
-				Message: nonNullSyncError,
+				Message:    nonNullSyncError,
+				Extensions: map[string]interface{}{"code": gqlerrors.CodeInternalServerError},
 				Locations: []location.SourceLocation{
 					{
 						Line: 4, Column: 11,
@@ -322,7 +327,8 @@ func TestNonNull_NullsAnObjectReturnedInAPromiseThatContainsANonNullableFieldTha
 		},
 		Errors: []gqlerrors.FormattedError{
 			{
-				Message: nonNullPromiseError,
+				Message:    nonNullPromiseError,
+				Extensions: map[string]interface{}{"code": gqlerrors.CodeInternalServerError},
 				Locations: []location.SourceLocation{
 					{
 						Line: 4, Column: 11,
@@ -408,7 +414,8 @@ func TestNonNull_NullsAComplexTreeOfNullableFieldsThatThrow(t *testing.T) {
 		},
 		Errors: []gqlerrors.FormattedError{
 			gqlerrors.FormatError(gqlerrors.Error{
-				Message: syncError,
+				Message:    syncError,
+				Extensions: map[string]interface{}{"code": gqlerrors.CodeInternalServerError},
 				Locations: []location.SourceLocation{
 					{Line: 4, Column: 11},
 				},
@@ -417,7 +424,8 @@ func TestNonNull_NullsAComplexTreeOfNullableFieldsThatThrow(t *testing.T) {
 				},
 			}),
 			gqlerrors.FormatError(gqlerrors.Error{
-				Message: syncError,
+				Message:    syncError,
+				Extensions: map[string]interface{}{"code": gqlerrors.CodeInternalServerError},
 				Locations: []location.SourceLocation{
 					{Line: 7, Column: 13},
 				},
@@ -426,7 +434,8 @@ func TestNonNull_NullsAComplexTreeOfNullableFieldsThatThrow(t *testing.T) {
 				},
 			}),
 			gqlerrors.FormatError(gqlerrors.Error{
-				Message: syncError,
+				Message:    syncError,
+				Extensions: map[string]interface{}{"code": gqlerrors.CodeInternalServerError},
 				Locations: []location.SourceLocation{
 					{Line: 11, Column: 13},
 				},
@@ -435,7 +444,8 @@ func TestNonNull_NullsAComplexTreeOfNullableFieldsThatThrow(t *testing.T) {
 				},
 			}),
 			gqlerrors.FormatError(gqlerrors.Error{
-				Message: syncError,
+				Message:    syncError,
+				Extensions: map[string]interface{}{"code": gqlerrors.CodeInternalServerError},
 				Locations: []location.SourceLocation{
 					{Line: 16, Column: 11},
 				},
@@ -444,7 +454,8 @@ func TestNonNull_NullsAComplexTreeOfNullableFieldsThatThrow(t *testing.T) {
 				},
 			}),
 			gqlerrors.FormatError(gqlerrors.Error{
-				Message: syncError,
+				Message:    syncError,
+				Extensions: map[string]interface{}{"code": gqlerrors.CodeInternalServerError},
 				Locations: []location.SourceLocation{
 					{Line: 19, Column: 13},
 				},
@@ -453,7 +464,8 @@ func TestNonNull_NullsAComplexTreeOfNullableFieldsThatThrow(t *testing.T) {
 				},
 			}),
 			gqlerrors.FormatError(gqlerrors.Error{
-				Message: syncError,
+				Message:    syncError,
+				Extensions: map[string]interface{}{"code": gqlerrors.CodeInternalServerError},
 				Locations: []location.SourceLocation{
 					{Line: 23, Column: 13},
 				},
@@ -462,7 +474,8 @@ func TestNonNull_NullsAComplexTreeOfNullableFieldsThatThrow(t *testing.T) {
 				},
 			}),
 			gqlerrors.FormatError(gqlerrors.Error{
-				Message: promiseError,
+				Message:    promiseError,
+				Extensions: map[string]interface{}{"code": gqlerrors.CodeInternalServerError},
 				Locations: []location.SourceLocation{
 					{Line: 5, Column: 11},
 				},
@@ -471,7 +484,8 @@ func TestNonNull_NullsAComplexTreeOfNullableFieldsThatThrow(t *testing.T) {
 				},
 			}),
 			gqlerrors.FormatError(gqlerrors.Error{
-				Message: promiseError,
+				Message:    promiseError,
+				Extensions: map[string]interface{}{"code": gqlerrors.CodeInternalServerError},
 				Locations: []location.SourceLocation{
 					{Line: 8, Column: 13},
 				},
@@ -480,7 +494,8 @@ func TestNonNull_NullsAComplexTreeOfNullableFieldsThatThrow(t *testing.T) {
 				},
 			}),
 			gqlerrors.FormatError(gqlerrors.Error{
-				Message: promiseError,
+				Message:    promiseError,
+				Extensions: map[string]interface{}{"code": gqlerrors.CodeInternalServerError},
 				Locations: []location.SourceLocation{
 					{Line: 12, Column: 13},
 				},
@@ -489,7 +504,8 @@ func TestNonNull_NullsAComplexTreeOfNullableFieldsThatThrow(t *testing.T) {
 				},
 			}),
 			gqlerrors.FormatError(gqlerrors.Error{
-				Message: promiseError,
+				Message:    promiseError,
+				Extensions: map[string]interface{}{"code": gqlerrors.CodeInternalServerError},
 				Locations: []location.SourceLocation{
 					{Line: 17, Column: 11},
 				},
@@ -498,7 +514,8 @@ func TestNonNull_NullsAComplexTreeOfNullableFieldsThatThrow(t *testing.T) {
 				},
 			}),
 			gqlerrors.FormatError(gqlerrors.Error{
-				Message: promiseError,
+				Message:    promiseError,
+				Extensions: map[string]interface{}{"code": gqlerrors.CodeInternalServerError},
 				Locations: []location.SourceLocation{
 					{Line: 20, Column: 13},
 				},
@@ -507,7 +524,8 @@ func TestNonNull_NullsAComplexTreeOfNullableFieldsThatThrow(t *testing.T) {
 				},
 			}),
 			gqlerrors.FormatError(gqlerrors.Error{
-				Message: promiseError,
+				Message:    promiseError,
+				Extensions: map[string]interface{}{"code": gqlerrors.CodeInternalServerError},
 				Locations: []location.SourceLocation{
 					{Line: 24, Column: 13},
 				},
@@ -591,7 +609,8 @@ func TestNonNull_NullsTheFirstNullableObjectAfterAFieldThrowsInALongChainOfField
 		},
 		Errors: []gqlerrors.FormattedError{
 			gqlerrors.FormatError(gqlerrors.Error{
-				Message: nonNullSyncError,
+				Message:    nonNullSyncError,
+				Extensions: map[string]interface{}{"code": gqlerrors.CodeInternalServerError},
 				Locations: []location.SourceLocation{
 					{Line: 8, Column: 19},
 				},
@@ -601,7 +620,8 @@ func TestNonNull_NullsTheFirstNullableObjectAfterAFieldThrowsInALongChainOfField
 				},
 			}),
 			gqlerrors.FormatError(gqlerrors.Error{
-				Message: nonNullSyncError,
+				Message:    nonNullSyncError,
+				Extensions: map[string]interface{}{"code": gqlerrors.CodeInternalServerError},
 				Locations: []location.SourceLocation{
 					{Line: 19, Column: 19},
 				},
@@ -611,7 +631,8 @@ func TestNonNull_NullsTheFirstNullableObjectAfterAFieldThrowsInALongChainOfField
 				},
 			}),
 			gqlerrors.FormatError(gqlerrors.Error{
-				Message: nonNullPromiseError,
+				Message:    nonNullPromiseError,
+				Extensions: map[string]interface{}{"code": gqlerrors.CodeInternalServerError},
 				Locations: []location.SourceLocation{
 					{Line: 30, Column: 19},
 				},
@@ -621,7 +642,8 @@ func TestNonNull_NullsTheFirstNullableObjectAfterAFieldThrowsInALongChainOfField
 				},
 			}),
 			gqlerrors.FormatError(gqlerrors.Error{
-				Message: nonNullPromiseError,
+				Message:    nonNullPromiseError,
+				Extensions: map[string]interface{}{"code": gqlerrors.CodeInternalServerError},
 				Locations: []location.SourceLocation{
 					{Line: 41, Column: 19},
 				},
@@ -713,7 +735,8 @@ func TestNonNull_NullsASynchronouslyReturnedObjectThatContainsANonNullableFieldT
 		},
 		Errors: []gqlerrors.FormattedError{
 			{
-				Message: `Cannot return null for non-nullable field DataType.nonNullSync.`,
+				Message:    `Cannot return null for non-nullable field DataType.nonNullSync.`,
+				Extensions: map[string]interface{}{"code": gqlerrors.CodeInternalServerError},
 				Locations: []location.SourceLocation{
 					{Line: 4, Column: 11},
 				},
@@ -752,7 +775,8 @@ func TestNonNull_NullsASynchronouslyReturnedObjectThatContainsANonNullableFieldT
 		},
 		Errors: []gqlerrors.FormattedError{
 			{
-				Message: `Cannot return null for non-nullable field DataType.nonNullPromise.`,
+				Message:    `Cannot return null for non-nullable field DataType.nonNullPromise.`,
+				Extensions: map[string]interface{}{"code": gqlerrors.CodeInternalServerError},
 				Locations: []location.SourceLocation{
 					{Line: 4, Column: 11},
 				},
@@ -792,7 +816,8 @@ func TestNonNull_NullsAnObjectReturnedInAPromiseThatContainsANonNullableFieldTha
 		},
 		Errors: []gqlerrors.FormattedError{
 			{
-				Message: `Cannot return null for non-nullable field DataType.nonNullSync.`,
+				Message:    `Cannot return null for non-nullable field DataType.nonNullSync.`,
+				Extensions: map[string]interface{}{"code": gqlerrors.CodeInternalServerError},
 				Locations: []location.SourceLocation{
 					{Line: 4, Column: 11},
 				},
@@ -831,7 +856,8 @@ func TestNonNull_NullsAnObjectReturnedInAPromiseThatContainsANonNullableFieldTha
 		},
 		Errors: []gqlerrors.FormattedError{
 			{
-				Message: `Cannot return null for non-nullable field DataType.nonNullPromise.`,
+				Message:    `Cannot return null for non-nullable field DataType.nonNullPromise.`,
+				Extensions: map[string]interface{}{"code": gqlerrors.CodeInternalServerError},
 				Locations: []location.SourceLocation{
 					{Line: 4, Column: 11},
 				},
@@ -985,7 +1011,8 @@ func TestNonNull_NullsTheFirstNullableObjectAfterAFieldReturnsNullInALongChainOf
 		},
 		Errors: []gqlerrors.FormattedError{
 			{
-				Message: `Cannot return null for non-nullable field DataType.nonNullSync.`,
+				Message:    `Cannot return null for non-nullable field DataType.nonNullSync.`,
+				Extensions: map[string]interface{}{"code": gqlerrors.CodeInternalServerError},
 				Locations: []location.SourceLocation{
 					{Line: 8, Column: 19},
 				},
@@ -995,7 +1022,8 @@ func TestNonNull_NullsTheFirstNullableObjectAfterAFieldReturnsNullInALongChainOf
 				},
 			},
 			{
-				Message: `Cannot return null for non-nullable field DataType.nonNullSync.`,
+				Message:    `Cannot return null for non-nullable field DataType.nonNullSync.`,
+				Extensions: map[string]interface{}{"code": gqlerrors.CodeInternalServerError},
 				Locations: []location.SourceLocation{
 					{Line: 19, Column: 19},
 				},
@@ -1005,7 +1033,8 @@ func TestNonNull_NullsTheFirstNullableObjectAfterAFieldReturnsNullInALongChainOf
 				},
 			},
 			{
-				Message: `Cannot return null for non-nullable field DataType.nonNullPromise.`,
+				Message:    `Cannot return null for non-nullable field DataType.nonNullPromise.`,
+				Extensions: map[string]interface{}{"code": gqlerrors.CodeInternalServerError},
 				Locations: []location.SourceLocation{
 					{Line: 30, Column: 19},
 				},
@@ -1015,7 +1044,8 @@ func TestNonNull_NullsTheFirstNullableObjectAfterAFieldReturnsNullInALongChainOf
 				},
 			},
 			{
-				Message: `Cannot return null for non-nullable field DataType.nonNullPromise.`,
+				Message:    `Cannot return null for non-nullable field DataType.nonNullPromise.`,
+				Extensions: map[string]interface{}{"code": gqlerrors.CodeInternalServerError},
 				Locations: []location.SourceLocation{
 					{Line: 41, Column: 19},
 				},
@@ -1051,7 +1081,8 @@ func TestNonNull_NullsTheTopLevelIfSyncNonNullableFieldThrows(t *testing.T) {
 		Data: nil,
 		Errors: []gqlerrors.FormattedError{
 			{
-				Message: nonNullSyncError,
+				Message:    nonNullSyncError,
+				Extensions: map[string]interface{}{"code": gqlerrors.CodeInternalServerError},
 				Locations: []location.SourceLocation{
 					{Line: 2, Column: 17},
 				},
@@ -1083,7 +1114,8 @@ func TestNonNull_NullsTheTopLevelIfSyncNonNullableFieldErrors(t *testing.T) {
 		Data: nil,
 		Errors: []gqlerrors.FormattedError{
 			{
-				Message: nonNullPromiseError,
+				Message:    nonNullPromiseError,
+				Extensions: map[string]interface{}{"code": gqlerrors.CodeInternalServerError},
 				Locations: []location.SourceLocation{
 					{Line: 2, Column: 17},
 				},
@@ -1115,7 +1147,8 @@ func TestNonNull_NullsTheTopLevelIfSyncNonNullableFieldReturnsNull(t *testing.T)
 		Data: nil,
 		Errors: []gqlerrors.FormattedError{
 			{
-				Message: `Cannot return null for non-nullable field DataType.nonNullSync.`,
+				Message:    `Cannot return null for non-nullable field DataType.nonNullSync.`,
+				Extensions: map[string]interface{}{"code": gqlerrors.CodeInternalServerError},
 				Locations: []location.SourceLocation{
 					{Line: 2, Column: 17},
 				},
@@ -1147,7 +1180,8 @@ func TestNonNull_NullsTheTopLevelIfSyncNonNullableFieldResolvesNull(t *testing.T
 		Data: nil,
 		Errors: []gqlerrors.FormattedError{
 			{
-				Message: `Cannot return null for non-nullable field DataType.nonNullPromise.`,
+				Message:    `Cannot return null for non-nullable field DataType.nonNullPromise.`,
+				Extensions: map[string]interface{}{"code": gqlerrors.CodeInternalServerError},
 				Locations: []location.SourceLocation{
 					{Line: 2, Column: 17},
 				},
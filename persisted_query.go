@@ -0,0 +1,194 @@
+package graphql
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"sync"
+
+	"github.com/graphql-go/graphql/gqlerrors"
+)
+
+// PersistedQueryVersion is the version of the automatic persisted queries
+// protocol supported by Do.
+const PersistedQueryVersion = 1
+
+// PersistedQuery is the persistedQuery extension of a request using automatic
+// persisted queries, sent instead of, or along with, the query text.
+type PersistedQuery struct {
+	Version    int    `json:"version"`
+	Sha256Hash string `json:"sha256Hash"`
+}
+
+// PersistedQueryStore stores the query texts of automatic persisted queries,
+// keyed by their SHA-256 hash. Implementations must be safe for concurrent
+// use.
+type PersistedQueryStore interface {
+	// Get returns the query text stored with the given hash, if any.
+	Get(ctx context.Context, hash string) (query string, ok bool, err error)
+
+	// Set stores the query text with the given hash.
+	Set(ctx context.Context, hash string, query string) error
+}
+
+// PersistedQueryError is an error of the automatic persisted queries or of
+// the safelisted operations, reported with its code in the extensions of the
+// error.
+type PersistedQueryError struct {
+	Message string
+	Code    string
+}
+
+func (e *PersistedQueryError) Error() string {
+	return e.Message
+}
+
+// Extensions implements gqlerrors.ExtendedError.
+func (e *PersistedQueryError) Extensions() map[string]interface{} {
+	return map[string]interface{}{"code": e.Code}
+}
+
+// UserFacing implements gqlerrors.UserFacingError, as the error is meant for
+// clients.
+func (e *PersistedQueryError) UserFacing() bool {
+	return true
+}
+
+var (
+	// ErrPersistedQueryNotFound is reported when the hash of a request is
+	// not in the store, in which case the client sends the query text again
+	// along with its hash.
+	ErrPersistedQueryNotFound = &PersistedQueryError{
+		Message: "PersistedQueryNotFound",
+		Code:    "PERSISTED_QUERY_NOT_FOUND",
+	}
+
+	// ErrPersistedQueryNotSupported is reported when a request uses
+	// automatic persisted queries but no store is configured.
+	ErrPersistedQueryNotSupported = &PersistedQueryError{
+		Message: "PersistedQueryNotSupported",
+		Code:    "PERSISTED_QUERY_NOT_SUPPORTED",
+	}
+
+	// ErrPersistedQueryHashMismatch is reported when the query text of a
+	// request does not have the hash sent along with it.
+	ErrPersistedQueryHashMismatch = &PersistedQueryError{
+		Message: "provided sha does not match query",
+		Code:    "BAD_USER_INPUT",
+	}
+
+	// ErrPersistedQueryVersion is reported when a request uses another
+	// version of the protocol than PersistedQueryVersion.
+	ErrPersistedQueryVersion = &PersistedQueryError{
+		Message: "Unsupported persisted query version",
+		Code:    "BAD_USER_INPUT",
+	}
+)
+
+// LoadPersistedQuery returns the query text of a request using automatic
+// persisted queries. A request without query text is looked up in the store,
+// while a request with query text registers it in the store once its hash is
+// verified.
+func LoadPersistedQuery(ctx context.Context, store PersistedQueryStore, persistedQuery *PersistedQuery, query string) (string, error) {
+	if store == nil {
+		return "", ErrPersistedQueryNotSupported
+	}
+	if persistedQuery.Version != PersistedQueryVersion {
+		return "", ErrPersistedQueryVersion
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	hash := strings.ToLower(persistedQuery.Sha256Hash)
+	if query == "" {
+		query, ok, err := store.Get(ctx, hash)
+		if err != nil {
+			return "", err
+		}
+		if !ok {
+			return "", ErrPersistedQueryNotFound
+		}
+		return query, nil
+	}
+
+	sum := sha256.Sum256([]byte(query))
+	if hex.EncodeToString(sum[:]) != hash {
+		return "", ErrPersistedQueryHashMismatch
+	}
+	if err := store.Set(ctx, hash, query); err != nil {
+		return "", err
+	}
+	return query, nil
+}
+
+// loadPersistedQuery sets the query text of p from its persisted query, if
+// any.
+func loadPersistedQuery(p *Params) []gqlerrors.FormattedError {
+	if p.PersistedQuery == nil {
+		return nil
+	}
+	query, err := LoadPersistedQuery(p.Context, p.PersistedQueryStore, p.PersistedQuery, p.RequestString)
+	if err != nil {
+		return gqlerrors.FormatErrors(err)
+	}
+	p.RequestString = query
+	return nil
+}
+
+// inMemoryPersistedQueryStore is a PersistedQueryStore evicting the least
+// recently used query once it is full.
+type inMemoryPersistedQueryStore struct {
+	mu      sync.Mutex
+	size    int
+	entries map[string]*list.Element
+	// order holds the entries from the most to the least recently used
+	order *list.List
+}
+
+type inMemoryPersistedQuery struct {
+	hash  string
+	query string
+}
+
+// NewInMemoryPersistedQueryStore returns a PersistedQueryStore holding at
+// most size queries in memory, evicting the least recently used ones.
+func NewInMemoryPersistedQueryStore(size int) PersistedQueryStore {
+	return &inMemoryPersistedQueryStore{
+		size:    size,
+		entries: map[string]*list.Element{},
+		order:   list.New(),
+	}
+}
+
+func (s *inMemoryPersistedQueryStore) Get(ctx context.Context, hash string) (string, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	element, ok := s.entries[hash]
+	if !ok {
+		return "", false, nil
+	}
+	s.order.MoveToFront(element)
+	return element.Value.(*inMemoryPersistedQuery).query, true, nil
+}
+
+func (s *inMemoryPersistedQueryStore) Set(ctx context.Context, hash string, query string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if element, ok := s.entries[hash]; ok {
+		s.order.MoveToFront(element)
+		return nil
+	}
+	if s.size <= 0 {
+		return nil
+	}
+	for s.order.Len() >= s.size {
+		oldest := s.order.Back()
+		s.order.Remove(oldest)
+		delete(s.entries, oldest.Value.(*inMemoryPersistedQuery).hash)
+	}
+	s.entries[hash] = s.order.PushFront(&inMemoryPersistedQuery{hash: hash, query: query})
+	return nil
+}
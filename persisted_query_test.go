@@ -0,0 +1,118 @@
+package graphql_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"reflect"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/testutil"
+)
+
+func sha256Hash(query string) string {
+	sum := sha256.Sum256([]byte(query))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestDoLoadsAutomaticPersistedQueries(t *testing.T) {
+	store := graphql.NewInMemoryPersistedQueryStore(10)
+	query := `{ hero { name } }`
+	persistedQuery := &graphql.PersistedQuery{Version: 1, Sha256Hash: sha256Hash(query)}
+
+	result := graphql.Do(graphql.Params{
+		Schema:              testutil.StarWarsSchema,
+		PersistedQuery:      persistedQuery,
+		PersistedQueryStore: store,
+	})
+	expectedErrors := gqlerrors.FormatErrors(graphql.ErrPersistedQueryNotFound)
+	if !reflect.DeepEqual(expectedErrors, result.Errors) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expectedErrors, result.Errors))
+	}
+	if code := result.Errors[0].Extensions["code"]; code != "PERSISTED_QUERY_NOT_FOUND" {
+		t.Fatalf("Unexpected error code: %v", code)
+	}
+
+	expected := &graphql.Result{
+		Data: map[string]interface{}{
+			"hero": map[string]interface{}{
+				"name": "R2-D2",
+			},
+		},
+	}
+	for _, requestString := range []string{query, ""} {
+		result = graphql.Do(graphql.Params{
+			Schema:              testutil.StarWarsSchema,
+			RequestString:       requestString,
+			PersistedQuery:      persistedQuery,
+			PersistedQueryStore: store,
+		})
+		if !reflect.DeepEqual(expected, result) {
+			t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
+		}
+	}
+}
+
+func TestDoRejectsInvalidAutomaticPersistedQueries(t *testing.T) {
+	query := `{ hero { name } }`
+	tests := map[string]struct {
+		params   graphql.Params
+		expected error
+	}{
+		"hash mismatch": {
+			params: graphql.Params{
+				RequestString:       query,
+				PersistedQuery:      &graphql.PersistedQuery{Version: 1, Sha256Hash: sha256Hash("{ hero { id } }")},
+				PersistedQueryStore: graphql.NewInMemoryPersistedQueryStore(10),
+			},
+			expected: graphql.ErrPersistedQueryHashMismatch,
+		},
+		"unsupported version": {
+			params: graphql.Params{
+				RequestString:       query,
+				PersistedQuery:      &graphql.PersistedQuery{Version: 2, Sha256Hash: sha256Hash(query)},
+				PersistedQueryStore: graphql.NewInMemoryPersistedQueryStore(10),
+			},
+			expected: graphql.ErrPersistedQueryVersion,
+		},
+		"no store": {
+			params: graphql.Params{
+				RequestString:  query,
+				PersistedQuery: &graphql.PersistedQuery{Version: 1, Sha256Hash: sha256Hash(query)},
+			},
+			expected: graphql.ErrPersistedQueryNotSupported,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			test.params.Schema = testutil.StarWarsSchema
+			result := graphql.Do(test.params)
+			expectedErrors := gqlerrors.FormatErrors(test.expected)
+			if !reflect.DeepEqual(expectedErrors, result.Errors) {
+				t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expectedErrors, result.Errors))
+			}
+		})
+	}
+}
+
+func TestInMemoryPersistedQueryStoreEvictsLeastRecentlyUsed(t *testing.T) {
+	ctx := context.Background()
+	store := graphql.NewInMemoryPersistedQueryStore(2)
+	store.Set(ctx, "a", "{ a }")
+	store.Set(ctx, "b", "{ b }")
+	if query, ok, _ := store.Get(ctx, "a"); !ok || query != "{ a }" {
+		t.Fatalf("Expected the first query to be stored")
+	}
+	store.Set(ctx, "c", "{ c }")
+
+	if _, ok, _ := store.Get(ctx, "b"); ok {
+		t.Fatalf("Expected the least recently used query to be evicted")
+	}
+	for hash, expected := range map[string]string{"a": "{ a }", "c": "{ c }"} {
+		if query, ok, _ := store.Get(ctx, hash); !ok || query != expected {
+			t.Fatalf("Expected query %v to be stored", hash)
+		}
+	}
+}
@@ -62,3 +62,88 @@ func TestRace(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestRaceConcurrentExecution(t *testing.T) {
+	tempdir, err := ioutil.TempDir("", "race")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempdir)
+
+	filename := filepath.Join(tempdir, "example.go")
+	err = ioutil.WriteFile(filename, []byte(`
+		package main
+
+		import (
+			"errors"
+			"fmt"
+
+			"github.com/graphql-go/graphql"
+		)
+
+		func main() {
+			itemType := graphql.NewObject(graphql.ObjectConfig{
+				Name: "Item",
+				Fields: graphql.Fields{
+					"name": &graphql.Field{
+						Type: graphql.String,
+						Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+							return p.Source, nil
+						},
+					},
+					"error": &graphql.Field{
+						Type: graphql.String,
+						Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+							return nil, errors.New("failed")
+						},
+					},
+					"panic": &graphql.Field{
+						Type: graphql.NewNonNull(graphql.String),
+						Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+							panic(errors.New("panicked"))
+						},
+					},
+					"thunk": &graphql.Field{
+						Type: graphql.String,
+						Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+							return func() (interface{}, error) { return p.Source, nil }, nil
+						},
+					},
+				},
+			})
+			itemsField := &graphql.Field{
+				Type: graphql.NewList(itemType),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return []interface{}{"a", "b", "c", "d"}, nil
+				},
+			}
+			schema, _ := graphql.NewSchema(graphql.SchemaConfig{
+				Query: graphql.NewObject(graphql.ObjectConfig{
+					Name: "RootQuery",
+					Fields: graphql.Fields{
+						"first":  itemsField,
+						"second": itemsField,
+						"third":  itemsField,
+					},
+				}),
+			})
+			result := graphql.Do(graphql.Params{
+				Schema:        schema,
+				RequestString: "{ first { name error thunk } second { name panic } third { thunk error } }",
+				Concurrency:   4,
+			})
+			if len(result.Errors) != 12 {
+				fmt.Println(result.Errors)
+			}
+		}
+	`), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := exec.Command("go", "run", "-race", filename).CombinedOutput()
+	if err != nil || len(result) != 0 {
+		t.Log(string(result))
+		t.Fatal(err)
+	}
+}
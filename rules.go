@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"math"
 	"reflect"
+	"runtime"
 	"sort"
 	"strings"
 
@@ -47,6 +48,33 @@ type ValidationRuleInstance struct {
 }
 type ValidationRuleFn func(context *ValidationContext) *ValidationRuleInstance
 
+// Name returns the name of the rule, which is the name of its function, e.g.
+// "FieldsOnCorrectTypeRule", or of the function returning it, e.g.
+// "MaxDepthRule", as long as it ends with "Rule". It returns "" for the other
+// anonymous functions.
+func (rule ValidationRuleFn) Name() string {
+	fn := runtime.FuncForPC(reflect.ValueOf(rule).Pointer())
+	if fn == nil {
+		return ""
+	}
+	name := fn.Name()
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+	// the name is qualified by the name of the package, and that of an
+	// anonymous function by the name of the enclosing function
+	if i := strings.Index(name, "."); i >= 0 {
+		name = name[i+1:]
+	}
+	if i := strings.Index(name, "."); i >= 0 {
+		name = name[:i]
+		if !strings.HasSuffix(name, "Rule") {
+			return ""
+		}
+	}
+	return name
+}
+
 func newValidationError(message string, nodes []ast.Node) *gqlerrors.Error {
 	return gqlerrors.NewError(
 		message,
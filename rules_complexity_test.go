@@ -0,0 +1,130 @@
+package graphql_test
+
+import (
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/location"
+	"github.com/graphql-go/graphql/testutil"
+)
+
+var complexityTestSchema = func() *graphql.Schema {
+	var userType *graphql.Object
+	userType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "User",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"name": &graphql.Field{
+					Type: graphql.String,
+				},
+				"friends": &graphql.Field{
+					Type: graphql.NewList(userType),
+					Args: graphql.FieldConfigArgument{
+						"first": &graphql.ArgumentConfig{
+							Type:         graphql.Int,
+							DefaultValue: 10,
+						},
+					},
+				},
+				"avatar": &graphql.Field{
+					Type: graphql.String,
+				},
+			}
+		}),
+	})
+	schema, _ := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Query",
+			Fields: graphql.Fields{
+				"users": &graphql.Field{
+					Type: graphql.NewList(userType),
+					Args: graphql.FieldConfigArgument{
+						"first": &graphql.ArgumentConfig{
+							Type: graphql.Int,
+						},
+					},
+				},
+			},
+		}),
+	})
+	return &schema
+}()
+
+func TestValidate_Complexity_OperationWithinMaximumComplexity(t *testing.T) {
+	// users: 1 + 5 * (name: 1)
+	testutil.ExpectPassesRuleWithSchema(t, complexityTestSchema, graphql.ComplexityRule(6, nil), `
+      {
+        users(first: 5) {
+          name
+        }
+      }
+    `)
+}
+func TestValidate_Complexity_MultipliesByTheFirstArgument(t *testing.T) {
+	// users: 1 + 5 * (name: 1 + friends: 1 + 10 * (name: 1))
+	testutil.ExpectFailsRuleWithSchema(t, complexityTestSchema, graphql.ComplexityRule(60, nil), `
+      query Users {
+        users(first: 5) {
+          name
+          friends {
+            name
+          }
+        }
+      }
+    `, []gqlerrors.FormattedError{
+		testutil.RuleError(`Operation "Users" has a complexity of 61, which exceeds the maximum complexity of 60.`, 2, 7),
+	})
+}
+func TestValidate_Complexity_UsesDefaultValueOfVariableArguments(t *testing.T) {
+	// users: 1 + 1 * (friends: 1 + 10 * (name: 1))
+	testutil.ExpectFailsRuleWithSchema(t, complexityTestSchema, graphql.ComplexityRule(11, nil), `
+      query Users($first: Int) {
+        users {
+          friends(first: $first) {
+            name
+          }
+        }
+      }
+    `, []gqlerrors.FormattedError{
+		testutil.RuleError(`Operation "Users" has a complexity of 12, which exceeds the maximum complexity of 11.`, 2, 7),
+	})
+}
+func TestValidate_Complexity_UsesValueOfVariableArguments(t *testing.T) {
+	result := graphql.Do(graphql.Params{
+		Schema:          *complexityTestSchema,
+		RequestString:   `query($n: Int) { users(first: $n) { name } }`,
+		VariableValues:  map[string]interface{}{"n": 1000000},
+		ValidationRules: []graphql.ValidationRuleFn{graphql.ComplexityRule(10, nil)},
+	})
+	// users: 1 + 1000000 * (name: 1)
+	expected := []gqlerrors.FormattedError{{
+		Message:    `Operation has a complexity of 1000001, which exceeds the maximum complexity of 10.`,
+		Extensions: map[string]interface{}{"code": gqlerrors.CodeValidationFailed, "rule": "ComplexityRule"},
+		Locations:  []location.SourceLocation{{Line: 1, Column: 1}},
+	}}
+	if result.Data != nil || !testutil.EqualFormattedErrors(expected, result.Errors) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result.Errors))
+	}
+}
+func TestValidate_Complexity_UsesFieldEstimators(t *testing.T) {
+	estimator := graphql.FieldComplexityEstimators(map[string]graphql.ComplexityEstimator{
+		"User.avatar": func(p graphql.ComplexityEstimatorParams) int {
+			return 50
+		},
+	}, nil)
+	// users: 1 + 1 * (name: 1 + avatar: 50), fragments included
+	testutil.ExpectFailsRuleWithSchema(t, complexityTestSchema, graphql.ComplexityRule(50, estimator), `
+      {
+        users {
+          ...UserFields
+        }
+      }
+      fragment UserFields on User {
+        name
+        avatar
+      }
+    `, []gqlerrors.FormattedError{
+		testutil.RuleError(`Operation has a complexity of 52, which exceeds the maximum complexity of 50.`, 2, 7),
+	})
+}
@@ -0,0 +1,57 @@
+package graphql_test
+
+import (
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/testutil"
+)
+
+func TestValidate_MaxAliases_OperationWithinMaximumAliases(t *testing.T) {
+	testutil.ExpectPassesRule(t, graphql.MaxAliasesRule(3), `
+      {
+        first: human {
+          name
+        }
+        second: human {
+          fullName: name
+        }
+      }
+    `)
+}
+func TestValidate_MaxAliases_OperationExceedingMaximumAliases(t *testing.T) {
+	testutil.ExpectFailsRule(t, graphql.MaxAliasesRule(2), `
+      query Aliases {
+        first: dog {
+          name
+        }
+        second: dog {
+          name
+        }
+        third: dog {
+          name
+        }
+      }
+    `, []gqlerrors.FormattedError{
+		testutil.RuleError(`Operation "Aliases" has 3 aliases, which exceeds the maximum of 2 aliases.`, 2, 7),
+	})
+}
+func TestValidate_MaxAliases_CountsAliasesOfEachFragmentSpread(t *testing.T) {
+	testutil.ExpectFailsRule(t, graphql.MaxAliasesRule(3), `
+      {
+        human {
+          ...Names
+          relatives {
+            ...Names
+          }
+        }
+      }
+      fragment Names on Human {
+        first: name
+        second: name
+      }
+    `, []gqlerrors.FormattedError{
+		testutil.RuleError(`Operation has 4 aliases, which exceeds the maximum of 3 aliases.`, 2, 7),
+	})
+}
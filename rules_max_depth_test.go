@@ -0,0 +1,113 @@
+package graphql_test
+
+import (
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/testutil"
+)
+
+func TestValidate_MaxDepth_OperationWithinMaximumDepth(t *testing.T) {
+	testutil.ExpectPassesRule(t, graphql.MaxDepthRule(3), `
+      {
+        human {
+          relatives {
+            name
+          }
+        }
+      }
+    `)
+}
+func TestValidate_MaxDepth_IgnoresIntrospectionFields(t *testing.T) {
+	testutil.ExpectPassesRule(t, graphql.MaxDepthRule(1), `
+      {
+        __schema {
+          types {
+            fields {
+              name
+            }
+          }
+        }
+        human {
+          __typename
+        }
+      }
+    `)
+}
+func TestValidate_MaxDepth_OperationExceedingMaximumDepth(t *testing.T) {
+	testutil.ExpectFailsRule(t, graphql.MaxDepthRule(3), `
+      query Deep {
+        human {
+          relatives {
+            relatives {
+              name
+            }
+          }
+        }
+      }
+    `, []gqlerrors.FormattedError{
+		testutil.RuleError(`Operation "Deep" has a depth of 4, which exceeds the maximum depth of 3.`, 2, 7),
+	})
+}
+func TestValidate_MaxDepth_CountsFieldsOfFragments(t *testing.T) {
+	testutil.ExpectFailsRule(t, graphql.MaxDepthRule(3), `
+      {
+        human {
+          ...Relatives
+        }
+        pet {
+          ... on Dog {
+            name
+          }
+        }
+      }
+      fragment Relatives on Human {
+        relatives {
+          ... on Human {
+            relatives {
+              name
+            }
+          }
+        }
+      }
+    `, []gqlerrors.FormattedError{
+		testutil.RuleError(`Operation has a depth of 4, which exceeds the maximum depth of 3.`, 2, 7),
+	})
+}
+func TestValidate_MaxDepth_ReportsEachOperation(t *testing.T) {
+	testutil.ExpectFailsRule(t, graphql.MaxDepthRule(1), `
+      query Shallow {
+        dog {
+          name
+        }
+      }
+      query Valid {
+        alien
+      }
+      query Deep {
+        human {
+          relatives {
+            name
+          }
+        }
+      }
+    `, []gqlerrors.FormattedError{
+		testutil.RuleError(`Operation "Shallow" has a depth of 2, which exceeds the maximum depth of 1.`, 2, 7),
+		testutil.RuleError(`Operation "Deep" has a depth of 3, which exceeds the maximum depth of 1.`, 10, 7),
+	})
+}
+func TestValidate_MaxDepth_DoesNotLoopOnFragmentCycles(t *testing.T) {
+	testutil.ExpectPassesRule(t, graphql.MaxDepthRule(3), `
+      {
+        human {
+          ...Relatives
+        }
+      }
+      fragment Relatives on Human {
+        relatives {
+          ...Relatives
+        }
+      }
+    `)
+}
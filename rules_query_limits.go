@@ -0,0 +1,306 @@
+package graphql
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/kinds"
+	"github.com/graphql-go/graphql/language/visitor"
+)
+
+// maxInt is the largest value of an int, at which measures saturate.
+const maxInt = int(^uint(0) >> 1)
+
+// MaxDepthRule Operations are not nested deeper than maxDepth
+//
+// A GraphQL document is only valid if the fields of each operation, fragments
+// included, are nested at most maxDepth levels deep. Introspection fields
+// such as __schema are not counted, so that introspection queries pass.
+func MaxDepthRule(maxDepth int) ValidationRuleFn {
+	return func(context *ValidationContext) *ValidationRuleInstance {
+		return newOperationLimitRuleInstance(context, func(operation *ast.OperationDefinition) {
+			measure := newOperationMeasure(context, operation, func(a, b int) int {
+				if a > b {
+					return a
+				}
+				return b
+			}, func(field *ast.Field, fieldDef *FieldDefinition, parentType Type, childDepth int) int {
+				return saturatingAdd(1, childDepth)
+			})
+			if depth := measure.operation(operation); depth > maxDepth {
+				reportError(
+					context,
+					fmt.Sprintf(`%v has a depth of %v, which exceeds the maximum depth of %v.`,
+						operationDescription(operation), depth, maxDepth),
+					[]ast.Node{operation},
+				)
+			}
+		})
+	}
+}
+
+// MaxAliasesRule Operations do not use more than maxAliases aliases
+//
+// A GraphQL document is only valid if each operation, fragments included,
+// has at most maxAliases aliased fields. An alias in a fragment counts once
+// for each time the fragment is spread.
+func MaxAliasesRule(maxAliases int) ValidationRuleFn {
+	return func(context *ValidationContext) *ValidationRuleInstance {
+		return newOperationLimitRuleInstance(context, func(operation *ast.OperationDefinition) {
+			measure := newOperationMeasure(context, operation, saturatingAdd, func(field *ast.Field, fieldDef *FieldDefinition, parentType Type, childAliases int) int {
+				if field.Alias != nil {
+					return saturatingAdd(1, childAliases)
+				}
+				return childAliases
+			})
+			if aliases := measure.operation(operation); aliases > maxAliases {
+				reportError(
+					context,
+					fmt.Sprintf(`%v has %v aliases, which exceeds the maximum of %v aliases.`,
+						operationDescription(operation), aliases, maxAliases),
+					[]ast.Node{operation},
+				)
+			}
+		})
+	}
+}
+
+// ComplexityEstimatorParams holds what a ComplexityEstimator knows about the
+// field whose complexity it estimates.
+type ComplexityEstimatorParams struct {
+	// FieldDef is the definition of the field.
+	FieldDef *FieldDefinition
+
+	// ParentType is the type on which the field is selected.
+	ParentType Type
+
+	// FieldAST is the selection of the field in the document.
+	FieldAST *ast.Field
+
+	// Args are the argument values of the field. Arguments given by a
+	// variable take the value of the variable in the request, or its default
+	// value when the variables are not known, as in ValidateDocument.
+	Args map[string]interface{}
+
+	// ChildComplexity is the complexity of the selection set of the field.
+	ChildComplexity int
+}
+
+// ComplexityEstimator estimates the complexity of a field.
+type ComplexityEstimator func(p ComplexityEstimatorParams) int
+
+// DefaultComplexityEstimator estimates the complexity of a field to 1 plus
+// the complexity of its selection set, multiplied by the value of the first,
+// last or limit argument of the field when there is one.
+func DefaultComplexityEstimator(p ComplexityEstimatorParams) int {
+	multiplier := 1
+	for _, name := range []string{"first", "last", "limit"} {
+		if n, ok := p.Args[name].(int); ok && n > 0 {
+			multiplier = n
+			break
+		}
+	}
+	return saturatingAdd(1, saturatingMul(multiplier, p.ChildComplexity))
+}
+
+// FieldComplexityEstimators returns a ComplexityEstimator using the estimator
+// keyed by "Type.field" in estimators for the fields it contains, and fallback
+// for the other fields. A nil fallback defaults to DefaultComplexityEstimator.
+func FieldComplexityEstimators(estimators map[string]ComplexityEstimator, fallback ComplexityEstimator) ComplexityEstimator {
+	if fallback == nil {
+		fallback = DefaultComplexityEstimator
+	}
+	return func(p ComplexityEstimatorParams) int {
+		if p.ParentType != nil && p.FieldDef != nil {
+			if estimator, ok := estimators[p.ParentType.Name()+"."+p.FieldDef.Name]; ok {
+				return estimator(p)
+			}
+		}
+		return fallback(p)
+	}
+}
+
+// ComplexityRule Operations are not more complex than maxComplexity
+//
+// A GraphQL document is only valid if the complexity of each operation,
+// fragments included, is at most maxComplexity. The complexity of an
+// operation is the sum of the complexities of its fields, as estimated by
+// the given estimator, which defaults to DefaultComplexityEstimator.
+func ComplexityRule(maxComplexity int, estimator ComplexityEstimator) ValidationRuleFn {
+	if estimator == nil {
+		estimator = DefaultComplexityEstimator
+	}
+	return func(context *ValidationContext) *ValidationRuleInstance {
+		return newOperationLimitRuleInstance(context, func(operation *ast.OperationDefinition) {
+			// operations with invalid variable values are not executed, the
+			// variables then take their default values
+			variables, err := getVariableValues(*context.Schema(), operation.VariableDefinitions, context.VariableValues())
+			if err != nil {
+				variables = nil
+			}
+			measure := newOperationMeasure(context, operation, saturatingAdd, func(field *ast.Field, fieldDef *FieldDefinition, parentType Type, childComplexity int) int {
+				return estimator(ComplexityEstimatorParams{
+					FieldDef:        fieldDef,
+					ParentType:      parentType,
+					FieldAST:        field,
+					Args:            getArgumentValues(fieldDef.Args, field.Arguments, variables),
+					ChildComplexity: childComplexity,
+				})
+			})
+			if complexity := measure.operation(operation); complexity > maxComplexity {
+				reportError(
+					context,
+					fmt.Sprintf(`%v has a complexity of %v, which exceeds the maximum complexity of %v.`,
+						operationDescription(operation), complexity, maxComplexity),
+					[]ast.Node{operation},
+				)
+			}
+		})
+	}
+}
+
+// newOperationLimitRuleInstance returns a rule instance calling check with
+// each operation of the document.
+func newOperationLimitRuleInstance(context *ValidationContext, check func(operation *ast.OperationDefinition)) *ValidationRuleInstance {
+	visitorOpts := &visitor.VisitorOptions{
+		KindFuncMap: map[string]visitor.NamedVisitFuncs{
+			kinds.OperationDefinition: {
+				Kind: func(p visitor.VisitFuncParams) (string, interface{}) {
+					if operation, ok := p.Node.(*ast.OperationDefinition); ok && operation != nil {
+						check(operation)
+					}
+					return visitor.ActionSkip, nil
+				},
+			},
+		},
+	}
+	return &ValidationRuleInstance{
+		VisitorOpts: visitorOpts,
+	}
+}
+
+func operationDescription(operation *ast.OperationDefinition) string {
+	if operation.Name != nil && operation.Name.Value != "" {
+		return fmt.Sprintf(`Operation "%v"`, operation.Name.Value)
+	}
+	return "Operation"
+}
+
+// operationMeasure measures an operation, such as its depth, by combining the
+// measures of its fields. Fragments are expanded, and measured once.
+type operationMeasure struct {
+	context        *ValidationContext
+	fragments      map[string]*ast.FragmentDefinition
+	fragmentValues map[string]int
+	visiting       map[string]bool
+	// combine combines the measures of sibling selections
+	combine func(a, b int) int
+	// field returns the measure of a field, given the measure of its
+	// selection set
+	field func(field *ast.Field, fieldDef *FieldDefinition, parentType Type, childValue int) int
+}
+
+func newOperationMeasure(
+	context *ValidationContext,
+	operation *ast.OperationDefinition,
+	combine func(a, b int) int,
+	field func(field *ast.Field, fieldDef *FieldDefinition, parentType Type, childValue int) int,
+) *operationMeasure {
+	fragments := map[string]*ast.FragmentDefinition{}
+	for _, fragment := range context.RecursivelyReferencedFragments(operation) {
+		if fragment.Name != nil {
+			fragments[fragment.Name.Value] = fragment
+		}
+	}
+	return &operationMeasure{
+		context:        context,
+		fragments:      fragments,
+		fragmentValues: map[string]int{},
+		visiting:       map[string]bool{},
+		combine:        combine,
+		field:          field,
+	}
+}
+
+func (m *operationMeasure) operation(operation *ast.OperationDefinition) int {
+	schema := m.context.Schema()
+	var rootType Type
+	switch operation.Operation {
+	case ast.OperationTypeQuery:
+		rootType = schema.QueryType()
+	case ast.OperationTypeMutation:
+		rootType = schema.MutationType()
+	case ast.OperationTypeSubscription:
+		rootType = schema.SubscriptionType()
+	}
+	return m.selectionSet(operation.SelectionSet, rootType)
+}
+
+func (m *operationMeasure) selectionSet(selectionSet *ast.SelectionSet, parentType Type) int {
+	value := 0
+	if selectionSet == nil || parentType == nil {
+		return value
+	}
+	schema := m.context.Schema()
+	for _, selection := range selectionSet.Selections {
+		switch selection := selection.(type) {
+		case *ast.Field:
+			if selection.Name == nil || strings.HasPrefix(selection.Name.Value, "__") {
+				continue
+			}
+			fieldDef := DefaultTypeInfoFieldDef(schema, parentType, selection)
+			if fieldDef == nil {
+				continue
+			}
+			fieldType, _ := GetNamed(fieldDef.Type).(Type)
+			childValue := m.selectionSet(selection.SelectionSet, fieldType)
+			value = m.combine(value, m.field(selection, fieldDef, parentType, childValue))
+		case *ast.InlineFragment:
+			fragmentType := parentType
+			if selection.TypeCondition != nil {
+				fragmentType, _ = typeFromAST(*schema, selection.TypeCondition)
+			}
+			value = m.combine(value, m.selectionSet(selection.SelectionSet, fragmentType))
+		case *ast.FragmentSpread:
+			if selection.Name != nil {
+				value = m.combine(value, m.fragment(selection.Name.Value))
+			}
+		}
+	}
+	return value
+}
+
+func (m *operationMeasure) fragment(name string) int {
+	if value, ok := m.fragmentValues[name]; ok {
+		return value
+	}
+	fragment, ok := m.fragments[name]
+	// cycles are reported by NoFragmentCyclesRule
+	if !ok || m.visiting[name] {
+		return 0
+	}
+	m.visiting[name] = true
+	var fragmentType Type
+	if fragment.TypeCondition != nil {
+		fragmentType, _ = typeFromAST(*m.context.Schema(), fragment.TypeCondition)
+	}
+	value := m.selectionSet(fragment.SelectionSet, fragmentType)
+	delete(m.visiting, name)
+	m.fragmentValues[name] = value
+	return value
+}
+
+func saturatingAdd(a, b int) int {
+	if b > 0 && a > maxInt-b {
+		return maxInt
+	}
+	return a + b
+}
+
+func saturatingMul(a, b int) int {
+	if a > 0 && b > maxInt/a {
+		return maxInt
+	}
+	return a * b
+}
@@ -0,0 +1,143 @@
+package graphql
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+)
+
+// ErrOperationNotSafelisted is reported when a request is not one of the
+// documents of the OperationManifest of Params.
+var ErrOperationNotSafelisted = &PersistedQueryError{
+	Message: "Operation is not in the safelist of the server.",
+	Code:    "OPERATION_NOT_SAFELISTED",
+}
+
+// OperationManifest is a safelist of documents, keyed by an id. Only the
+// documents of the manifest are executed by Do when it is set in Params.
+type OperationManifest struct {
+	documents map[string]string
+	// ids holds the id of each document, keyed by its text
+	ids map[string]string
+}
+
+// NewOperationManifest returns a manifest of the given documents, keyed by
+// their id, after validating each of them against the schema.
+func NewOperationManifest(schema *Schema, documents map[string]string) (*OperationManifest, error) {
+	manifest := &OperationManifest{
+		documents: make(map[string]string, len(documents)),
+		ids:       make(map[string]string, len(documents)),
+	}
+	ids := make([]string, 0, len(documents))
+	for id := range documents {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	var messages []string
+	for _, id := range ids {
+		document := documents[id]
+		if errs := validateManifestDocument(schema, id, document); len(errs) != 0 {
+			for _, err := range errs {
+				messages = append(messages, fmt.Sprintf(`operation "%v": %v`, id, err.Message))
+			}
+			continue
+		}
+		manifest.documents[id] = document
+		manifest.ids[document] = id
+	}
+	if len(messages) != 0 {
+		return nil, fmt.Errorf("invalid operation manifest:\n%v", strings.Join(messages, "\n"))
+	}
+	return manifest, nil
+}
+
+// LoadOperationManifestJSON returns the manifest of the documents of a JSON
+// object mapping ids to documents.
+func LoadOperationManifestJSON(schema *Schema, data []byte) (*OperationManifest, error) {
+	var documents map[string]string
+	if err := json.Unmarshal(data, &documents); err != nil {
+		return nil, fmt.Errorf("invalid operation manifest: %v", err)
+	}
+	return NewOperationManifest(schema, documents)
+}
+
+// LoadOperationManifestDir returns the manifest of the .graphql files of a
+// directory and of its subdirectories. The id of a document is the path of
+// its file relative to the directory, without the extension, such as
+// "users/profile" for users/profile.graphql.
+func LoadOperationManifestDir(schema *Schema, dir string) (*OperationManifest, error) {
+	documents := map[string]string{}
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || filepath.Ext(path) != ".graphql" {
+			return nil
+		}
+		body, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		documents[filepath.ToSlash(strings.TrimSuffix(rel, ".graphql"))] = string(body)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return NewOperationManifest(schema, documents)
+}
+
+// Lookup returns the document with the given id.
+func (m *OperationManifest) Lookup(id string) (string, bool) {
+	document, ok := m.documents[id]
+	return document, ok
+}
+
+// ID returns the id of the given document, which must be identical to the
+// document of the manifest.
+func (m *OperationManifest) ID(document string) (string, bool) {
+	id, ok := m.ids[document]
+	return id, ok
+}
+
+// Len returns the number of documents of the manifest.
+func (m *OperationManifest) Len() int {
+	return len(m.documents)
+}
+
+func validateManifestDocument(schema *Schema, id string, document string) []gqlerrors.FormattedError {
+	AST, err := parser.Parse(parser.ParseParams{
+		Source: source.NewSource(&source.Source{
+			Body: []byte(document),
+			Name: id,
+		}),
+	})
+	if err != nil {
+		return gqlerrors.FormatErrors(err)
+	}
+	return ValidateDocument(schema, AST, SpecifiedRules).Errors
+}
+
+// checkSafelist rejects the request of p unless it is in its manifest.
+func checkSafelist(p *Params) []gqlerrors.FormattedError {
+	if p.OperationManifest == nil {
+		return nil
+	}
+	if _, ok := p.OperationManifest.ID(p.RequestString); !ok {
+		return gqlerrors.FormatErrors(ErrOperationNotSafelisted)
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package graphql_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/testutil"
+)
+
+func TestDoRejectsOperationsNotInTheManifest(t *testing.T) {
+	manifest, err := graphql.LoadOperationManifestJSON(&testutil.StarWarsSchema, []byte(`{
+		"HeroName": "query HeroName { hero { name } }"
+	}`))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	result := graphql.Do(graphql.Params{
+		Schema:            testutil.StarWarsSchema,
+		RequestString:     "query HeroName { hero { name } }",
+		OperationManifest: manifest,
+	})
+	expected := &graphql.Result{
+		Data: map[string]interface{}{
+			"hero": map[string]interface{}{
+				"name": "R2-D2",
+			},
+		},
+	}
+	if !reflect.DeepEqual(expected, result) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
+	}
+
+	result = graphql.Do(graphql.Params{
+		Schema:            testutil.StarWarsSchema,
+		RequestString:     "query HeroID { hero { id } }",
+		OperationManifest: manifest,
+	})
+	expectedErrors := gqlerrors.FormatErrors(graphql.ErrOperationNotSafelisted)
+	if !reflect.DeepEqual(expectedErrors, result.Errors) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expectedErrors, result.Errors))
+	}
+	if code := result.Errors[0].Extensions["code"]; code != "OPERATION_NOT_SAFELISTED" {
+		t.Fatalf("Unexpected error code: %v", code)
+	}
+}
+
+func TestLoadOperationManifestDir(t *testing.T) {
+	dir, err := ioutil.TempDir("", "manifest")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		"hero.graphql":         "query Hero { hero { name } }",
+		"humans/luke.graphql":  `query Luke { human(id: "1000") { name } }`,
+		"humans/README.md":     "not an operation",
+		"droids/droid.graphql": `query Droid { droid(id: "2001") { name } }`,
+	}
+	for name, body := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if err := ioutil.WriteFile(path, []byte(body), 0644); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	manifest, err := graphql.LoadOperationManifestDir(&testutil.StarWarsSchema, dir)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if manifest.Len() != 3 {
+		t.Fatalf("Expected 3 operations, got %v", manifest.Len())
+	}
+	if document, ok := manifest.Lookup("humans/luke"); !ok || document != files["humans/luke.graphql"] {
+		t.Fatalf("Unexpected document: %v", document)
+	}
+	if id, ok := manifest.ID(files["droids/droid.graphql"]); !ok || id != "droids/droid" {
+		t.Fatalf("Unexpected id: %v", id)
+	}
+}
+
+func TestLoadOperationManifestValidatesOperations(t *testing.T) {
+	_, err := graphql.LoadOperationManifestJSON(&testutil.StarWarsSchema, []byte(`{
+		"Valid": "{ hero { name } }",
+		"Unknown": "{ hero { age } }",
+		"Syntax": "{ hero {"
+	}`))
+	if err == nil {
+		t.Fatalf("Expected the invalid operations to be reported")
+	}
+	for _, expected := range []string{
+		`operation "Unknown": Cannot query field "age" on type "Character".`,
+		`operation "Syntax": Syntax Error Syntax (1:9) Expected Name, found EOF`,
+	} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Fatalf("Expected %q in the error, got: %v", expected, err)
+		}
+	}
+	if strings.Contains(err.Error(), `"Valid"`) {
+		t.Fatalf("Unexpected error for a valid operation: %v", err)
+	}
+}
@@ -7,6 +7,10 @@ type SchemaConfig struct {
 	Types        []Type
 	Directives   []*Directive
 	Extensions   []Extension
+
+	// FieldMiddleware wraps the resolvers of all the fields of the schema,
+	// the first middleware being the outermost.
+	FieldMiddleware []FieldMiddleware
 }
 
 type TypeMap map[string]Type
@@ -41,12 +45,16 @@ type Schema struct {
 	implementations  map[string][]*Object
 	possibleTypeMap  map[string]map[string]bool
 	extensions       []Extension
+	fieldMiddleware  []FieldMiddleware
+
+	// id identifies the schema in the keys of the DocumentCache
+	id uint64
 }
 
 func NewSchema(config SchemaConfig) (Schema, error) {
 	var err error
 
-	schema := Schema{}
+	schema := Schema{id: nextSchemaID()}
 
 	if err = invariant(config.Query != nil, "Schema query must be Object Type but got: nil."); err != nil {
 		return schema, err
@@ -127,9 +135,9 @@ func NewSchema(config SchemaConfig) (Schema, error) {
 
 	// Enforce correct interface implementations
 	for _, ttype := range schema.typeMap {
-		if ttype, ok := ttype.(*Object); ok {
+		if ttype, ok := ttype.(implementer); ok {
 			for _, iface := range ttype.Interfaces() {
-				err := assertObjectImplementsInterface(&schema, ttype, iface)
+				err := assertImplementsInterface(&schema, ttype, iface)
 				if err != nil {
 					return schema, err
 				}
@@ -142,6 +150,9 @@ func NewSchema(config SchemaConfig) (Schema, error) {
 		schema.extensions = config.Extensions
 	}
 
+	// Add field middleware from config
+	schema.fieldMiddleware = config.FieldMiddleware
+
 	return schema, nil
 }
 
@@ -168,9 +179,9 @@ func (gq *Schema) AddImplementation() error {
 
 	// Enforce correct interface implementations
 	for _, ttype := range gq.typeMap {
-		if ttype, ok := ttype.(*Object); ok {
+		if ttype, ok := ttype.(implementer); ok {
 			for _, iface := range ttype.Interfaces() {
-				err := assertObjectImplementsInterface(gq, ttype, iface)
+				err := assertImplementsInterface(gq, ttype, iface)
 				if err != nil {
 					return err
 				}
@@ -192,6 +203,8 @@ func (gq *Schema) AppendType(objectType Type) error {
 	if err != nil {
 		return err
 	}
+	// documents validated against the previous types must be validated again
+	gq.id = nextSchemaID()
 	//Now Add interface implementation..
 	return gq.AddImplementation()
 }
@@ -267,6 +280,12 @@ func (gq *Schema) AddExtensions(e ...Extension) {
 	gq.extensions = append(gq.extensions, e...)
 }
 
+// AddFieldMiddleware wraps the resolvers of the schema with additional
+// middleware, inside the middleware added before.
+func (gq *Schema) AddFieldMiddleware(m ...FieldMiddleware) {
+	gq.fieldMiddleware = append(gq.fieldMiddleware, m...)
+}
+
 // map-reduce
 func typeMapReducer(schema *Schema, typeMap TypeMap, objectType Type) (TypeMap, error) {
 	var err error
@@ -327,6 +346,20 @@ func typeMapReducer(schema *Schema, typeMap TypeMap, objectType Type) (TypeMap,
 			}
 		}
 	}
+	if objectType, ok := objectType.(*Interface); ok {
+		interfaces := objectType.Interfaces()
+		if objectType.err != nil {
+			return typeMap, objectType.err
+		}
+		for _, innerObjectType := range interfaces {
+			if innerObjectType.err != nil {
+				return typeMap, innerObjectType.err
+			}
+			if typeMap, err = typeMapReducer(schema, typeMap, innerObjectType); err != nil {
+				return typeMap, err
+			}
+		}
+	}
 
 	switch objectType := objectType.(type) {
 	case *Object:
@@ -378,7 +411,54 @@ func typeMapReducer(schema *Schema, typeMap TypeMap, objectType Type) (TypeMap,
 	return typeMap, nil
 }
 
-func assertObjectImplementsInterface(schema *Schema, object *Object, iface *Interface) error {
+// implementer is an Object or Interface type, both of which may implement
+// interfaces.
+type implementer interface {
+	Named
+	Name() string
+	Fields() FieldDefinitionMap
+	Interfaces() []*Interface
+}
+
+var _ implementer = (*Object)(nil)
+var _ implementer = (*Interface)(nil)
+
+func assertImplementsInterface(schema *Schema, object implementer, iface *Interface) error {
+	// Assert the type does not implement itself, directly or through a cycle.
+	err := invariantf(
+		object != implementer(iface),
+		`Type %v cannot implement itself because it would create a circular reference.`, object,
+	)
+	if err != nil {
+		return err
+	}
+
+	// Assert every interface implemented by the interface is also implemented
+	// by the type. (transitive)
+	for _, transitive := range iface.Interfaces() {
+		implemented := false
+		for _, other := range object.Interfaces() {
+			if other == transitive {
+				implemented = true
+				break
+			}
+		}
+		if object == implementer(transitive) {
+			err = invariantf(
+				false,
+				`Type %v cannot implement %v because it would create a circular reference.`, object, iface,
+			)
+		} else {
+			err = invariantf(
+				implemented,
+				`Type %v must implement %v because it is implemented by %v.`, object, transitive, iface,
+			)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
 	objectFieldMap := object.Fields()
 	ifaceFieldMap := iface.Fields()
 
@@ -388,7 +468,7 @@ func assertObjectImplementsInterface(schema *Schema, object *Object, iface *Inte
 		ifaceField := ifaceFieldMap[fieldName]
 
 		// Assert interface field exists on object.
-		err := invariantf(
+		err = invariantf(
 			objectField != nil,
 			`"%v" expects field "%v" but "%v" does not `+
 				`provide it.`, iface, fieldName, object)
@@ -533,6 +613,13 @@ func isTypeSubTypeOf(schema *Schema, maybeSubType Type, superType Type) bool {
 		if maybeSubType, ok := maybeSubType.(*Object); ok && schema.IsPossibleType(superType, maybeSubType) {
 			return true
 		}
+		if maybeSubType, ok := maybeSubType.(*Interface); ok {
+			for _, iface := range maybeSubType.Interfaces() {
+				if iface == superType {
+					return true
+				}
+			}
+		}
 	}
 	if superType, ok := superType.(*Union); ok {
 		if maybeSubType, ok := maybeSubType.(*Object); ok && schema.IsPossibleType(superType, maybeSubType) {
@@ -0,0 +1,368 @@
+package graphql
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/printer"
+)
+
+// PrintSchema returns the SDL representation of the given schema.
+//
+// Directives and types are printed in alphabetical order, as are the fields,
+// arguments and enum values they contain, so that the output is stable across
+// runs. Specified directives, specified scalars and introspection types are
+// omitted.
+func PrintSchema(schema Schema) string {
+	return printDocument(astFromSchema(schema))
+}
+
+// PrintType returns the SDL representation of a single named type.
+func PrintType(ttype Type) string {
+	node := astFromType(ttype)
+	if node == nil {
+		return ""
+	}
+	return printNode(node)
+}
+
+func printDocument(doc *ast.Document) string {
+	if len(doc.Definitions) == 0 {
+		return ""
+	}
+	return printNode(doc)
+}
+
+// printNode prints node, leaving blank the blank lines which the printer
+// indents, such as the ones separating the described fields.
+func printNode(node ast.Node) string {
+	lines := strings.Split(fmt.Sprintf("%v", printer.Print(node)), "\n")
+	for i, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			lines[i] = ""
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// astFromSchema builds a schema document from the given schema, containing
+// a schema definition (only if the root types do not use the conventional
+// names), followed by the custom directive definitions and type definitions.
+func astFromSchema(schema Schema) *ast.Document {
+	definitions := []ast.Node{}
+
+	if schemaDef := astSchemaDefinition(schema); schemaDef != nil {
+		definitions = append(definitions, schemaDef)
+	}
+
+	directives := []*Directive{}
+	for _, directive := range schema.Directives() {
+		if isSpecifiedDirective(directive) {
+			continue
+		}
+		directives = append(directives, directive)
+	}
+	sort.Slice(directives, func(i, j int) bool {
+		return directives[i].Name < directives[j].Name
+	})
+	for _, directive := range directives {
+		definitions = append(definitions, astFromDirective(directive))
+	}
+
+	typeNames := []string{}
+	for name, ttype := range schema.TypeMap() {
+		if isIntrospectionType(ttype) || isSpecifiedScalarType(ttype) {
+			continue
+		}
+		typeNames = append(typeNames, name)
+	}
+	sort.Strings(typeNames)
+	for _, name := range typeNames {
+		if node := astFromType(schema.Type(name)); node != nil {
+			definitions = append(definitions, node)
+		}
+	}
+
+	return ast.NewDocument(&ast.Document{
+		Definitions: definitions,
+	})
+}
+
+func astSchemaDefinition(schema Schema) *ast.SchemaDefinition {
+	if isSchemaOfCommonNames(schema) {
+		return nil
+	}
+
+	operationTypes := []*ast.OperationTypeDefinition{}
+	addOperationType := func(operation string, ttype *Object) {
+		if ttype == nil {
+			return
+		}
+		operationTypes = append(operationTypes, ast.NewOperationTypeDefinition(&ast.OperationTypeDefinition{
+			Operation: operation,
+			Type:      astNamed(ttype.Name()),
+		}))
+	}
+	addOperationType(ast.OperationTypeQuery, schema.QueryType())
+	addOperationType(ast.OperationTypeMutation, schema.MutationType())
+	addOperationType(ast.OperationTypeSubscription, schema.SubscriptionType())
+
+	return ast.NewSchemaDefinition(&ast.SchemaDefinition{
+		OperationTypes: operationTypes,
+	})
+}
+
+// isSchemaOfCommonNames reports whether the root operation types use the
+// names "Query", "Mutation" and "Subscription", in which case the schema
+// definition may be omitted from the printed output.
+func isSchemaOfCommonNames(schema Schema) bool {
+	if queryType := schema.QueryType(); queryType != nil && queryType.Name() != "Query" {
+		return false
+	}
+	if mutationType := schema.MutationType(); mutationType != nil && mutationType.Name() != "Mutation" {
+		return false
+	}
+	if subscriptionType := schema.SubscriptionType(); subscriptionType != nil && subscriptionType.Name() != "Subscription" {
+		return false
+	}
+	return true
+}
+
+func isSpecifiedDirective(directive *Directive) bool {
+	for _, specifiedDirective := range specifiedDirectivesWithSpecifiedBy() {
+		if directive.Name == specifiedDirective.Name {
+			return true
+		}
+	}
+	return false
+}
+
+func isIntrospectionType(ttype Type) bool {
+	for _, introspectionType := range GetIntrospectionTypes() {
+		if ttype.Name() == introspectionType.Name() {
+			return true
+		}
+	}
+	return false
+}
+
+func isSpecifiedScalarType(ttype Type) bool {
+	for _, scalarType := range getSpecifiedScalarTypes() {
+		if ttype.Name() == scalarType.Name() {
+			return true
+		}
+	}
+	return false
+}
+
+// astFromType returns the type definition node for the given named type, or
+// nil if the type is not a named type.
+func astFromType(ttype Type) ast.Node {
+	switch ttype := ttype.(type) {
+	case *Scalar:
+		directives := []*ast.Directive{}
+		if url := ttype.SpecifiedByURL(); url != "" {
+			directives = append(directives, astDirective(SpecifiedByDirective.Name, map[string]ast.Value{
+				"url": ast.NewStringValue(&ast.StringValue{Value: url}),
+			}))
+		}
+		return ast.NewScalarDefinition(&ast.ScalarDefinition{
+			Name:        astName(ttype.Name()),
+			Description: astDescription(ttype.Description()),
+			Directives:  directives,
+		})
+	case *Object:
+		interfaces := []*ast.Named{}
+		for _, iface := range ttype.Interfaces() {
+			interfaces = append(interfaces, astNamed(iface.Name()))
+		}
+		return ast.NewObjectDefinition(&ast.ObjectDefinition{
+			Name:        astName(ttype.Name()),
+			Description: astDescription(ttype.Description()),
+			Interfaces:  interfaces,
+			Fields:      astFromFieldDefinitionMap(ttype.Fields()),
+		})
+	case *Interface:
+		interfaces := []*ast.Named{}
+		for _, iface := range ttype.Interfaces() {
+			interfaces = append(interfaces, astNamed(iface.Name()))
+		}
+		return ast.NewInterfaceDefinition(&ast.InterfaceDefinition{
+			Name:        astName(ttype.Name()),
+			Description: astDescription(ttype.Description()),
+			Interfaces:  interfaces,
+			Fields:      astFromFieldDefinitionMap(ttype.Fields()),
+		})
+	case *Union:
+		types := []*ast.Named{}
+		for _, possibleType := range ttype.Types() {
+			types = append(types, astNamed(possibleType.Name()))
+		}
+		return ast.NewUnionDefinition(&ast.UnionDefinition{
+			Name:        astName(ttype.Name()),
+			Description: astDescription(ttype.Description()),
+			Types:       types,
+		})
+	case *Enum:
+		values := append([]*EnumValueDefinition{}, ttype.Values()...)
+		sort.Slice(values, func(i, j int) bool {
+			return values[i].Name < values[j].Name
+		})
+		valueDefs := []*ast.EnumValueDefinition{}
+		for _, value := range values {
+			valueDefs = append(valueDefs, ast.NewEnumValueDefinition(&ast.EnumValueDefinition{
+				Name:        astName(value.Name),
+				Description: astDescription(value.Description),
+				Directives:  astDeprecated(value.DeprecationReason),
+			}))
+		}
+		return ast.NewEnumDefinition(&ast.EnumDefinition{
+			Name:        astName(ttype.Name()),
+			Description: astDescription(ttype.Description()),
+			Values:      valueDefs,
+		})
+	case *InputObject:
+		fieldMap := ttype.Fields()
+		fieldNames := []string{}
+		for name := range fieldMap {
+			fieldNames = append(fieldNames, name)
+		}
+		sort.Strings(fieldNames)
+		fields := []*ast.InputValueDefinition{}
+		for _, name := range fieldNames {
+			field := fieldMap[name]
+			fields = append(fields, astInputValueDefinition(name, field.Description(), field.Type, field.DefaultValue))
+		}
+		return ast.NewInputObjectDefinition(&ast.InputObjectDefinition{
+			Name:        astName(ttype.Name()),
+			Description: astDescription(ttype.Description()),
+			Fields:      fields,
+		})
+	}
+	return nil
+}
+
+func astFromFieldDefinitionMap(fieldMap FieldDefinitionMap) []*ast.FieldDefinition {
+	fieldNames := []string{}
+	for name := range fieldMap {
+		fieldNames = append(fieldNames, name)
+	}
+	sort.Strings(fieldNames)
+
+	fields := []*ast.FieldDefinition{}
+	for _, name := range fieldNames {
+		field := fieldMap[name]
+		fields = append(fields, ast.NewFieldDefinition(&ast.FieldDefinition{
+			Name:        astName(name),
+			Description: astDescription(field.Description),
+			Arguments:   astFromArguments(field.Args),
+			Type:        astFromTypeReference(field.Type),
+			Directives:  astDeprecated(field.DeprecationReason),
+		}))
+	}
+	return fields
+}
+
+func astFromArguments(args []*Argument) []*ast.InputValueDefinition {
+	sorted := append([]*Argument{}, args...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Name() < sorted[j].Name()
+	})
+	inputValues := []*ast.InputValueDefinition{}
+	for _, arg := range sorted {
+		inputValues = append(inputValues, astInputValueDefinition(arg.Name(), arg.Description(), arg.Type, arg.DefaultValue))
+	}
+	return inputValues
+}
+
+func astFromDirective(directive *Directive) *ast.DirectiveDefinition {
+	locations := []*ast.Name{}
+	for _, location := range directive.Locations {
+		locations = append(locations, astName(location))
+	}
+	return ast.NewDirectiveDefinition(&ast.DirectiveDefinition{
+		Name:        astName(directive.Name),
+		Description: astDescription(directive.Description),
+		Arguments:   astFromArguments(directive.Args),
+		Locations:   locations,
+	})
+}
+
+func astInputValueDefinition(name string, description string, ttype Input, defaultValue interface{}) *ast.InputValueDefinition {
+	def := &ast.InputValueDefinition{
+		Name:        astName(name),
+		Description: astDescription(description),
+		Type:        astFromTypeReference(ttype),
+	}
+	if defaultValue != nil {
+		if value := astFromValue(defaultValue, ttype); value != nil {
+			def.DefaultValue = value
+		}
+	}
+	return ast.NewInputValueDefinition(def)
+}
+
+// astFromTypeReference converts a (possibly wrapped) type into its AST
+// representation, e.g. `[String!]`.
+func astFromTypeReference(ttype Type) ast.Type {
+	switch ttype := ttype.(type) {
+	case *NonNull:
+		return ast.NewNonNull(&ast.NonNull{
+			Type: astFromTypeReference(ttype.OfType),
+		})
+	case *List:
+		return ast.NewList(&ast.List{
+			Type: astFromTypeReference(ttype.OfType),
+		})
+	}
+	return astNamed(ttype.Name())
+}
+
+// astDeprecated returns the @deprecated directive for the given deprecation
+// reason. The reason argument is omitted if it is the default reason.
+func astDeprecated(reason string) []*ast.Directive {
+	if reason == "" {
+		return nil
+	}
+	args := map[string]ast.Value{}
+	if reason != DefaultDeprecationReason {
+		args["reason"] = ast.NewStringValue(&ast.StringValue{Value: reason})
+	}
+	return []*ast.Directive{astDirective(DeprecatedDirective.Name, args)}
+}
+
+func astDirective(name string, args map[string]ast.Value) *ast.Directive {
+	argNames := []string{}
+	for argName := range args {
+		argNames = append(argNames, argName)
+	}
+	sort.Strings(argNames)
+	arguments := []*ast.Argument{}
+	for _, argName := range argNames {
+		arguments = append(arguments, ast.NewArgument(&ast.Argument{
+			Name:  astName(argName),
+			Value: args[argName],
+		}))
+	}
+	return ast.NewDirective(&ast.Directive{
+		Name:      astName(name),
+		Arguments: arguments,
+	})
+}
+
+func astDescription(description string) *ast.StringValue {
+	if description == "" {
+		return nil
+	}
+	return ast.NewStringValue(&ast.StringValue{Value: description})
+}
+
+func astName(name string) *ast.Name {
+	return ast.NewName(&ast.Name{Value: name})
+}
+
+func astNamed(name string) *ast.Named {
+	return ast.NewNamed(&ast.Named{Name: astName(name)})
+}
@@ -0,0 +1,394 @@
+package graphql_test
+
+import (
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/testutil"
+)
+
+func printSingleFieldSchema(t *testing.T, field *graphql.Field) string {
+	query := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"singleField": field,
+		},
+	})
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: query,
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return graphql.PrintSchema(schema)
+}
+
+func expectPrinted(t *testing.T, printed string, expected string) {
+	if printed != expected {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, printed))
+	}
+}
+
+func TestSchemaPrinter_PrintsStringField(t *testing.T) {
+	printed := printSingleFieldSchema(t, &graphql.Field{
+		Type: graphql.String,
+	})
+	expectPrinted(t, printed, `type Query {
+  singleField: String
+}
+`)
+}
+
+func TestSchemaPrinter_PrintsWrappedTypes(t *testing.T) {
+	printed := printSingleFieldSchema(t, &graphql.Field{
+		Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String))),
+	})
+	expectPrinted(t, printed, `type Query {
+  singleField: [String!]!
+}
+`)
+}
+
+func TestSchemaPrinter_PrintsArgumentsInAlphabeticalOrderWithDefaults(t *testing.T) {
+	printed := printSingleFieldSchema(t, &graphql.Field{
+		Type: graphql.String,
+		Args: graphql.FieldConfigArgument{
+			"argTwo": &graphql.ArgumentConfig{
+				Type: graphql.NewNonNull(graphql.Int),
+			},
+			"argOne": &graphql.ArgumentConfig{
+				Type:         graphql.Int,
+				DefaultValue: 2,
+			},
+			"argThree": &graphql.ArgumentConfig{
+				Type:         graphql.Boolean,
+				DefaultValue: false,
+			},
+		},
+	})
+	expectPrinted(t, printed, `type Query {
+  singleField(argOne: Int = 2, argThree: Boolean = false, argTwo: Int!): String
+}
+`)
+}
+
+func TestSchemaPrinter_PrintsObjectImplementingInterfaces(t *testing.T) {
+	fooType := graphql.NewInterface(graphql.InterfaceConfig{
+		Name: "Foo",
+		Fields: graphql.Fields{
+			"str": &graphql.Field{Type: graphql.String},
+		},
+	})
+	barType := graphql.NewInterface(graphql.InterfaceConfig{
+		Name: "Baaz",
+		Fields: graphql.Fields{
+			"int": &graphql.Field{Type: graphql.Int},
+		},
+	})
+	bazType := graphql.NewObject(graphql.ObjectConfig{
+		Name:       "Bar",
+		Interfaces: []*graphql.Interface{fooType, barType},
+		Fields: graphql.Fields{
+			"str": &graphql.Field{Type: graphql.String},
+			"int": &graphql.Field{Type: graphql.Int},
+		},
+	})
+	query := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"bar": &graphql.Field{Type: bazType},
+		},
+	})
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: query,
+		Types: []graphql.Type{bazType},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expectPrinted(t, graphql.PrintSchema(schema), `interface Baaz {
+  int: Int
+}
+
+type Bar implements Foo & Baaz {
+  int: Int
+  str: String
+}
+
+interface Foo {
+  str: String
+}
+
+type Query {
+  bar: Bar
+}
+`)
+}
+
+func TestSchemaPrinter_PrintsEnumsInputsAndUnions(t *testing.T) {
+	rgbType := graphql.NewEnum(graphql.EnumConfig{
+		Name: "RGB",
+		Values: graphql.EnumValueConfigMap{
+			"RED":   &graphql.EnumValueConfig{Value: 0},
+			"GREEN": &graphql.EnumValueConfig{Value: 1},
+			"BLUE":  &graphql.EnumValueConfig{Value: 2, DeprecationReason: "Not a warm color"},
+		},
+	})
+	inputType := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "Filter",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"color": &graphql.InputObjectFieldConfig{
+				Type:         rgbType,
+				DefaultValue: 1,
+			},
+			"tags": &graphql.InputObjectFieldConfig{
+				Type:         graphql.NewList(graphql.String),
+				DefaultValue: []interface{}{"a", "b"},
+			},
+		},
+	})
+	fooType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Foo",
+		Fields: graphql.Fields{
+			"bool": &graphql.Field{Type: graphql.Boolean},
+		},
+	})
+	barType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Bar",
+		Fields: graphql.Fields{
+			"str": &graphql.Field{Type: graphql.String},
+		},
+	})
+	unionType := graphql.NewUnion(graphql.UnionConfig{
+		Name:  "FooOrBar",
+		Types: []*graphql.Object{fooType, barType},
+		ResolveType: func(p graphql.ResolveTypeParams) *graphql.Object {
+			return nil
+		},
+	})
+	query := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"search": &graphql.Field{
+				Type: graphql.NewList(unionType),
+				Args: graphql.FieldConfigArgument{
+					"filter": &graphql.ArgumentConfig{
+						Type: inputType,
+						DefaultValue: map[string]interface{}{
+							"color": 0,
+						},
+					},
+				},
+			},
+		},
+	})
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: query,
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expectPrinted(t, graphql.PrintSchema(schema), `type Bar {
+  str: String
+}
+
+input Filter {
+  color: RGB = GREEN
+  tags: [String] = ["a", "b"]
+}
+
+type Foo {
+  bool: Boolean
+}
+
+union FooOrBar = Foo | Bar
+
+type Query {
+  search(filter: Filter = {color: RED}): [FooOrBar]
+}
+
+enum RGB {
+  BLUE @deprecated(reason: "Not a warm color")
+  GREEN
+  RED
+}
+`)
+}
+
+func TestSchemaPrinter_PrintsDescriptionsAndDeprecations(t *testing.T) {
+	dateType := graphql.NewScalar(graphql.ScalarConfig{
+		Name:           "Date",
+		Description:    "A calendar date",
+		SpecifiedByURL: "https://tools.ietf.org/html/rfc3339",
+		Serialize: func(value interface{}) interface{} {
+			return value
+		},
+	})
+	query := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Query",
+		Description: "The root query",
+		Fields: graphql.Fields{
+			"today": &graphql.Field{
+				Type:        dateType,
+				Description: "Today's date",
+			},
+			"yesterday": &graphql.Field{
+				Type:              dateType,
+				DeprecationReason: graphql.DefaultDeprecationReason,
+			},
+		},
+	})
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: query,
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expectPrinted(t, graphql.PrintSchema(schema), `"""A calendar date"""
+scalar Date @specifiedBy(url: "https://tools.ietf.org/html/rfc3339")
+
+"""The root query"""
+type Query {
+
+  """Today's date"""
+  today: Date
+  yesterday: Date @deprecated
+}
+`)
+}
+
+func TestSchemaPrinter_PrintsCustomDirectivesAndRootTypes(t *testing.T) {
+	customDirective := graphql.NewDirective(graphql.DirectiveConfig{
+		Name:        "customDirective",
+		Description: "A custom directive",
+		Locations:   []string{graphql.DirectiveLocationField, graphql.DirectiveLocationQuery},
+		Args: graphql.FieldConfigArgument{
+			"level": &graphql.ArgumentConfig{
+				Type:         graphql.Int,
+				DefaultValue: 1,
+			},
+		},
+	})
+	root := graphql.NewObject(graphql.ObjectConfig{
+		Name: "CustomQueryType",
+		Fields: graphql.Fields{
+			"bar": &graphql.Field{Type: graphql.String},
+		},
+	})
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query:      root,
+		Directives: append(graphql.SpecifiedDirectives, customDirective),
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expectPrinted(t, graphql.PrintSchema(schema), `schema {
+  query: CustomQueryType
+}
+
+"""A custom directive"""
+directive @customDirective(level: Int = 1) on FIELD | QUERY
+
+type CustomQueryType {
+  bar: String
+}
+`)
+}
+
+func TestSchemaPrinter_RoundTripsBuiltSchema(t *testing.T) {
+	sdl := `directive @auth(role: String = "admin") on FIELD_DEFINITION | OBJECT
+
+enum Color {
+  BLUE @deprecated(reason: "No longer blue")
+  GREEN @deprecated
+  RED
+}
+
+scalar Date @specifiedBy(url: "https://example.com/date")
+
+input Filter {
+  color: Color = RED
+  first: Int = 10
+}
+
+interface Node {
+  id: ID!
+}
+
+type Query {
+  node(id: ID!): Node
+  things(filter: Filter): [Thing!]!
+}
+
+type Thing implements Node {
+  date: Date
+  id: ID!
+}
+`
+	schema, err := graphql.BuildSchema(sdl)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expectPrinted(t, graphql.PrintSchema(*schema), sdl)
+}
+
+func TestSchemaPrinter_PrintsInterfaceImplementingInterfaces(t *testing.T) {
+	sdl := `interface Node {
+  id: ID!
+}
+
+type Query {
+  resource: Resource
+}
+
+interface Resource implements Node {
+  id: ID!
+  url: String
+}
+
+type Thing implements Node & Resource {
+  id: ID!
+  url: String
+}
+`
+	schema, err := graphql.BuildSchema(sdl)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expectPrinted(t, graphql.PrintSchema(*schema), sdl)
+}
+
+func TestSchemaPrinter_PrintType(t *testing.T) {
+	enumType := graphql.NewEnum(graphql.EnumConfig{
+		Name:        "Episode",
+		Description: "One of the films",
+		Values: graphql.EnumValueConfigMap{
+			"NEWHOPE": &graphql.EnumValueConfig{Value: 4},
+			"EMPIRE":  &graphql.EnumValueConfig{Value: 5},
+		},
+	})
+	expectPrinted(t, graphql.PrintType(enumType), `"""One of the films"""
+enum Episode {
+  EMPIRE
+  NEWHOPE
+}`)
+	expectPrinted(t, graphql.PrintType(graphql.NewList(enumType)), "")
+}
+
+func TestPrintSchemaDoesNotAlterSpecifiedDirectives(t *testing.T) {
+	// with spare capacity, appending to SpecifiedDirectives writes to the
+	// array shared with the other schemas
+	specifiedDirectives := graphql.SpecifiedDirectives
+	defer func() { graphql.SpecifiedDirectives = specifiedDirectives }()
+	graphql.SpecifiedDirectives = append(make([]*graphql.Directive, 0, len(specifiedDirectives)+1), specifiedDirectives...)
+	spare := graphql.SpecifiedDirectives[:len(specifiedDirectives)+1]
+
+	schema, err := graphql.BuildSchema(`type Query { a: String }`)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	graphql.PrintSchema(*schema)
+	if spare[len(specifiedDirectives)] != nil {
+		t.Fatalf("Unexpected directive appended to SpecifiedDirectives: %v", spare[len(specifiedDirectives)].Name)
+	}
+}
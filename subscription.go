@@ -5,8 +5,6 @@ import (
 	"fmt"
 
 	"github.com/graphql-go/graphql/gqlerrors"
-	"github.com/graphql-go/graphql/language/parser"
-	"github.com/graphql-go/graphql/language/source"
 )
 
 // SubscribeParams parameters for subscribing
@@ -23,36 +21,42 @@ type SubscribeParams struct {
 
 // Subscribe performs a subscribe operation on the given query and schema
 // To finish a subscription you can simply close the channel from inside the `Subscribe` function
-// currently does not support extensions hooks
 func Subscribe(p Params) chan *Result {
-
-	source := source.NewSource(&source.Source{
-		Body: []byte(p.RequestString),
-		Name: "GraphQL request",
-	})
-
-	// TODO run extensions hooks
-
-	// parse the source
-	AST, err := parser.Parse(parser.ParseParams{Source: source})
-	if err != nil {
-
-		// merge the errors from extensions and the original error from parser
-		return sendOneResultAndClose(&Result{
-			Errors: gqlerrors.FormatErrors(err),
-		})
+	if p.ErrorFormatter == nil {
+		return subscribe(p)
 	}
+	var done <-chan struct{}
+	if p.Context != nil {
+		done = p.Context.Done()
+	}
+	results := subscribe(p)
+	formattedResults := make(chan *Result)
+	go func() {
+		defer close(formattedResults)
+		for result := range results {
+			formatErrors(p.ErrorFormatter, result)
+			select {
+			case formattedResults <- result:
+			case <-done:
+				// the consumer may be gone, the results left are drained so
+				// that the subscription is not blocked sending them
+				go func() {
+					for range results {
+					}
+				}()
+				return
+			}
+		}
+	}()
+	return formattedResults
+}
 
-	// validate document
-	validationResult := ValidateDocument(&p.Schema, AST, nil)
-
-	if !validationResult.IsValid {
-		// run validation finish functions for extensions
-		return sendOneResultAndClose(&Result{
-			Errors: validationResult.Errors,
-		})
-
+func subscribe(p Params) chan *Result {
+	AST, result := prepareDocument(&p)
+	if result != nil {
+		return sendOneResultAndClose(result)
 	}
+
 	return ExecuteSubscription(ExecuteParams{
 		Schema:        p.Schema,
 		Root:          p.RootObject,
@@ -60,6 +64,7 @@ func Subscribe(p Params) chan *Result {
 		OperationName: p.OperationName,
 		Args:          p.VariableValues,
 		Context:       p.Context,
+		Concurrency:   p.Concurrency,
 	})
 }
 
@@ -71,7 +76,8 @@ func sendOneResultAndClose(res *Result) chan *Result {
 }
 
 // ExecuteSubscription is similar to graphql.Execute but returns a channel instead of a Result
-// currently does not support extensions
+// Each event is executed like a query, so the ExecutionDidStart hooks of the extensions run
+// once per event and the results of the extensions are added to every Result
 func ExecuteSubscription(p ExecuteParams) chan *Result {
 
 	if p.Context == nil {
@@ -86,22 +92,26 @@ func ExecuteSubscription(p ExecuteParams) chan *Result {
 			OperationName: p.OperationName,
 			Args:          p.Args,
 			Context:       p.Context,
+			Concurrency:   p.Concurrency,
 		})
 	}
 	var resultChannel = make(chan *Result)
+
+	// sendError sends a Result holding the error, along with the results of the extensions
+	var sendError = func(err error) {
+		result := &Result{
+			Errors: gqlerrors.FormatErrors(err),
+		}
+		addExtensionResults(p.Schema.extensions, p.Context, result)
+		resultChannel <- result
+	}
+
 	go func() {
 		defer close(resultChannel)
 		defer func() {
-			if err := recover(); err != nil {
-				e, ok := err.(error)
-				if !ok {
-					return
-				}
-				resultChannel <- &Result{
-					Errors: gqlerrors.FormatErrors(e),
-				}
+			if r := recover(); r != nil {
+				sendError(newPanicError(r))
 			}
-			return
 		}()
 
 		exeContext, err := buildExecutionContext(buildExecutionCtxParams{
@@ -114,18 +124,14 @@ func ExecuteSubscription(p ExecuteParams) chan *Result {
 		})
 
 		if err != nil {
-			resultChannel <- &Result{
-				Errors: gqlerrors.FormatErrors(err),
-			}
+			sendError(gqlerrors.FormatError(err).WithCode(gqlerrors.CodeBadUserInput))
 
 			return
 		}
 
 		operationType, err := getOperationRootType(p.Schema, exeContext.Operation)
 		if err != nil {
-			resultChannel <- &Result{
-				Errors: gqlerrors.FormatErrors(err),
-			}
+			sendError(gqlerrors.FormatError(err).WithCode(gqlerrors.CodeBadUserInput))
 
 			return
 		}
@@ -147,9 +153,7 @@ func ExecuteSubscription(p ExecuteParams) chan *Result {
 		fieldDef := getFieldDef(p.Schema, operationType, fieldName)
 
 		if fieldDef == nil {
-			resultChannel <- &Result{
-				Errors: gqlerrors.FormatErrors(fmt.Errorf("the subscription field %q is not defined", fieldName)),
-			}
+			sendError(internalError(fmt.Errorf("the subscription field %q is not defined", fieldName)))
 
 			return
 		}
@@ -157,9 +161,7 @@ func ExecuteSubscription(p ExecuteParams) chan *Result {
 		resolveFn := fieldDef.Subscribe
 
 		if resolveFn == nil {
-			resultChannel <- &Result{
-				Errors: gqlerrors.FormatErrors(fmt.Errorf("the subscription function %q is not defined", fieldName)),
-			}
+			sendError(internalError(fmt.Errorf("the subscription function %q is not defined", fieldName)))
 			return
 		}
 		fieldPath := &ResponsePath{
@@ -187,17 +189,13 @@ func ExecuteSubscription(p ExecuteParams) chan *Result {
 			Context: p.Context,
 		})
 		if err != nil {
-			resultChannel <- &Result{
-				Errors: gqlerrors.FormatErrors(err),
-			}
+			sendError(err)
 
 			return
 		}
 
 		if fieldResult == nil {
-			resultChannel <- &Result{
-				Errors: gqlerrors.FormatErrors(fmt.Errorf("no field result")),
-			}
+			sendError(internalError(fmt.Errorf("no field result")))
 
 			return
 		}
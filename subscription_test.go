@@ -285,3 +285,41 @@ var dummyQuery = graphql.NewObject(graphql.ObjectConfig{
 		"hello": &graphql.Field{Type: graphql.String},
 	},
 })
+
+func TestSubscribeUsesValidationOptions(t *testing.T) {
+	schema := makeSubscriptionSchema(t, graphql.ObjectConfig{
+		Name: "Subscription",
+		Fields: graphql.Fields{
+			"sub": &graphql.Field{
+				Type:      graphql.String,
+				Subscribe: makeSubscribeToMapFunction([]map[string]interface{}{{"sub": "a"}}),
+			},
+		},
+	})
+	query := `subscription { sub alias: sub }`
+
+	results := []*graphql.Result{}
+	for result := range graphql.Subscribe(graphql.Params{
+		Schema:          schema,
+		RequestString:   query,
+		ValidationRules: []graphql.ValidationRuleFn{graphql.MaxAliasesRule(0)},
+	}) {
+		results = append(results, result)
+	}
+	if len(results) != 1 || len(results[0].Errors) != 1 ||
+		results[0].Errors[0].Message != "Operation has 1 aliases, which exceeds the maximum of 0 aliases." {
+		t.Fatalf("Unexpected results: %v", results)
+	}
+
+	results = []*graphql.Result{}
+	for result := range graphql.Subscribe(graphql.Params{
+		Schema:         schema,
+		RequestString:  query,
+		SkipValidation: true,
+	}) {
+		results = append(results, result)
+	}
+	if len(results) != 1 || len(results[0].Errors) != 0 {
+		t.Fatalf("Unexpected results: %v", results)
+	}
+}
@@ -28,6 +28,7 @@ var IntrospectionQuery = `
     kind
     name
     description
+    specifiedByURL
     fields(includeDeprecated: true) {
       name
       description
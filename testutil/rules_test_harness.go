@@ -595,10 +595,23 @@ func ExpectPassesRule(t *testing.T, rule graphql.ValidationRuleFn, queryString s
 	expectValidRule(t, TestSchema, []graphql.ValidationRuleFn{rule}, queryString)
 }
 func ExpectFailsRule(t *testing.T, rule graphql.ValidationRuleFn, queryString string, expectedErrors []gqlerrors.FormattedError) {
-	expectInvalidRule(t, TestSchema, []graphql.ValidationRuleFn{rule}, queryString, expectedErrors)
+	expectInvalidRule(t, TestSchema, []graphql.ValidationRuleFn{rule}, queryString, ruleErrors(rule, expectedErrors))
 }
 func ExpectFailsRuleWithSchema(t *testing.T, schema *graphql.Schema, rule graphql.ValidationRuleFn, queryString string, expectedErrors []gqlerrors.FormattedError) {
-	expectInvalidRule(t, schema, []graphql.ValidationRuleFn{rule}, queryString, expectedErrors)
+	expectInvalidRule(t, schema, []graphql.ValidationRuleFn{rule}, queryString, ruleErrors(rule, expectedErrors))
+}
+
+// ruleErrors returns the expected errors, with the extensions of the errors
+// reported by rule for those which have none.
+func ruleErrors(rule graphql.ValidationRuleFn, expectedErrors []gqlerrors.FormattedError) []gqlerrors.FormattedError {
+	errs := make([]gqlerrors.FormattedError, len(expectedErrors))
+	for i, err := range expectedErrors {
+		if err.Extensions == nil {
+			err = RuleErrorWithExtensions(rule, err)
+		}
+		errs[i] = err
+	}
+	return errs
 }
 func ExpectPassesRuleWithSchema(t *testing.T, schema *graphql.Schema, rule graphql.ValidationRuleFn, queryString string) {
 	expectValidRule(t, schema, []graphql.ValidationRuleFn{rule}, queryString)
@@ -621,3 +634,13 @@ func RuleError(message string, locs ...int) gqlerrors.FormattedError {
 		Locations: locations,
 	}
 }
+
+// RuleErrorWithExtensions returns err with the extensions of the errors
+// reported by rule, as reported by graphql.Do.
+func RuleErrorWithExtensions(rule graphql.ValidationRuleFn, err gqlerrors.FormattedError) gqlerrors.FormattedError {
+	err.Extensions = map[string]interface{}{"code": gqlerrors.CodeValidationFailed}
+	if name := rule.Name(); name != "" {
+		err.Extensions["rule"] = name
+	}
+	return err
+}
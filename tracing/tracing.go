@@ -0,0 +1,203 @@
+// Package tracing provides a graphql.Extension reporting the timings of the
+// phases of each request and of its resolvers under the "tracing" key of the
+// extensions of the result, in the Apollo tracing format:
+//
+//	schema.AddExtensions(tracing.New())
+//
+// See https://github.com/apollographql/apollo-tracing for the format.
+package tracing
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+)
+
+// Version is the version of the Apollo tracing format reported by the
+// extension.
+const Version = 1
+
+// Trace is the trace of a request, reported under the "tracing" key of the
+// extensions of its result.
+type Trace struct {
+	Version    int           `json:"version"`
+	StartTime  time.Time     `json:"startTime"`
+	EndTime    time.Time     `json:"endTime"`
+	Duration   time.Duration `json:"duration"`
+	Parsing    *Phase        `json:"parsing,omitempty"`
+	Validation *Phase        `json:"validation,omitempty"`
+	Execution  Execution     `json:"execution"`
+}
+
+// Phase is the timing of a phase of a request. The offsets and durations
+// are in nanoseconds, the offsets being relative to the start of the request.
+type Phase struct {
+	StartOffset time.Duration `json:"startOffset"`
+	Duration    time.Duration `json:"duration"`
+}
+
+// Execution holds the traces of the resolvers called by the execution.
+type Execution struct {
+	Resolvers []Resolver `json:"resolvers"`
+}
+
+// Resolver is the trace of the call of the resolver of a field.
+type Resolver struct {
+	Path        []interface{} `json:"path"`
+	ParentType  string        `json:"parentType"`
+	FieldName   string        `json:"fieldName"`
+	ReturnType  string        `json:"returnType"`
+	StartOffset time.Duration `json:"startOffset"`
+	Duration    time.Duration `json:"duration"`
+}
+
+// Extension traces the requests of a schema. The traces are kept in the
+// contexts of the requests, so that a single Extension traces any number of
+// concurrent requests.
+type Extension struct{}
+
+var _ graphql.Extension = (*Extension)(nil)
+
+// New returns an Extension tracing requests.
+func New() *Extension {
+	return &Extension{}
+}
+
+type traceKey struct{}
+
+// tracer records the trace of a request. The resolvers of sibling fields
+// may be called concurrently, so the trace is guarded by mu.
+type tracer struct {
+	mu    sync.Mutex
+	trace Trace
+}
+
+func (e *Extension) tracerFrom(ctx context.Context) *tracer {
+	if ctx == nil {
+		return nil
+	}
+	t, _ := ctx.Value(traceKey{}).(*tracer)
+	return t
+}
+
+func (e *Extension) withTracer(ctx context.Context) (context.Context, *tracer) {
+	if t := e.tracerFrom(ctx); t != nil {
+		return ctx, t
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	t := &tracer{trace: Trace{
+		Version:   Version,
+		StartTime: time.Now(),
+		Execution: Execution{Resolvers: []Resolver{}},
+	}}
+	return context.WithValue(ctx, traceKey{}, t), t
+}
+
+// phase returns the timing of a phase which started at start.
+func (t *tracer) phase(start, end time.Time) *Phase {
+	return &Phase{
+		StartOffset: start.Sub(t.trace.StartTime),
+		Duration:    end.Sub(start),
+	}
+}
+
+// Init starts the trace of the request.
+func (e *Extension) Init(ctx context.Context, p *graphql.Params) context.Context {
+	ctx, _ = e.withTracer(ctx)
+	return ctx
+}
+
+// Name returns "tracing", the key of the trace in the extensions of the
+// result.
+func (e *Extension) Name() string {
+	return "tracing"
+}
+
+// ParseDidStart times the parsing of the request.
+func (e *Extension) ParseDidStart(ctx context.Context) (context.Context, graphql.ParseFinishFunc) {
+	ctx, t := e.withTracer(ctx)
+	start := time.Now()
+	return ctx, func(err error) {
+		end := time.Now()
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		t.trace.Parsing = t.phase(start, end)
+	}
+}
+
+// ValidationDidStart times the validation of the request.
+func (e *Extension) ValidationDidStart(ctx context.Context) (context.Context, graphql.ValidationFinishFunc) {
+	ctx, t := e.withTracer(ctx)
+	start := time.Now()
+	return ctx, func([]gqlerrors.FormattedError) {
+		end := time.Now()
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		t.trace.Validation = t.phase(start, end)
+	}
+}
+
+// ExecutionDidStart starts the trace of the request if it is executed
+// without being parsed by graphql.Do, and ends it with the execution.
+func (e *Extension) ExecutionDidStart(ctx context.Context) (context.Context, graphql.ExecutionFinishFunc) {
+	ctx, t := e.withTracer(ctx)
+	return ctx, func(*graphql.Result) {
+		end := time.Now()
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		t.trace.EndTime = end
+		t.trace.Duration = end.Sub(t.trace.StartTime)
+	}
+}
+
+// ResolveFieldDidStart times the call of the resolver of a field.
+func (e *Extension) ResolveFieldDidStart(ctx context.Context, i *graphql.ResolveInfo) (context.Context, graphql.ResolveFieldFinishFunc) {
+	t := e.tracerFrom(ctx)
+	if t == nil {
+		return ctx, func(interface{}, error) {}
+	}
+	start := time.Now()
+	return ctx, func(interface{}, error) {
+		end := time.Now()
+		resolver := Resolver{
+			Path:       i.Path.AsArray(),
+			ParentType: i.ParentType.Name(),
+			FieldName:  i.FieldName,
+			ReturnType: i.ReturnType.String(),
+		}
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		phase := t.phase(start, end)
+		resolver.StartOffset, resolver.Duration = phase.StartOffset, phase.Duration
+		t.trace.Execution.Resolvers = append(t.trace.Execution.Resolvers, resolver)
+	}
+}
+
+// HasResult returns true, as the trace is reported in the result.
+func (e *Extension) HasResult() bool {
+	return true
+}
+
+// GetResult returns the *Trace of the request, or nil if it was not traced.
+// The trace of a request which is not executed, such as a request with a
+// syntax error, ends when it is returned.
+func (e *Extension) GetResult(ctx context.Context) interface{} {
+	t := e.tracerFrom(ctx)
+	if t == nil {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.trace.EndTime.IsZero() {
+		t.trace.EndTime = time.Now()
+		t.trace.Duration = t.trace.EndTime.Sub(t.trace.StartTime)
+	}
+	trace := t.trace
+	trace.Execution.Resolvers = append([]Resolver{}, t.trace.Execution.Resolvers...)
+	return &trace
+}
@@ -0,0 +1,203 @@
+package tracing_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/testutil"
+	"github.com/graphql-go/graphql/tracing"
+)
+
+func newSchema(t *testing.T) graphql.Schema {
+	friendType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Friend",
+		Fields: graphql.Fields{
+			"name": &graphql.Field{Type: graphql.String},
+		},
+	})
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Query",
+			Fields: graphql.Fields{
+				"hero": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.String),
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						time.Sleep(time.Millisecond)
+						return "Luke", nil
+					},
+				},
+				"friends": &graphql.Field{
+					Type: graphql.NewList(friendType),
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return []map[string]interface{}{{"name": "Han"}, {"name": "Leia"}}, nil
+					},
+				},
+			},
+		}),
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	schema.AddExtensions(tracing.New())
+	return schema
+}
+
+// resolvers returns the traced resolvers without their timings, sorted by
+// path.
+func resolvers(trace *tracing.Trace) []tracing.Resolver {
+	var resolvers []tracing.Resolver
+	for _, r := range trace.Execution.Resolvers {
+		r.StartOffset, r.Duration = 0, 0
+		resolvers = append(resolvers, r)
+	}
+	sort.Slice(resolvers, func(i, j int) bool {
+		return fmt.Sprint(resolvers[i].Path) < fmt.Sprint(resolvers[j].Path)
+	})
+	return resolvers
+}
+
+func traceOf(t *testing.T, result *graphql.Result) *tracing.Trace {
+	if len(result.Errors) != 0 {
+		t.Fatalf("Unexpected errors: %v", result.Errors)
+	}
+	trace, ok := result.Extensions["tracing"].(*tracing.Trace)
+	if !ok {
+		t.Fatalf("Expected a trace, got %#v", result.Extensions)
+	}
+	return trace
+}
+
+func TestExtension_TracesRequests(t *testing.T) {
+	result := graphql.Do(graphql.Params{
+		Schema:        newSchema(t),
+		RequestString: `{ hero friends { name } }`,
+		Concurrency:   4,
+	})
+	trace := traceOf(t, result)
+
+	expected := []tracing.Resolver{
+		{Path: []interface{}{"friends", 0, "name"}, ParentType: "Friend", FieldName: "name", ReturnType: "String"},
+		{Path: []interface{}{"friends", 1, "name"}, ParentType: "Friend", FieldName: "name", ReturnType: "String"},
+		{Path: []interface{}{"friends"}, ParentType: "Query", FieldName: "friends", ReturnType: "[Friend]"},
+		{Path: []interface{}{"hero"}, ParentType: "Query", FieldName: "hero", ReturnType: "String!"},
+	}
+	if got := resolvers(trace); !reflect.DeepEqual(expected, got) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, got))
+	}
+
+	if trace.Version != tracing.Version || trace.EndTime.Sub(trace.StartTime) != trace.Duration {
+		t.Fatalf("Unexpected trace: %#v", trace)
+	}
+	if trace.Parsing == nil || trace.Validation == nil {
+		t.Fatalf("Expected the parsing and the validation to be traced, got %#v", trace)
+	}
+	if trace.Validation.StartOffset < trace.Parsing.StartOffset+trace.Parsing.Duration {
+		t.Fatalf("Expected the validation to start after the parsing, got %#v and %#v", trace.Parsing, trace.Validation)
+	}
+	for _, r := range trace.Execution.Resolvers {
+		if r.StartOffset < trace.Validation.StartOffset || r.StartOffset+r.Duration > trace.Duration {
+			t.Fatalf("Expected the resolver to be called during the execution, got %#v", r)
+		}
+		if r.FieldName == "hero" && r.Duration < time.Millisecond {
+			t.Fatalf("Expected the duration of the resolver to be traced, got %v", r.Duration)
+		}
+	}
+}
+
+func TestExtension_EncodesTracesInTheApolloTracingFormat(t *testing.T) {
+	result := graphql.Do(graphql.Params{
+		Schema:        newSchema(t),
+		RequestString: `{ hero }`,
+	})
+	b, err := json.Marshal(traceOf(t, result))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var trace map[string]interface{}
+	if err := json.Unmarshal(b, &trace); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	keys := func(m interface{}) []string {
+		var keys []string
+		for k := range m.(map[string]interface{}) {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		return keys
+	}
+	expected := []string{"duration", "endTime", "execution", "parsing", "startTime", "validation", "version"}
+	if got := keys(trace); !reflect.DeepEqual(expected, got) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, got))
+	}
+	expected = []string{"duration", "startOffset"}
+	if got := keys(trace["parsing"]); !reflect.DeepEqual(expected, got) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, got))
+	}
+	resolver := trace["execution"].(map[string]interface{})["resolvers"].([]interface{})[0]
+	expected = []string{"duration", "fieldName", "parentType", "path", "returnType", "startOffset"}
+	if got := keys(resolver); !reflect.DeepEqual(expected, got) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, got))
+	}
+	if _, err := time.Parse(time.RFC3339Nano, trace["startTime"].(string)); err != nil {
+		t.Fatalf("Expected an RFC 3339 start time, got %v", trace["startTime"])
+	}
+}
+
+func TestExtension_TracesConcurrentRequestsSeparately(t *testing.T) {
+	schema := newSchema(t)
+	var wg sync.WaitGroup
+	results := make([]*graphql.Result, 8)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i] = graphql.Do(graphql.Params{
+				Schema:        schema,
+				RequestString: `{ friends { name } }`,
+				Concurrency:   2,
+			})
+		}(i)
+	}
+	wg.Wait()
+	for _, result := range results {
+		if trace := traceOf(t, result); len(trace.Execution.Resolvers) != 3 {
+			t.Fatalf("Expected 3 traced resolvers, got %v", trace.Execution.Resolvers)
+		}
+	}
+}
+
+func TestExtension_TracesExecutions(t *testing.T) {
+	AST, err := parser.Parse(parser.ParseParams{Source: `{ hero }`})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	trace := traceOf(t, graphql.Execute(graphql.ExecuteParams{
+		Schema: newSchema(t),
+		AST:    AST,
+	}))
+	if trace.Parsing != nil || trace.Validation != nil || len(trace.Execution.Resolvers) != 1 {
+		t.Fatalf("Unexpected trace: %#v", trace)
+	}
+}
+
+func TestExtension_TracesInvalidRequests(t *testing.T) {
+	result := graphql.Do(graphql.Params{
+		Schema:        newSchema(t),
+		RequestString: `{ hero`,
+	})
+	trace, ok := result.Extensions["tracing"].(*tracing.Trace)
+	if !ok {
+		t.Fatalf("Expected a trace, got %#v", result.Extensions)
+	}
+	if trace.Parsing == nil || trace.Validation != nil || trace.EndTime.Sub(trace.StartTime) != trace.Duration || trace.Duration < trace.Parsing.Duration {
+		t.Fatalf("Unexpected trace: %#v", trace)
+	}
+}
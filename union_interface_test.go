@@ -163,7 +163,7 @@ func TestUnionIntersectionTypes_CanIntrospectOnUnionAndIntersectionTypes(t *test
 						"name": "name",
 					},
 				},
-				"interfaces": nil,
+				"interfaces": []interface{}{},
 				"possibleTypes": []interface{}{
 					map[string]interface{}{
 						"name": "Dog",
@@ -639,3 +639,77 @@ func TestUnionIntersectionTypes_ValueMayBeNilPointer(t *testing.T) {
 		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
 	}
 }
+
+type testResource struct {
+	ID  string `json:"id"`
+	URL string `json:"url"`
+}
+
+func TestUnionIntersectionTypes_ExecutesAndIntrospectsInterfacesImplementingInterfaces(t *testing.T) {
+	schema, err := graphql.BuildSchemaWithResolvers(`
+		interface Node {
+			id: ID!
+		}
+		interface Resource implements Node {
+			id: ID!
+			url: String
+		}
+		type Image implements Node & Resource {
+			id: ID!
+			url: String
+		}
+		type Query {
+			resource: Resource
+		}
+	`, graphql.ResolverMap{
+		Fields: map[string]graphql.FieldResolveFn{
+			"Query.resource": func(p graphql.ResolveParams) (interface{}, error) {
+				return &testResource{ID: "1", URL: "https://example.com/1.png"}, nil
+			},
+		},
+		IsTypeOf: map[string]graphql.IsTypeOfFn{
+			"Image": func(p graphql.IsTypeOfParams) bool {
+				_, ok := p.Value.(*testResource)
+				return ok
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	query := `{
+		resource {
+			__typename
+			... on Node { id }
+			url
+		}
+		__type(name: "Resource") {
+			interfaces { name }
+			possibleTypes { name }
+		}
+	}`
+	expected := &graphql.Result{
+		Data: map[string]interface{}{
+			"resource": map[string]interface{}{
+				"__typename": "Image",
+				"id":         "1",
+				"url":        "https://example.com/1.png",
+			},
+			"__type": map[string]interface{}{
+				"interfaces": []interface{}{
+					map[string]interface{}{"name": "Node"},
+				},
+				"possibleTypes": []interface{}{
+					map[string]interface{}{"name": "Image"},
+				},
+			},
+		},
+	}
+	result := g(t, graphql.Params{
+		Schema:        *schema,
+		RequestString: query,
+	})
+	if !reflect.DeepEqual(expected, result) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
+	}
+}
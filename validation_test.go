@@ -1644,3 +1644,162 @@ func TestTypeSystem_ObjectsMustAdhereToInterfaceTheyImplement_RejectsAnObjectWit
 		t.Fatalf("Expected error: %v, got %v", expectedError, err)
 	}
 }
+
+func TestTypeSystem_InterfacesMustAdhereToInterfaceTheyImplement_AcceptsAnInterfaceWhichImplementsAnInterface(t *testing.T) {
+	nodeInterface := graphql.NewInterface(graphql.InterfaceConfig{
+		Name: "Node",
+		Fields: graphql.Fields{
+			"id": &graphql.Field{
+				Type: graphql.ID,
+			},
+		},
+	})
+	resourceInterface := graphql.NewInterface(graphql.InterfaceConfig{
+		Name:       "Resource",
+		Interfaces: []*graphql.Interface{nodeInterface},
+		Fields: graphql.Fields{
+			"id": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.ID),
+			},
+			"url": &graphql.Field{
+				Type: graphql.String,
+			},
+		},
+	})
+	anotherObject := graphql.NewObject(graphql.ObjectConfig{
+		Name:       "AnotherObject",
+		Interfaces: []*graphql.Interface{nodeInterface, resourceInterface},
+		Fields: graphql.Fields{
+			"id": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.ID),
+			},
+			"url": &graphql.Field{
+				Type: graphql.String,
+			},
+		},
+	})
+	_, err := schemaWithFieldType(anotherObject)
+	if err != nil {
+		t.Fatalf(`unexpected error: %v for type "%v"`, err, anotherObject)
+	}
+}
+
+func TestTypeSystem_InterfacesMustAdhereToInterfaceTheyImplement_RejectsAnInterfaceMissingAnInterfaceField(t *testing.T) {
+	nodeInterface := graphql.NewInterface(graphql.InterfaceConfig{
+		Name: "Node",
+		Fields: graphql.Fields{
+			"id": &graphql.Field{
+				Type: graphql.ID,
+			},
+		},
+	})
+	resourceInterface := graphql.NewInterface(graphql.InterfaceConfig{
+		Name:       "Resource",
+		Interfaces: []*graphql.Interface{nodeInterface},
+		Fields: graphql.Fields{
+			"url": &graphql.Field{
+				Type: graphql.String,
+			},
+		},
+	})
+	_, err := schemaWithFieldType(resourceInterface)
+	expectedError := `"Node" expects field "id" but "Resource" does not provide it.`
+	if err == nil || err.Error() != expectedError {
+		t.Fatalf("Expected error: %v, got %v", expectedError, err)
+	}
+}
+
+func TestTypeSystem_InterfacesMustAdhereToInterfaceTheyImplement_RejectsAnObjectMissingATransitiveInterface(t *testing.T) {
+	nodeInterface := graphql.NewInterface(graphql.InterfaceConfig{
+		Name: "Node",
+		Fields: graphql.Fields{
+			"id": &graphql.Field{
+				Type: graphql.ID,
+			},
+		},
+	})
+	resourceInterface := graphql.NewInterface(graphql.InterfaceConfig{
+		Name:       "Resource",
+		Interfaces: []*graphql.Interface{nodeInterface},
+		Fields: graphql.Fields{
+			"id": &graphql.Field{
+				Type: graphql.ID,
+			},
+		},
+	})
+	anotherObject := graphql.NewObject(graphql.ObjectConfig{
+		Name:       "AnotherObject",
+		Interfaces: []*graphql.Interface{resourceInterface},
+		Fields: graphql.Fields{
+			"id": &graphql.Field{
+				Type: graphql.ID,
+			},
+		},
+	})
+	_, err := schemaWithFieldType(anotherObject)
+	expectedError := `Type AnotherObject must implement Node because it is implemented by Resource.`
+	if err == nil || err.Error() != expectedError {
+		t.Fatalf("Expected error: %v, got %v", expectedError, err)
+	}
+}
+
+func TestTypeSystem_InterfacesMustAdhereToInterfaceTheyImplement_RejectsCircularInterfaceImplementations(t *testing.T) {
+	var firstInterface, secondInterface *graphql.Interface
+	firstInterface = graphql.NewInterface(graphql.InterfaceConfig{
+		Name: "First",
+		Interfaces: graphql.InterfacesThunk(func() []*graphql.Interface {
+			return []*graphql.Interface{secondInterface}
+		}),
+		Fields: graphql.Fields{
+			"id": &graphql.Field{
+				Type: graphql.ID,
+			},
+		},
+	})
+	secondInterface = graphql.NewInterface(graphql.InterfaceConfig{
+		Name:       "Second",
+		Interfaces: []*graphql.Interface{firstInterface},
+		Fields: graphql.Fields{
+			"id": &graphql.Field{
+				Type: graphql.ID,
+			},
+		},
+	})
+	_, err := schemaWithFieldType(firstInterface)
+	expectedErrors := []string{
+		`Type First cannot implement Second because it would create a circular reference.`,
+		`Type Second cannot implement First because it would create a circular reference.`,
+	}
+	if err == nil || (err.Error() != expectedErrors[0] && err.Error() != expectedErrors[1]) {
+		t.Fatalf("Expected error: %v, got %v", expectedErrors[0], err)
+	}
+}
+
+func TestTypeSystem_InterfacesMustAdhereToInterfaceTheyImplement_AcceptsAnInterfaceWithASubtypedInterfaceField(t *testing.T) {
+	var nodeInterface, resourceInterface *graphql.Interface
+	nodeInterface = graphql.NewInterface(graphql.InterfaceConfig{
+		Name: "Node",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"parent": &graphql.Field{
+					Type: nodeInterface,
+				},
+			}
+		}),
+	})
+	resourceInterface = graphql.NewInterface(graphql.InterfaceConfig{
+		Name:       "Resource",
+		Interfaces: []*graphql.Interface{nodeInterface},
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"parent": &graphql.Field{
+					Type: resourceInterface,
+				},
+			}
+		}),
+	})
+	_, err := schemaWithFieldType(resourceInterface)
+	if err != nil {
+		t.Fatalf(`unexpected error: %v for type "%v"`, err, resourceInterface)
+	}
+}
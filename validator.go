@@ -27,23 +27,29 @@ type ValidationResult struct {
  */
 
 func ValidateDocument(schema *Schema, astDoc *ast.Document, rules []ValidationRuleFn) (vr ValidationResult) {
+	return validateDocument(schema, astDoc, rules, nil)
+}
+
+// validateDocument validates the document of a request, whose variable values
+// are made available to the rules, e.g. to ComplexityRule.
+func validateDocument(schema *Schema, astDoc *ast.Document, rules []ValidationRuleFn, variableValues map[string]interface{}) (vr ValidationResult) {
 	if len(rules) == 0 {
 		rules = SpecifiedRules
 	}
 
 	if schema == nil {
-		vr.Errors = append(vr.Errors, gqlerrors.NewFormattedError("Must provide schema"))
+		vr.Errors = append(vr.Errors, gqlerrors.NewFormattedError("Must provide schema").WithCode(gqlerrors.CodeInternalServerError))
 		return vr
 	}
 	if astDoc == nil {
-		vr.Errors = append(vr.Errors, gqlerrors.NewFormattedError("Must provide document"))
+		vr.Errors = append(vr.Errors, gqlerrors.NewFormattedError("Must provide document").WithCode(gqlerrors.CodeInternalServerError))
 		return vr
 	}
 
 	typeInfo := NewTypeInfo(&TypeInfoConfig{
 		Schema: schema,
 	})
-	vr.Errors = VisitUsingRules(schema, typeInfo, astDoc, rules)
+	vr.Errors = visitUsingRules(schema, typeInfo, astDoc, rules, variableValues)
 	if len(vr.Errors) == 0 {
 		vr.IsValid = true
 	}
@@ -57,12 +63,16 @@ func ValidateDocument(schema *Schema, astDoc *ast.Document, rules []ValidationRu
 // Had to expose it to unit test experimental customizable validation feature,
 // but not meant for public consumption
 func VisitUsingRules(schema *Schema, typeInfo *TypeInfo, astDoc *ast.Document, rules []ValidationRuleFn) []gqlerrors.FormattedError {
+	return visitUsingRules(schema, typeInfo, astDoc, rules, nil)
+}
 
+func visitUsingRules(schema *Schema, typeInfo *TypeInfo, astDoc *ast.Document, rules []ValidationRuleFn, variableValues map[string]interface{}) []gqlerrors.FormattedError {
 	context := NewValidationContext(schema, astDoc, typeInfo)
+	context.variableValues = variableValues
 	visitors := []*visitor.VisitorOptions{}
 
 	for _, rule := range rules {
-		instance := rule(context)
+		instance := rule(context.forRule(rule))
 		visitors = append(visitors, instance.VisitorOpts)
 	}
 
@@ -89,12 +99,17 @@ type ValidationContext struct {
 	schema                         *Schema
 	astDoc                         *ast.Document
 	typeInfo                       *TypeInfo
-	errors                         []gqlerrors.FormattedError
+	errors                         *[]gqlerrors.FormattedError
 	fragments                      map[string]*ast.FragmentDefinition
 	variableUsages                 map[HasSelectionSet][]*VariableUsage
 	recursiveVariableUsages        map[*ast.OperationDefinition][]*VariableUsage
 	recursivelyReferencedFragments map[*ast.OperationDefinition][]*ast.FragmentDefinition
 	fragmentSpreads                map[*ast.SelectionSet][]*ast.FragmentSpread
+	variableValues                 map[string]interface{}
+
+	// rule is the name of the rule reporting errors through the context, the
+	// errors being shared with the contexts of the other rules
+	rule string
 }
 
 func NewValidationContext(schema *Schema, astDoc *ast.Document, typeInfo *TypeInfo) *ValidationContext {
@@ -102,6 +117,7 @@ func NewValidationContext(schema *Schema, astDoc *ast.Document, typeInfo *TypeIn
 		schema:                         schema,
 		astDoc:                         astDoc,
 		typeInfo:                       typeInfo,
+		errors:                         new([]gqlerrors.FormattedError),
 		fragments:                      map[string]*ast.FragmentDefinition{},
 		variableUsages:                 map[HasSelectionSet][]*VariableUsage{},
 		recursiveVariableUsages:        map[*ast.OperationDefinition][]*VariableUsage{},
@@ -110,12 +126,26 @@ func NewValidationContext(schema *Schema, astDoc *ast.Document, typeInfo *TypeIn
 	}
 }
 
+// forRule returns a copy of the context for the given rule, sharing its
+// errors and its caches, so that the errors reported by the rule are
+// attributed to it.
+func (ctx *ValidationContext) forRule(rule ValidationRuleFn) *ValidationContext {
+	ruleCtx := *ctx
+	ruleCtx.rule = rule.Name()
+	return &ruleCtx
+}
+
+// ReportError reports a validation error, with the GRAPHQL_VALIDATION_FAILED
+// code and the name of the rule reporting it in its extensions.
 func (ctx *ValidationContext) ReportError(err error) {
-	formattedErr := gqlerrors.FormatError(err)
-	ctx.errors = append(ctx.errors, formattedErr)
+	formattedErr := gqlerrors.FormatError(err).WithCode(gqlerrors.CodeValidationFailed)
+	if ctx.rule != "" {
+		formattedErr = formattedErr.WithExtension("rule", ctx.rule)
+	}
+	*ctx.errors = append(*ctx.errors, formattedErr)
 }
 func (ctx *ValidationContext) Errors() []gqlerrors.FormattedError {
-	return ctx.errors
+	return *ctx.errors
 }
 
 func (ctx *ValidationContext) Schema() *Schema {
@@ -124,23 +154,29 @@ func (ctx *ValidationContext) Schema() *Schema {
 func (ctx *ValidationContext) Document() *ast.Document {
 	return ctx.astDoc
 }
+
+// VariableValues returns the variable values of the request whose document
+// is validated, or nil when they are not known, as in ValidateDocument.
+func (ctx *ValidationContext) VariableValues() map[string]interface{} {
+	return ctx.variableValues
+}
 func (ctx *ValidationContext) Fragment(name string) *ast.FragmentDefinition {
 	if len(ctx.fragments) == 0 {
 		if ctx.Document() == nil {
 			return nil
 		}
+		// the map is filled in place, as it is shared with the contexts of
+		// the other rules
 		defs := ctx.Document().Definitions
-		fragments := map[string]*ast.FragmentDefinition{}
 		for _, def := range defs {
 			if def, ok := def.(*ast.FragmentDefinition); ok {
 				defName := ""
 				if def.Name != nil {
 					defName = def.Name.Value
 				}
-				fragments[defName] = def
+				ctx.fragments[defName] = def
 			}
 		}
-		ctx.fragments = fragments
 	}
 	f, _ := ctx.fragments[name]
 	return f
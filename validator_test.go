@@ -73,19 +73,22 @@ func TestValidator_SupportsFullValidation_ValidatesUsingACustomTypeInfo(t *testi
 
 	expectedErrors := []gqlerrors.FormattedError{
 		{
-			Message: `Cannot query field "catOrDog" on type "QueryRoot". Did you mean "catOrDog"?`,
+			Message:    `Cannot query field "catOrDog" on type "QueryRoot". Did you mean "catOrDog"?`,
+			Extensions: map[string]interface{}{"code": gqlerrors.CodeValidationFailed, "rule": "FieldsOnCorrectTypeRule"},
 			Locations: []location.SourceLocation{
 				{Line: 3, Column: 9},
 			},
 		},
 		{
-			Message: `Cannot query field "furColor" on type "Cat". Did you mean "furColor"?`,
+			Message:    `Cannot query field "furColor" on type "Cat". Did you mean "furColor"?`,
+			Extensions: map[string]interface{}{"code": gqlerrors.CodeValidationFailed, "rule": "FieldsOnCorrectTypeRule"},
 			Locations: []location.SourceLocation{
 				{Line: 5, Column: 13},
 			},
 		},
 		{
-			Message: `Cannot query field "isHousetrained" on type "Dog". Did you mean "isHousetrained"?`,
+			Message:    `Cannot query field "isHousetrained" on type "Dog". Did you mean "isHousetrained"?`,
+			Extensions: map[string]interface{}{"code": gqlerrors.CodeValidationFailed, "rule": "FieldsOnCorrectTypeRule"},
 			Locations: []location.SourceLocation{
 				{Line: 8, Column: 13},
 			},
@@ -420,6 +420,7 @@ func TestVariables_ObjectsAndNullability_UsingVariables_ErrorsOnNullForNestedNon
 			{
 				Message: `Variable "$input" got invalid value {"a":"foo","b":"bar","c":null}.` +
 					"\nIn field \"c\": Expected \"String!\", found null.",
+				Extensions: map[string]interface{}{"code": gqlerrors.CodeBadUserInput},
 				Locations: []location.SourceLocation{
 					{
 						Line: 2, Column: 17,
@@ -450,7 +451,8 @@ func TestVariables_ObjectsAndNullability_UsingVariables_ErrorsOnIncorrectType(t
 		Data: nil,
 		Errors: []gqlerrors.FormattedError{
 			{
-				Message: "Variable \"$input\" got invalid value \"foo bar\".\nExpected \"TestInputObject\", found not an object.",
+				Message:    "Variable \"$input\" got invalid value \"foo bar\".\nExpected \"TestInputObject\", found not an object.",
+				Extensions: map[string]interface{}{"code": gqlerrors.CodeBadUserInput},
 				Locations: []location.SourceLocation{
 					{
 						Line: 2, Column: 17,
@@ -486,6 +488,7 @@ func TestVariables_ObjectsAndNullability_UsingVariables_ErrorsOnOmissionOfNested
 			{
 				Message: `Variable "$input" got invalid value {"a":"foo","b":"bar"}.` +
 					"\nIn field \"c\": Expected \"String!\", found null.",
+				Extensions: map[string]interface{}{"code": gqlerrors.CodeBadUserInput},
 				Locations: []location.SourceLocation{
 					{
 						Line: 2, Column: 17,
@@ -523,6 +526,7 @@ func TestVariables_ObjectsAndNullability_UsingVariables_ErrorsOnDeepNestedErrors
 				Message: `Variable "$input" got invalid value {"na":{"a":"foo"}}.` +
 					"\nIn field \"na\": In field \"c\": Expected \"String!\", found null." +
 					"\nIn field \"nb\": Expected \"String!\", found null.",
+				Extensions: map[string]interface{}{"code": gqlerrors.CodeBadUserInput},
 				Locations: []location.SourceLocation{
 					{
 						Line: 2, Column: 19,
@@ -565,6 +569,7 @@ func TestVariables_ObjectsAndNullability_UsingVariables_ErrorsOnAdditionOfUnknow
 			{
 				Message: `Variable "$input" got invalid value {"a":"foo","b":"bar","c":"baz","extra":"dog"}.` +
 					"\nIn field \"extra\": Unknown field.",
+				Extensions: map[string]interface{}{"code": gqlerrors.CodeBadUserInput},
 				Locations: []location.SourceLocation{
 					{
 						Line: 2, Column: 17,
@@ -771,7 +776,8 @@ func TestVariables_NonNullableScalars_DoesNotAllowNonNullableInputsToBeOmittedIn
 		Data: nil,
 		Errors: []gqlerrors.FormattedError{
 			{
-				Message: `Variable "$value" of required type "String!" was not provided.`,
+				Message:    `Variable "$value" of required type "String!" was not provided.`,
+				Extensions: map[string]interface{}{"code": gqlerrors.CodeBadUserInput},
 				Locations: []location.SourceLocation{
 					{
 						Line: 2, Column: 31,
@@ -807,7 +813,8 @@ func TestVariables_NonNullableScalars_DoesNotAllowNonNullableInputsToBeSetToNull
 		Data: nil,
 		Errors: []gqlerrors.FormattedError{
 			{
-				Message: `Variable "$value" of required type "String!" was not provided.`,
+				Message:    `Variable "$value" of required type "String!" was not provided.`,
+				Extensions: map[string]interface{}{"code": gqlerrors.CodeBadUserInput},
 				Locations: []location.SourceLocation{
 					{
 						Line: 2, Column: 31,
@@ -1032,7 +1039,8 @@ func TestVariables_ListsAndNullability_DoesNotAllowNonNullListsToBeNull(t *testi
 		Data: nil,
 		Errors: []gqlerrors.FormattedError{
 			{
-				Message: `Variable "$input" of required type "[String]!" was not provided.`,
+				Message:    `Variable "$input" of required type "[String]!" was not provided.`,
+				Extensions: map[string]interface{}{"code": gqlerrors.CodeBadUserInput},
 				Locations: []location.SourceLocation{
 					{
 						Line: 2, Column: 17,
@@ -1189,6 +1197,7 @@ func TestVariables_ListsAndNullability_DoesNotAllowListOfNonNullsToContainNull(t
 				Message: `Variable "$input" got invalid value ` +
 					`["A",null,"B"].` +
 					"\nIn element #1: Expected \"String!\", found null.",
+				Extensions: map[string]interface{}{"code": gqlerrors.CodeBadUserInput},
 				Locations: []location.SourceLocation{
 					{
 						Line: 2, Column: 17,
@@ -1223,7 +1232,8 @@ func TestVariables_ListsAndNullability_DoesNotAllowNonNullListOfNonNullsToBeNull
 		Data: nil,
 		Errors: []gqlerrors.FormattedError{
 			{
-				Message: `Variable "$input" of required type "[String!]!" was not provided.`,
+				Message:    `Variable "$input" of required type "[String!]!" was not provided.`,
+				Extensions: map[string]interface{}{"code": gqlerrors.CodeBadUserInput},
 				Locations: []location.SourceLocation{
 					{
 						Line: 2, Column: 17,
@@ -1291,6 +1301,7 @@ func TestVariables_ListsAndNullability_DoesNotAllowNonNullListOfNonNullsToContai
 				Message: `Variable "$input" got invalid value ` +
 					`["A",null,"B"].` +
 					"\nIn element #1: Expected \"String!\", found null.",
+				Extensions: map[string]interface{}{"code": gqlerrors.CodeBadUserInput},
 				Locations: []location.SourceLocation{
 					{
 						Line: 2, Column: 17,
@@ -1327,7 +1338,8 @@ func TestVariables_ListsAndNullability_DoesNotAllowInvalidTypesToBeUsedAsValues(
 		Data: nil,
 		Errors: []gqlerrors.FormattedError{
 			{
-				Message: `Variable "$input" expected value of type "TestType!" which cannot be used as an input type.`,
+				Message:    `Variable "$input" expected value of type "TestType!" which cannot be used as an input type.`,
+				Extensions: map[string]interface{}{"code": gqlerrors.CodeBadUserInput},
 				Locations: []location.SourceLocation{
 					{
 						Line: 2, Column: 17,
@@ -1365,7 +1377,8 @@ func TestVariables_ListsAndNullability_DoesNotAllowUnknownTypesToBeUsedAsValues(
 		Data: nil,
 		Errors: []gqlerrors.FormattedError{
 			{
-				Message: `Variable "$input" expected value of type "UnknownType!" which cannot be used as an input type.`,
+				Message:    `Variable "$input" expected value of type "UnknownType!" which cannot be used as an input type.`,
+				Extensions: map[string]interface{}{"code": gqlerrors.CodeBadUserInput},
 				Locations: []location.SourceLocation{
 					{
 						Line: 2, Column: 17,